@@ -0,0 +1,328 @@
+// gamelist-admin is a command line client of the GamelistAdmin gRPC service.
+//
+//	gamelist-admin [flags] <entity> <action> [args]
+//
+// Responses are printed as JSON. Games are created and updated from JSON too:
+//
+//	gamelist-admin -token $TOKEN games create '{"name": "Doom", "year_released": 1993, "platforms": ["PC"]}'
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "github.com/br3w0r/gamelist-backend/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+type command struct {
+	usage string
+	nArgs int
+	run   func(ctx context.Context, client pb.GamelistAdminClient, args []string) (proto.Message, error)
+}
+
+var commands = map[string]command{
+	"games list":    {"[cursor] [limit]", -1, list(pb.GamelistAdminClient.ListGames)},
+	"games get":     {"<id>", 1, byID(pb.GamelistAdminClient.GetGame)},
+	"games create":  {"<game json>", 1, createGame},
+	"games update":  {"<game json with id>", 1, updateGame},
+	"games delete":  {"<id> [reassign to id]", -1, deleteItem(pb.GamelistAdminClient.DeleteGame)},
+	"games restore": {"<id>", 1, byID(pb.GamelistAdminClient.RestoreGame)},
+
+	"genres list":    {"[cursor] [limit]", -1, list(pb.GamelistAdminClient.ListGenres)},
+	"genres create":  {"<name>", 1, createItem(pb.GamelistAdminClient.CreateGenre)},
+	"genres update":  {"<id> <name>", 2, updateItem(pb.GamelistAdminClient.UpdateGenre)},
+	"genres delete":  {"<id> [reassign to id]", -1, deleteItem(pb.GamelistAdminClient.DeleteGenre)},
+	"genres restore": {"<id>", 1, byID(pb.GamelistAdminClient.RestoreGenre)},
+
+	"platforms list":    {"[cursor] [limit]", -1, list(pb.GamelistAdminClient.ListPlatforms)},
+	"platforms create":  {"<name>", 1, createItem(pb.GamelistAdminClient.CreatePlatform)},
+	"platforms update":  {"<id> <name>", 2, updateItem(pb.GamelistAdminClient.UpdatePlatform)},
+	"platforms delete":  {"<id> [reassign to id]", -1, deleteItem(pb.GamelistAdminClient.DeletePlatform)},
+	"platforms restore": {"<id>", 1, byID(pb.GamelistAdminClient.RestorePlatform)},
+
+	"list-types list":    {"[cursor] [limit]", -1, list(pb.GamelistAdminClient.ListListTypes)},
+	"list-types create":  {"<name>", 1, createItem(pb.GamelistAdminClient.CreateListType)},
+	"list-types update":  {"<id> <name>", 2, updateItem(pb.GamelistAdminClient.UpdateListType)},
+	"list-types delete":  {"<id> [reassign to id]", -1, deleteItem(pb.GamelistAdminClient.DeleteListType)},
+	"list-types restore": {"<id>", 1, byID(pb.GamelistAdminClient.RestoreListType)},
+
+	"social-types list":    {"[cursor] [limit]", -1, list(pb.GamelistAdminClient.ListSocialTypes)},
+	"social-types create":  {"<name>", 1, createItem(pb.GamelistAdminClient.CreateSocialType)},
+	"social-types update":  {"<id> <name>", 2, updateItem(pb.GamelistAdminClient.UpdateSocialType)},
+	"social-types delete":  {"<id> [reassign to id]", -1, deleteItem(pb.GamelistAdminClient.DeleteSocialType)},
+	"social-types restore": {"<id>", 1, byID(pb.GamelistAdminClient.RestoreSocialType)},
+
+	"scrape-jobs list":   {"[limit]", -1, listScrapeJobs},
+	"scrape-jobs get":    {"<id>", 1, byID(pb.GamelistAdminClient.GetScrapeJob)},
+	"scrape-jobs start":  {"", 0, empty(pb.GamelistAdminClient.StartScrapeJob)},
+	"scrape-jobs cancel": {"<id>", 1, byID(pb.GamelistAdminClient.CancelScrapeJob)},
+
+	"duplicates list":    {"[status] [limit]", -1, listDuplicates},
+	"duplicates scan":    {"", 0, empty(pb.GamelistAdminClient.ScanGameDuplicates)},
+	"duplicates dismiss": {"<game id> <duplicate id>", 2, dismissDuplicate},
+	"duplicates merge":   {"<survivor id> <duplicate id>", 2, mergeGames},
+}
+
+func main() {
+	var (
+		address = flag.String("address", "localhost:9090", "address of the admin gRPC server")
+		token   = flag.String("token", os.Getenv("GAMELIST_ADMIN_TOKEN"), "admin token, $GAMELIST_ADMIN_TOKEN by default")
+		useTLS  = flag.Bool("tls", false, "connect with TLS")
+		caFile  = flag.String("ca", "", "PEM bundle to verify the server with, system roots if empty")
+		timeout = flag.Duration("timeout", 30*time.Second, "deadline of the call")
+	)
+	flag.Usage = usage
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[args[0]+" "+args[1]]
+	args = args[2:]
+	if !ok || (cmd.nArgs >= 0 && len(args) != cmd.nArgs) {
+		usage()
+		os.Exit(2)
+	}
+
+	creds, err := transportCredentials(*useTLS, *caFile)
+	if err != nil {
+		fail(err)
+	}
+
+	conn, err := grpc.Dial(*address, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		fail(err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+*token)
+
+	resp, err := cmd.run(ctx, pb.NewGamelistAdminClient(conn), args)
+	if err != nil {
+		fail(err)
+	}
+
+	out, err := protojson.MarshalOptions{Multiline: true, EmitUnpopulated: true}.Marshal(resp)
+	if err != nil {
+		fail(err)
+	}
+	fmt.Println(string(out))
+}
+
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), "Usage: gamelist-admin [flags] <entity> <action> [args]\n\nCommands:\n")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintln(flag.CommandLine.Output(), strings.TrimSpace("  "+name+" "+commands[name].usage))
+	}
+
+	fmt.Fprintf(flag.CommandLine.Output(), "\nFlags:\n")
+	flag.PrintDefaults()
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "gamelist-admin:", err)
+	os.Exit(1)
+}
+
+func transportCredentials(useTLS bool, caFile string) (credentials.TransportCredentials, error) {
+	if !useTLS {
+		return insecure.NewCredentials(), nil
+	}
+
+	conf := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caFile != "" {
+		ca, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+
+		conf.RootCAs = x509.NewCertPool()
+		if !conf.RootCAs.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no certificates found in %s", caFile)
+		}
+	}
+
+	return credentials.NewTLS(conf), nil
+}
+
+func parseID(arg string) (uint64, error) {
+	id, err := strconv.ParseUint(arg, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("wrong id \"%s\"", arg)
+	}
+
+	return id, nil
+}
+
+// optionalArg parses the i-th argument as a number if it's given
+func optionalArg(args []string, i int) (uint64, error) {
+	if i >= len(args) {
+		return 0, nil
+	}
+
+	return strconv.ParseUint(args[i], 10, 64)
+}
+
+func byID[T proto.Message](call func(pb.GamelistAdminClient, context.Context, *pb.IdRequest, ...grpc.CallOption) (T, error)) func(context.Context, pb.GamelistAdminClient, []string) (proto.Message, error) {
+	return func(ctx context.Context, client pb.GamelistAdminClient, args []string) (proto.Message, error) {
+		id, err := parseID(args[0])
+		if err != nil {
+			return nil, err
+		}
+
+		return call(client, ctx, &pb.IdRequest{Id: id})
+	}
+}
+
+func empty[T proto.Message](call func(pb.GamelistAdminClient, context.Context, *pb.Empty, ...grpc.CallOption) (T, error)) func(context.Context, pb.GamelistAdminClient, []string) (proto.Message, error) {
+	return func(ctx context.Context, client pb.GamelistAdminClient, args []string) (proto.Message, error) {
+		return call(client, ctx, &pb.Empty{})
+	}
+}
+
+func deleteItem(call func(pb.GamelistAdminClient, context.Context, *pb.DeleteRequest, ...grpc.CallOption) (*pb.Empty, error)) func(context.Context, pb.GamelistAdminClient, []string) (proto.Message, error) {
+	return func(ctx context.Context, client pb.GamelistAdminClient, args []string) (proto.Message, error) {
+		if len(args) == 0 || len(args) > 2 {
+			return nil, fmt.Errorf("expected <id> [reassign to id]")
+		}
+		id, err := parseID(args[0])
+		if err != nil {
+			return nil, err
+		}
+		reassignTo, err := optionalArg(args, 1)
+		if err != nil {
+			return nil, fmt.Errorf("wrong reassign to id: %w", err)
+		}
+
+		return call(client, ctx, &pb.DeleteRequest{Id: id, ReassignTo: reassignTo})
+	}
+}
+
+func createItem(call func(pb.GamelistAdminClient, context.Context, *pb.CatalogItem, ...grpc.CallOption) (*pb.CatalogItem, error)) func(context.Context, pb.GamelistAdminClient, []string) (proto.Message, error) {
+	return func(ctx context.Context, client pb.GamelistAdminClient, args []string) (proto.Message, error) {
+		return call(client, ctx, &pb.CatalogItem{Name: args[0]})
+	}
+}
+
+func updateItem(call func(pb.GamelistAdminClient, context.Context, *pb.CatalogItem, ...grpc.CallOption) (*pb.CatalogItem, error)) func(context.Context, pb.GamelistAdminClient, []string) (proto.Message, error) {
+	return func(ctx context.Context, client pb.GamelistAdminClient, args []string) (proto.Message, error) {
+		id, err := parseID(args[0])
+		if err != nil {
+			return nil, err
+		}
+
+		return call(client, ctx, &pb.CatalogItem{Id: id, Name: args[1]})
+	}
+}
+
+func list[T proto.Message](call func(pb.GamelistAdminClient, context.Context, *pb.ListRequest, ...grpc.CallOption) (T, error)) func(context.Context, pb.GamelistAdminClient, []string) (proto.Message, error) {
+	return func(ctx context.Context, client pb.GamelistAdminClient, args []string) (proto.Message, error) {
+		if len(args) > 2 {
+			return nil, fmt.Errorf("expected [cursor] [limit]")
+		}
+		var cursor string
+		if len(args) > 0 {
+			cursor = args[0]
+		}
+		limit, err := optionalArg(args, 1)
+		if err != nil {
+			return nil, fmt.Errorf("wrong limit: %w", err)
+		}
+
+		return call(client, ctx, &pb.ListRequest{Cursor: cursor, Limit: uint32(limit)})
+	}
+}
+
+func createGame(ctx context.Context, client pb.GamelistAdminClient, args []string) (proto.Message, error) {
+	var game pb.Game
+	if err := protojson.Unmarshal([]byte(args[0]), &game); err != nil {
+		return nil, fmt.Errorf("wrong game json: %w", err)
+	}
+
+	return client.CreateGame(ctx, &game)
+}
+
+func updateGame(ctx context.Context, client pb.GamelistAdminClient, args []string) (proto.Message, error) {
+	var game pb.Game
+	if err := protojson.Unmarshal([]byte(args[0]), &game); err != nil {
+		return nil, fmt.Errorf("wrong game json: %w", err)
+	}
+	if game.Id == 0 {
+		return nil, fmt.Errorf("game json has no id")
+	}
+
+	return client.UpdateGame(ctx, &game)
+}
+
+func listScrapeJobs(ctx context.Context, client pb.GamelistAdminClient, args []string) (proto.Message, error) {
+	limit, err := optionalArg(args, 0)
+	if err != nil {
+		return nil, fmt.Errorf("wrong limit: %w", err)
+	}
+
+	return client.ListScrapeJobs(ctx, &pb.ListRequest{Limit: uint32(limit)})
+}
+
+func listDuplicates(ctx context.Context, client pb.GamelistAdminClient, args []string) (proto.Message, error) {
+	var status string
+	if len(args) > 0 {
+		status = strings.ToLower(args[0])
+	}
+	limit, err := optionalArg(args, 1)
+	if err != nil {
+		return nil, fmt.Errorf("wrong limit: %w", err)
+	}
+
+	return client.ListGameDuplicates(ctx, &pb.ListDuplicatesRequest{Status: status, Limit: uint32(limit)})
+}
+
+func dismissDuplicate(ctx context.Context, client pb.GamelistAdminClient, args []string) (proto.Message, error) {
+	gameID, err := parseID(args[0])
+	if err != nil {
+		return nil, err
+	}
+	duplicateID, err := parseID(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	return client.DismissGameDuplicate(ctx, &pb.GameDuplicateRequest{GameId: gameID, DuplicateId: duplicateID})
+}
+
+func mergeGames(ctx context.Context, client pb.GamelistAdminClient, args []string) (proto.Message, error) {
+	survivorID, err := parseID(args[0])
+	if err != nil {
+		return nil, err
+	}
+	duplicateID, err := parseID(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	return client.MergeGames(ctx, &pb.MergeGamesRequest{SurvivorId: survivorID, DuplicateId: duplicateID})
+}
@@ -0,0 +1,180 @@
+// Package config loads the typed configuration of the server. Values come from defaults,
+// a YAML or TOML file, environment variables and flags, later ones override earlier ones.
+package config
+
+import (
+	"time"
+)
+
+const (
+	// CONFIG_FILE_ENV is the environment variable with the path of the config file, the
+	// --config flag overrides it
+	CONFIG_FILE_ENV = "CONFIG_FILE"
+	// FILE_SUFFIX of environment variables which have paths of files with secrets, e.g.
+	// DB_PASSWORD_FILE
+	FILE_SUFFIX = "_FILE"
+	// REDACTED replaces secrets in printed configs
+	REDACTED = "[redacted]"
+)
+
+// Fields are named by their name tags in files and flags, e.g. database.password and
+// --database.password, and by their env tags in the environment. Secret fields may be
+// read from files of their environment variables with FILE_SUFFIX.
+type Config struct {
+	Server     Server     `name:"server"`
+	Database   Database   `name:"database"`
+	Scraper    Scraper    `name:"scraper"`
+	Games      Games      `name:"games"`
+	API        API        `name:"api"`
+	Accounts   Accounts   `name:"accounts"`
+	Admin      Admin      `name:"admin"`
+	Metrics    Metrics    `name:"metrics"`
+	Tracing    Tracing    `name:"tracing"`
+	Log        Log        `name:"log"`
+	StressTest StressTest `name:"stress_test"`
+
+	// File is the YAML or TOML file the config was loaded from, empty if there's none
+	File string
+	// PrintConfig asks to print the config instead of running the server
+	PrintConfig bool
+}
+
+type Server struct {
+	Port        int    `name:"port" env:"PORT" usage:"port of the HTTP server"`
+	Production  bool   `name:"production" env:"PRODUCTION_MODE" usage:"run gin in release mode"`
+	ServeStatic bool   `name:"serve_static" env:"SERVE_STATIC" usage:"serve the frontend from static_dir"`
+	StaticDir   string `name:"static_dir" env:"STATIC_FOLDER" usage:"directory of the built frontend"`
+
+	ReadTimeout     time.Duration `name:"read_timeout" env:"HTTP_READ_TIMEOUT" usage:"limit on reading a request"`
+	WriteTimeout    time.Duration `name:"write_timeout" env:"HTTP_WRITE_TIMEOUT" usage:"limit on writing a response, event streams aren't limited"`
+	IdleTimeout     time.Duration `name:"idle_timeout" env:"HTTP_IDLE_TIMEOUT" usage:"how long idle keep-alive connections are kept"`
+	ShutdownTimeout time.Duration `name:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT" usage:"how long requests and jobs are waited for on stop"`
+	DrainDelay      time.Duration `name:"drain_delay" env:"DRAIN_DELAY" usage:"how long the server is unready before it stops"`
+
+	ReadyCheckScraper bool `name:"ready_check_scraper" env:"READY_CHECK_SCRAPER" usage:"make readiness depend on reaching the scraper"`
+}
+
+type Database struct {
+	Host     string `name:"host" env:"DB_HOST" usage:"host of Postgres"`
+	Port     int    `name:"port" env:"DB_PORT" usage:"port of Postgres"`
+	User     string `name:"user" env:"DB_USER" usage:"user of Postgres"`
+	Name     string `name:"name" env:"DB_NAME" usage:"name of the database"`
+	Password string `name:"password" env:"DB_PASSWORD" secret:"true" usage:"password of the user, required in production mode"`
+	SSL      bool   `name:"ssl" env:"DB_SSL" usage:"require SSL"`
+	TimeZone string `name:"timezone" env:"DB_TIMEZONE" usage:"time zone of the connection"`
+
+	QueryTimeout time.Duration `name:"query_timeout" env:"DB_QUERY_TIMEOUT" usage:"limit on the queries of each request to the database, 0 for none"`
+}
+
+type Scraper struct {
+	Address     string        `name:"address" env:"SCRAPER_GRPC_ADDRESS" usage:"address of the scraper including port"`
+	TLS         bool          `name:"tls" env:"SCRAPER_TLS" usage:"connect to the scraper over TLS"`
+	CAFile      string        `name:"ca_file" env:"SCRAPER_CA_FILE" usage:"PEM bundle to verify the scraper with instead of system roots"`
+	CertFile    string        `name:"cert_file" env:"SCRAPER_CERT_FILE" usage:"client certificate for mTLS"`
+	KeyFile     string        `name:"key_file" env:"SCRAPER_KEY_FILE" usage:"key of the client certificate"`
+	ServerName  string        `name:"server_name" env:"SCRAPER_SERVER_NAME" usage:"server name to verify instead of the address"`
+	CallTimeout time.Duration `name:"call_timeout" env:"SCRAPER_CALL_TIMEOUT" usage:"deadline of a single scrape call, 0 for none"`
+	MaxRetries  int           `name:"max_retries" env:"SCRAPER_MAX_RETRIES" usage:"reconnects in a row without receiving a game"`
+}
+
+type Games struct {
+	Sources        []string `name:"sources" env:"GAME_SOURCES" usage:"<name>=<kind>:<location> entries of game sources"`
+	SourcePriority string   `name:"source_priority" env:"GAME_SOURCE_PRIORITY" usage:"which sources win per field, e.g. *=scraper,catalog"`
+	ForceScrape    bool     `name:"force_scrape" env:"FORCE_SCRAPE" usage:"scrape games on boot"`
+	ScrapeSchedule string   `name:"scrape_schedule" env:"SCRAPE_SCHEDULE" usage:"cron spec of scrape jobs"`
+
+	HTTPTimeout time.Duration `name:"http_timeout" env:"GAME_SOURCE_HTTP_TIMEOUT" usage:"limit on fetching and reading a feed of an HTTP source"`
+}
+
+type API struct {
+	PageSizeLimits       string    `name:"page_size_limits" env:"PAGE_SIZE_LIMITS" usage:"max page sizes of games, my-games, catalog, profiles and * for the rest, e.g. *=100;games=50"`
+	GraphQLMaxComplexity int       `name:"graphql_max_complexity" env:"GRAPHQL_MAX_COMPLEXITY" usage:"limit on costs of GraphQL queries"`
+	EventBackend         string    `name:"event_backend" env:"EVENT_BACKEND" usage:"local or postgres"`
+	V0Sunset             time.Time `name:"v0_sunset" env:"API_V0_SUNSET" usage:"date v0 of the API is removed at"`
+}
+
+type Accounts struct {
+	DeletionGrace time.Duration `name:"deletion_grace" env:"DELETION_GRACE" usage:"how long deleted accounts may be restored"`
+	PurgeSchedule string        `name:"purge_schedule" env:"PURGE_SCHEDULE" usage:"cron spec of purges of deleted accounts"`
+}
+
+type Admin struct {
+	GRPCAddress string   `name:"grpc_address" env:"ADMIN_GRPC_ADDRESS" usage:"address of the admin gRPC server"`
+	Tokens      []string `name:"tokens" env:"ADMIN_TOKENS" secret:"true" usage:"tokens of the admin API, it's disabled without them"`
+}
+
+type Metrics struct {
+	Address string `name:"address" env:"METRICS_ADDRESS" usage:"address of the metrics server, empty to disable it"`
+	Token   string `name:"token" env:"METRICS_TOKEN" secret:"true" usage:"bearer token of scrapes of metrics"`
+}
+
+type Tracing struct {
+	Exporter    string  `name:"exporter" env:"TRACING_EXPORTER" usage:"empty, stdout or otlp"`
+	SampleRatio float64 `name:"sample_ratio" env:"TRACING_SAMPLE_RATIO" usage:"ratio of sampled traces started by the server"`
+}
+
+type Log struct {
+	Format string `name:"format" env:"LOG_FORMAT" usage:"text or json"`
+	Level  string `name:"level" env:"LOG_LEVEL" usage:"debug, info, warn or error"`
+	Levels string `name:"levels" env:"LOG_LEVELS" usage:"levels of subsystems, e.g. gorm=warn,http=info"`
+}
+
+type StressTest struct {
+	Enabled bool     `name:"enabled" env:"STRESS_TEST" usage:"run the stress test instead of serving"`
+	Options []string `name:"options" env:"STRESS_TEST_OPTIONS" usage:"steps of the stress test"`
+}
+
+// Default is the config without a file, environment variables and flags
+func Default() Config {
+	return Config{
+		Server: Server{
+			Port:            8080,
+			ServeStatic:     true,
+			StaticDir:       "../gamelist-frontend/gamelist/dist",
+			ReadTimeout:     15 * time.Second,
+			WriteTimeout:    60 * time.Second,
+			IdleTimeout:     120 * time.Second,
+			ShutdownTimeout: 30 * time.Second,
+			DrainDelay:      5 * time.Second,
+		},
+		Database: Database{
+			Host:         "localhost",
+			Port:         5432,
+			User:         "postgres",
+			Name:         "gamelist",
+			TimeZone:     "UTC",
+			QueryTimeout: 10 * time.Second,
+		},
+		Scraper: Scraper{
+			Address:     "localhost:8888",
+			CallTimeout: 30 * time.Minute,
+			MaxRetries:  5,
+		},
+		Games: Games{
+			Sources:     []string{"scraper"},
+			HTTPTimeout: 30 * time.Minute,
+		},
+		API: API{
+			GraphQLMaxComplexity: 5000,
+			EventBackend:         "local",
+			V0Sunset:             time.Date(2027, time.April, 19, 0, 0, 0, 0, time.UTC),
+		},
+		Accounts: Accounts{
+			DeletionGrace: 720 * time.Hour,
+			PurgeSchedule: "@hourly",
+		},
+		Admin: Admin{
+			GRPCAddress: ":9090",
+		},
+		Tracing: Tracing{
+			SampleRatio: 1,
+		},
+		Log: Log{
+			Format: "text",
+			Level:  "info",
+		},
+		StressTest: StressTest{
+			Options: []string{"user_creation", "get_game=75", "get_all_games", "get_user_games"},
+		},
+	}
+}
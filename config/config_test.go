@@ -0,0 +1,245 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func envOf(vars map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := vars[key]
+		return value, ok
+	}
+}
+
+func writeFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	convey.Convey("Defaults should be valid and have no password", t, func() {
+		conf, err := Load(nil, envOf(nil), io.Discard)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(conf.Validate(), convey.ShouldBeNil)
+		convey.So(conf.Database.Password, convey.ShouldBeEmpty)
+		convey.So(conf.Server.Port, convey.ShouldEqual, 8080)
+
+		options, err := conf.ServerOptions()
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(options.Address, convey.ShouldEqual, ":8080")
+		convey.So(options.DBConfig.Port, convey.ShouldEqual, "5432")
+		convey.So(options.GameSources, convey.ShouldHaveLength, 1)
+		convey.So(options.GameSources[0].Timeout, convey.ShouldEqual, 30*time.Minute)
+	})
+
+	convey.Convey("Flags should override env, which overrides the file", t, func() {
+		file := writeFile(t, "config.yaml", `
+server:
+  port: 9000
+  production: true
+  drain_delay: 1s
+database:
+  host: db
+  port: 6543
+games:
+  sources: [scraper, "catalog=file:/data/games.csv"]
+api:
+  v0_sunset: 2028-01-01
+`)
+		conf, err := Load(
+			[]string{"--server.port", "9002", "--server.production=false", "--log.level=debug"},
+			envOf(map[string]string{
+				CONFIG_FILE_ENV: file,
+				"PORT":          "9001",
+				"DB_PORT":       "7654",
+				"SCRAPER_TLS":   "1",
+			}),
+			io.Discard,
+		)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(conf.File, convey.ShouldEqual, file)
+		convey.So(conf.Server.Port, convey.ShouldEqual, 9002)
+		convey.So(conf.Server.Production, convey.ShouldBeFalse)
+		convey.So(conf.Server.DrainDelay, convey.ShouldEqual, time.Second)
+		convey.So(conf.Database.Host, convey.ShouldEqual, "db")
+		convey.So(conf.Database.Port, convey.ShouldEqual, 7654)
+		convey.So(conf.Scraper.TLS, convey.ShouldBeTrue)
+		convey.So(conf.Games.Sources, convey.ShouldResemble, []string{"scraper", "catalog=file:/data/games.csv"})
+		convey.So(conf.API.V0Sunset, convey.ShouldEqual, time.Date(2028, time.January, 1, 0, 0, 0, 0, time.UTC))
+		convey.So(conf.Log.Level, convey.ShouldEqual, "debug")
+	})
+
+	convey.Convey("TOML files should be loaded too", t, func() {
+		file := writeFile(t, "config.toml", `
+[database]
+name = "games"
+ssl = true
+
+[admin]
+tokens = ["first", "second"]
+`)
+		conf, err := Load([]string{"--config", file}, envOf(nil), io.Discard)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(conf.Database.Name, convey.ShouldEqual, "games")
+		convey.So(conf.Database.SSL, convey.ShouldBeTrue)
+		convey.So(conf.Admin.Tokens, convey.ShouldResemble, []string{"first", "second"})
+	})
+
+	convey.Convey("Secrets should be read from files of their variables", t, func() {
+		conf, err := Load(nil, envOf(map[string]string{
+			"DB_PASSWORD":      "ignored",
+			"DB_PASSWORD_FILE": writeFile(t, "password", "s3cret\n"),
+			"ADMIN_TOKENS_FILE": writeFile(t, "tokens", `first
+second
+`),
+		}), io.Discard)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(conf.Database.Password, convey.ShouldEqual, "s3cret")
+		convey.So(conf.Admin.Tokens, convey.ShouldResemble, []string{"first", "second"})
+
+		convey.Convey("But not values which aren't secrets", func() {
+			conf, err := Load(nil, envOf(map[string]string{"DB_USER_FILE": "/nonexistent"}), io.Discard)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(conf.Database.User, convey.ShouldEqual, "postgres")
+		})
+	})
+
+	convey.Convey("Wrong values should be reported with their names", t, func() {
+		_, err := Load(nil, envOf(map[string]string{
+			"DB_PORT":            "five",
+			"DRAIN_DELAY":        "5",
+			"METRICS_TOKEN_FILE": "/nonexistent",
+		}), io.Discard)
+		convey.So(err, convey.ShouldNotBeNil)
+		convey.So(err.Error(), convey.ShouldContainSubstring, `DB_PORT: "five" must be an integer`)
+		convey.So(err.Error(), convey.ShouldContainSubstring, `DRAIN_DELAY: "5" must be a duration`)
+		convey.So(err.Error(), convey.ShouldContainSubstring, "METRICS_TOKEN_FILE: failed to read secret")
+
+		_, err = Load([]string{"--server.port=x"}, envOf(nil), io.Discard)
+		convey.So(err.Error(), convey.ShouldContainSubstring, "server.port")
+
+		file := writeFile(t, "config.yml", `
+database:
+  hots: db
+logs:
+  level: debug
+`)
+		_, err = Load([]string{"--config", file}, envOf(nil), io.Discard)
+		convey.So(err.Error(), convey.ShouldContainSubstring, "database.hots in "+file+": unknown option")
+		convey.So(err.Error(), convey.ShouldContainSubstring, "logs in "+file+": unknown section")
+
+		_, err = Load([]string{"--config", writeFile(t, "config.json", "{}")}, envOf(nil), io.Discard)
+		convey.So(err.Error(), convey.ShouldContainSubstring, "unknown format")
+	})
+
+	convey.Convey("Help should be asked for with -h", t, func() {
+		var help bytes.Buffer
+		_, err := Load([]string{"-h"}, envOf(nil), &help)
+		convey.So(errors.Is(err, flag.ErrHelp), convey.ShouldBeTrue)
+		convey.So(help.String(), convey.ShouldContainSubstring, "-database.password")
+		convey.So(help.String(), convey.ShouldContainSubstring, "$DB_PASSWORD")
+	})
+}
+
+func TestValidate(t *testing.T) {
+	convey.Convey("All problems of the config should be reported at once", t, func() {
+		conf := Default()
+		conf.Server.Production = true
+		conf.Server.Port = 0
+		conf.Scraper.CertFile = "client.pem"
+		conf.Games.Sources = []string{"catalog=file"}
+		conf.Games.ScrapeSchedule = "every day"
+		conf.API.EventBackend = "redis"
+		conf.Tracing.SampleRatio = 2
+		conf.Log.Levels = "gorm"
+
+		err := conf.Validate()
+		convey.So(err, convey.ShouldNotBeNil)
+		lines := strings.Split(err.Error(), "\n")
+		convey.So(lines, convey.ShouldHaveLength, 8)
+		for _, prefix := range []string{
+			"server.port:", "database.password: is required in production mode", "scraper.cert_file:",
+			"games.sources:", "games.scrape_schedule:", "api.event_backend:", "tracing.sample_ratio:", "log.levels:",
+		} {
+			found := false
+			for _, line := range lines {
+				found = found || strings.HasPrefix(line, prefix)
+			}
+			convey.So(found, convey.ShouldBeTrue)
+		}
+	})
+
+	convey.Convey("Examples in usages of options should be valid", t, func() {
+		conf := Default()
+		for _, f := range conf.fields() {
+			if _, example, ok := strings.Cut(f.usage, "e.g. "); ok {
+				convey.So(f.set(example), convey.ShouldBeNil)
+			}
+		}
+		convey.So(conf.API.PageSizeLimits, convey.ShouldEqual, "*=100;games=50")
+		convey.So(conf.Validate(), convey.ShouldBeNil)
+	})
+}
+
+func TestPrint(t *testing.T) {
+	convey.Convey("Printed configs should redact secrets and load back", t, func() {
+		conf := Default()
+		conf.Database.Password = "s3cret"
+		conf.Admin.Tokens = []string{"admin-token"}
+		conf.Server.DrainDelay = 2 * time.Second
+
+		var printed bytes.Buffer
+		convey.So(conf.Print(&printed), convey.ShouldBeNil)
+		convey.So(printed.String(), convey.ShouldNotContainSubstring, "s3cret")
+		convey.So(printed.String(), convey.ShouldNotContainSubstring, "admin-token")
+		convey.So(printed.String(), convey.ShouldContainSubstring, "password: '"+REDACTED+"'")
+		convey.So(printed.String(), convey.ShouldContainSubstring, "token: \"\"")
+
+		file := writeFile(t, "printed.yaml", printed.String())
+		_, err := Load([]string{"--config", file}, envOf(nil), io.Discard)
+		convey.So(err, convey.ShouldNotBeNil)
+		convey.So(err.Error(), convey.ShouldContainSubstring, "database.password in "+file)
+		convey.So(err.Error(), convey.ShouldContainSubstring, "admin.tokens in "+file)
+		convey.So(err.Error(), convey.ShouldNotContainSubstring, "metrics.token")
+
+		conf.Database.Password = ""
+		conf.Admin.Tokens = nil
+		printed.Reset()
+		convey.So(conf.Print(&printed), convey.ShouldBeNil)
+		file = writeFile(t, "printed.yaml", printed.String())
+		loaded, err := Load([]string{"--config", file}, envOf(nil), io.Discard)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(loaded.Server, convey.ShouldResemble, conf.Server)
+		convey.So(loaded.API, convey.ShouldResemble, conf.API)
+		convey.So(loaded.StressTest, convey.ShouldResemble, conf.StressTest)
+		convey.So(loaded.Database, convey.ShouldResemble, conf.Database)
+		convey.So(loaded.Admin.Tokens, convey.ShouldBeEmpty)
+	})
+
+	convey.Convey("Redacted secrets shouldn't be loaded from the environment or flags", t, func() {
+		_, err := Load(nil, envOf(map[string]string{"ADMIN_TOKENS": "token," + REDACTED}), io.Discard)
+		convey.So(err, convey.ShouldNotBeNil)
+		convey.So(err.Error(), convey.ShouldContainSubstring, "ADMIN_TOKENS")
+
+		_, err = Load([]string{"--database.password", REDACTED}, envOf(nil), io.Discard)
+		convey.So(err, convey.ShouldNotBeNil)
+
+		loaded, err := Load([]string{"--scraper.address", REDACTED}, envOf(nil), io.Discard)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(loaded.Scraper.Address, convey.ShouldEqual, REDACTED)
+	})
+}
@@ -0,0 +1,334 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+const DATE_LAYOUT = "2006-01-02"
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// field is a value of the config
+type field struct {
+	// path is the name in files and flags, e.g. database.password
+	path   string
+	env    string
+	secret bool
+	usage  string
+	value  reflect.Value
+}
+
+// fields of the config in the order of declaration
+func (c *Config) fields() []field {
+	var fields []field
+
+	sections := reflect.ValueOf(c).Elem()
+	for i := 0; i < sections.NumField(); i++ {
+		sectionName, ok := sections.Type().Field(i).Tag.Lookup("name")
+		if !ok {
+			continue
+		}
+
+		section := sections.Field(i)
+		for j := 0; j < section.NumField(); j++ {
+			tag := section.Type().Field(j).Tag
+			fields = append(fields, field{
+				path:   sectionName + "." + tag.Get("name"),
+				env:    tag.Get("env"),
+				secret: tag.Get("secret") == "true",
+				usage:  tag.Get("usage"),
+				value:  section.Field(j),
+			})
+		}
+	}
+
+	return fields
+}
+
+// Load loads the config from the file of the --config flag or CONFIG_FILE, environment
+// variables of lookupEnv and flags of args, e.g. os.Args[1:]. Help of flags is written
+// to output and flag.ErrHelp is returned if it's asked for.
+func Load(args []string, lookupEnv func(string) (string, bool), output io.Writer) (*Config, error) {
+	conf := Default()
+	fields := conf.fields()
+
+	// Flags are applied last, but the file is named by a flag
+	type flagValue struct {
+		field *field
+		value string
+	}
+	var flagValues []flagValue
+
+	flags := flag.NewFlagSet(filepath.Base(os.Args[0]), flag.ContinueOnError)
+	flags.SetOutput(output)
+	flags.StringVar(&conf.File, "config", "", "YAML or TOML config file, $"+CONFIG_FILE_ENV+" by default")
+	flags.BoolVar(&conf.PrintConfig, "print-config", false, "print the config with secrets redacted and exit")
+	for i := range fields {
+		f := &fields[i]
+		flags.Var(&flagSetter{
+			isBool: f.value.Kind() == reflect.Bool,
+			set: func(value string) error {
+				if err := f.set(value); err != nil {
+					return err
+				}
+				flagValues = append(flagValues, flagValue{f, value})
+				return nil
+			},
+		}, f.path, fmt.Sprintf("%s, $%s", f.usage, f.env))
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if flags.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(flags.Args(), " "))
+	}
+
+	if conf.File == "" {
+		conf.File, _ = lookupEnv(CONFIG_FILE_ENV)
+	}
+	if conf.File != "" {
+		if err := conf.loadFile(fields, conf.File); err != nil {
+			return nil, err
+		}
+	}
+
+	var errs []error
+	for i := range fields {
+		if err := fields[i].loadEnv(lookupEnv); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	for _, flagValue := range flagValues {
+		// Values were checked while parsing
+		flagValue.field.set(flagValue.value) //nolint:errcheck
+	}
+
+	return &conf, nil
+}
+
+func (c *Config) loadFile(fields []field, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	values := map[string]interface{}{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &values)
+	case ".toml":
+		err = toml.Unmarshal(data, &values)
+	default:
+		return fmt.Errorf("config file %s: unknown format %q, use .yaml, .yml or .toml", path, ext)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	byPath := make(map[string]*field, len(fields))
+	sections := map[string]bool{}
+	for i := range fields {
+		byPath[fields[i].path] = &fields[i]
+		sectionName, _, _ := strings.Cut(fields[i].path, ".")
+		sections[sectionName] = true
+	}
+
+	var errs []error
+	for _, key := range sortedKeys(values) {
+		section, ok := values[key].(map[string]interface{})
+		if !ok || !sections[key] {
+			errs = append(errs, fmt.Errorf("%s in %s: unknown section", key, path))
+			continue
+		}
+
+		for _, name := range sortedKeys(section) {
+			f, ok := byPath[key+"."+name]
+			if !ok {
+				errs = append(errs, fmt.Errorf("%s.%s in %s: unknown option", key, name, path))
+				continue
+			}
+
+			value, err := fileValue(section[name])
+			if err == nil {
+				err = f.set(value)
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s in %s: %w", f.path, path, err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// fileValue is the value of a file as it would be in an environment variable
+func fileValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case time.Time:
+		return v.Format(DATE_LAYOUT), nil
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = fmt.Sprint(item)
+		}
+		return strings.Join(items, ","), nil
+	case map[string]interface{}:
+		return "", errors.New("must be a value, not a section")
+	}
+
+	return fmt.Sprint(value), nil
+}
+
+// loadEnv sets the field from its environment variable or the file of its secret
+func (f *field) loadEnv(lookupEnv func(string) (string, bool)) error {
+	if f.secret {
+		if path, ok := lookupEnv(f.env + FILE_SUFFIX); ok {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("%s%s: failed to read secret: %w", f.env, FILE_SUFFIX, err)
+			}
+			if err := f.set(strings.TrimSpace(string(data))); err != nil {
+				return fmt.Errorf("%s%s: %w", f.env, FILE_SUFFIX, err)
+			}
+			return nil
+		}
+	}
+
+	value, ok := lookupEnv(f.env)
+	if !ok {
+		return nil
+	}
+	if err := f.set(value); err != nil {
+		return fmt.Errorf("%s: %w", f.env, err)
+	}
+
+	return nil
+}
+
+func (f *field) set(value string) error {
+	if f.secret && isRedacted(value) {
+		return fmt.Errorf("%q is the placeholder of a printed config, set the secret itself", REDACTED)
+	}
+
+	v := f.value
+	switch {
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("%q must be a duration like 30s or 5m", value)
+		}
+		v.SetInt(int64(d))
+	case v.Type() == timeType:
+		if value == "" {
+			v.Set(reflect.ValueOf(time.Time{}))
+			return nil
+		}
+		t, err := time.Parse(DATE_LAYOUT, value)
+		if err != nil {
+			return fmt.Errorf("%q must be a date like %s", value, DATE_LAYOUT)
+		}
+		v.Set(reflect.ValueOf(t))
+	case v.Kind() == reflect.String:
+		v.SetString(value)
+	case v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%q must be 1, 0, true or false", value)
+		}
+		v.SetBool(b)
+	case v.Kind() == reflect.Int:
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%q must be an integer", value)
+		}
+		v.SetInt(int64(i))
+	case v.Kind() == reflect.Float64:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%q must be a number", value)
+		}
+		v.SetFloat(n)
+	case v.Kind() == reflect.Slice:
+		v.Set(reflect.ValueOf(splitList(value)))
+	default:
+		panic("config: unsupported type of " + f.path)
+	}
+
+	return nil
+}
+
+// isRedacted tells whether a value, or an item of a list, is REDACTED, which printed
+// configs have in place of secrets and must not be loaded back as one
+func isRedacted(value string) bool {
+	for _, item := range splitList(value) {
+		if item == REDACTED {
+			return true
+		}
+	}
+
+	return false
+}
+
+// splitList splits items by commas and lines, which are handy in files of secrets
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == '\n' }) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// flagSetter is a flag of a field. Bool fields may be set with a bare flag.
+type flagSetter struct {
+	isBool bool
+	set    func(string) error
+	value  string
+}
+
+func (s *flagSetter) String() string {
+	return s.value
+}
+
+func (s *flagSetter) Set(value string) error {
+	s.value = value
+	return s.set(value)
+}
+
+func (s *flagSetter) IsBoolFlag() bool {
+	return s.isBool
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/br3w0r/gamelist-backend/entity"
+	"github.com/br3w0r/gamelist-backend/repository"
+	"github.com/br3w0r/gamelist-backend/server"
+	"github.com/br3w0r/gamelist-backend/service"
+	"github.com/br3w0r/gamelist-backend/util/logger"
+	"github.com/br3w0r/gamelist-backend/util/tracing"
+	"gopkg.in/yaml.v3"
+)
+
+func joinList(items []string) string {
+	return strings.Join(items, ",")
+}
+
+// ServerOptions of the config, which must be valid, see Validate
+func (c *Config) ServerOptions() (server.ServerOptions, error) {
+	gameSources, err := service.ParseGameSources(joinList(c.Games.Sources))
+	if err != nil {
+		return server.ServerOptions{}, err
+	}
+	for i := range gameSources {
+		gameSources[i].Timeout = c.Games.HTTPTimeout
+	}
+	sourcePriority, err := entity.ParseSourcePriority(c.Games.SourcePriority)
+	if err != nil {
+		return server.ServerOptions{}, err
+	}
+	pageLimits, err := entity.ParsePageLimits(c.API.PageSizeLimits)
+	if err != nil {
+		return server.ServerOptions{}, err
+	}
+
+	scraper := service.DefaultScraperConfig
+	scraper.Address = c.Scraper.Address
+	scraper.TLS = c.Scraper.TLS
+	scraper.CAFile = c.Scraper.CAFile
+	scraper.CertFile = c.Scraper.CertFile
+	scraper.KeyFile = c.Scraper.KeyFile
+	scraper.ServerName = c.Scraper.ServerName
+	scraper.CallTimeout = c.Scraper.CallTimeout
+	scraper.MaxRetries = c.Scraper.MaxRetries
+
+	return server.ServerOptions{
+		Production:           c.Server.Production,
+		ServeStatic:          c.Server.ServeStatic,
+		ForceScrape:          c.Games.ForceScrape,
+		StaticDir:            c.Server.StaticDir,
+		Scraper:              scraper,
+		GameSources:          gameSources,
+		SourcePriority:       sourcePriority,
+		PageLimits:           pageLimits,
+		GraphQLMaxComplexity: c.API.GraphQLMaxComplexity,
+		EventBackend:         c.API.EventBackend,
+		// Stress tests run while the boot scrape goes on
+		ScraperAsync:      c.StressTest.Enabled,
+		ScrapeSchedule:    c.Games.ScrapeSchedule,
+		DeletionGrace:     c.Accounts.DeletionGrace,
+		PurgeSchedule:     c.Accounts.PurgeSchedule,
+		APIv0Sunset:       c.API.V0Sunset,
+		AdminGRPCAddress:  c.Admin.GRPCAddress,
+		AdminTokens:       c.Admin.Tokens,
+		MetricsAddress:    c.Metrics.Address,
+		MetricsToken:      c.Metrics.Token,
+		ReadyCheckScraper: c.Server.ReadyCheckScraper,
+		DrainDelay:        c.Server.DrainDelay,
+		Address:           ":" + strconv.Itoa(c.Server.Port),
+		ReadTimeout:       c.Server.ReadTimeout,
+		WriteTimeout:      c.Server.WriteTimeout,
+		IdleTimeout:       c.Server.IdleTimeout,
+		ShutdownTimeout:   c.Server.ShutdownTimeout,
+		StressTest:        c.StressTest.Enabled,
+		StressTestOptions: c.StressTest.Options,
+		DBConfig: &repository.DBConfig{
+			Host:     c.Database.Host,
+			Port:     strconv.Itoa(c.Database.Port),
+			User:     c.Database.User,
+			DBName:   c.Database.Name,
+			Password: c.Database.Password,
+			SSL:      c.Database.SSL,
+			TimeZone: c.Database.TimeZone,
+		},
+		DBQueryTimeout: c.Database.QueryTimeout,
+	}, nil
+}
+
+// LoggerConfig of the config, which must be valid, see Validate
+func (c *Config) LoggerConfig() (logger.Config, error) {
+	level, err := logger.ParseLevel(c.Log.Level)
+	if err != nil {
+		return logger.Config{}, err
+	}
+	levels, err := logger.ParseLevels(c.Log.Levels)
+	if err != nil {
+		return logger.Config{}, err
+	}
+
+	return logger.Config{Format: c.Log.Format, Level: level, Levels: levels}, nil
+}
+
+func (c *Config) TracingConfig() tracing.Config {
+	return tracing.Config{
+		Exporter:    c.Tracing.Exporter,
+		SampleRatio: c.Tracing.SampleRatio,
+	}
+}
+
+// Print writes the config as YAML, which may be used as a config file. Secrets which
+// are set are replaced with REDACTED, which Load rejects until they are set again.
+func (c *Config) Print(w io.Writer) error {
+	sections := yaml.Node{Kind: yaml.MappingNode}
+	var section *yaml.Node
+	for _, f := range c.fields() {
+		sectionName, name, _ := strings.Cut(f.path, ".")
+		if section == nil || sections.Content[len(sections.Content)-2].Value != sectionName {
+			section = &yaml.Node{Kind: yaml.MappingNode}
+			sections.Content = append(sections.Content, scalarNode(sectionName), section)
+		}
+
+		var value yaml.Node
+		if err := value.Encode(printedValue(f)); err != nil {
+			return err
+		}
+		section.Content = append(section.Content, scalarNode(name), &value)
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&sections); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+func scalarNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Value: value}
+}
+
+func printedValue(f field) interface{} {
+	if f.secret && !f.value.IsZero() {
+		return REDACTED
+	}
+
+	switch v := f.value.Interface().(type) {
+	case time.Duration:
+		return v.String()
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format(DATE_LAYOUT)
+	case []string:
+		// Empty lists are printed as [] rather than null
+		if len(v) == 0 {
+			return []string{}
+		}
+	}
+
+	return f.value.Interface()
+}
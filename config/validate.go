@@ -0,0 +1,91 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/br3w0r/gamelist-backend/entity"
+	"github.com/br3w0r/gamelist-backend/server"
+	"github.com/br3w0r/gamelist-backend/service"
+	"github.com/br3w0r/gamelist-backend/util/logger"
+	"github.com/br3w0r/gamelist-backend/util/tracing"
+	"github.com/robfig/cron/v3"
+)
+
+// Validate checks values which parse but don't make sense. All problems are reported
+// at once, each prefixed with the name of its option.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(path string, err error) {
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+		}
+	}
+	checkf := func(path string, ok bool, format string, args ...interface{}) {
+		if !ok {
+			check(path, fmt.Errorf(format, args...))
+		}
+	}
+
+	checkf("server.port", c.Server.Port > 0 && c.Server.Port < 1<<16, "%d isn't a port", c.Server.Port)
+	checkf("server.static_dir", !c.Server.ServeStatic || c.Server.StaticDir != "", "is required to serve static files")
+	for path, d := range map[string]int64{
+		"server.read_timeout":     int64(c.Server.ReadTimeout),
+		"server.write_timeout":    int64(c.Server.WriteTimeout),
+		"server.idle_timeout":     int64(c.Server.IdleTimeout),
+		"server.shutdown_timeout": int64(c.Server.ShutdownTimeout),
+		"server.drain_delay":      int64(c.Server.DrainDelay),
+		"scraper.call_timeout":    int64(c.Scraper.CallTimeout),
+		"database.query_timeout":  int64(c.Database.QueryTimeout),
+	} {
+		checkf(path, d >= 0, "mustn't be negative")
+	}
+
+	checkf("database.host", c.Database.Host != "", "is required")
+	checkf("database.port", c.Database.Port > 0 && c.Database.Port < 1<<16, "%d isn't a port", c.Database.Port)
+	checkf("database.user", c.Database.User != "", "is required")
+	checkf("database.name", c.Database.Name != "", "is required")
+	checkf("database.password", !c.Server.Production || c.Database.Password != "", "is required in production mode")
+
+	checkf("scraper.address", c.Scraper.Address != "", "is required")
+	checkf("scraper.cert_file", (c.Scraper.CertFile == "") == (c.Scraper.KeyFile == ""), "is required with scraper.key_file and the other way around")
+	checkf("scraper.max_retries", c.Scraper.MaxRetries >= 0, "mustn't be negative")
+	checkf("games.http_timeout", c.Games.HTTPTimeout > 0, "must be positive")
+
+	_, err := service.ParseGameSources(joinList(c.Games.Sources))
+	check("games.sources", err)
+	_, err = entity.ParseSourcePriority(c.Games.SourcePriority)
+	check("games.source_priority", err)
+	if c.Games.ScrapeSchedule != "" {
+		_, err = cron.ParseStandard(c.Games.ScrapeSchedule)
+		check("games.scrape_schedule", err)
+	}
+
+	_, err = entity.ParsePageLimits(c.API.PageSizeLimits)
+	check("api.page_size_limits", err)
+	checkf("api.graphql_max_complexity", c.API.GraphQLMaxComplexity > 0, "must be positive")
+	checkf("api.event_backend", c.API.EventBackend == server.EVENT_BACKEND_LOCAL || c.API.EventBackend == server.EVENT_BACKEND_POSTGRES,
+		"%q isn't %s or %s", c.API.EventBackend, server.EVENT_BACKEND_LOCAL, server.EVENT_BACKEND_POSTGRES)
+
+	checkf("accounts.deletion_grace", c.Accounts.DeletionGrace >= 0, "mustn't be negative")
+	if c.Accounts.PurgeSchedule != "" {
+		_, err = cron.ParseStandard(c.Accounts.PurgeSchedule)
+		check("accounts.purge_schedule", err)
+	}
+
+	switch c.Tracing.Exporter {
+	case tracing.EXPORTER_NONE, tracing.EXPORTER_STDOUT, tracing.EXPORTER_OTLP:
+	default:
+		check("tracing.exporter", fmt.Errorf("%q isn't empty, %s or %s", c.Tracing.Exporter, tracing.EXPORTER_STDOUT, tracing.EXPORTER_OTLP))
+	}
+	checkf("tracing.sample_ratio", c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "%g isn't between 0 and 1", c.Tracing.SampleRatio)
+
+	checkf("log.format", c.Log.Format == logger.FORMAT_TEXT || c.Log.Format == logger.FORMAT_JSON,
+		"%q isn't %s or %s", c.Log.Format, logger.FORMAT_TEXT, logger.FORMAT_JSON)
+	_, err = logger.ParseLevel(c.Log.Level)
+	check("log.level", err)
+	_, err = logger.ParseLevels(c.Log.Levels)
+	check("log.levels", err)
+
+	return errors.Join(errs...)
+}
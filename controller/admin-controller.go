@@ -0,0 +1,402 @@
+package controller
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+
+	"github.com/br3w0r/gamelist-backend/entity"
+	pb "github.com/br3w0r/gamelist-backend/proto"
+	"github.com/br3w0r/gamelist-backend/service"
+	utilErrs "github.com/br3w0r/gamelist-backend/util/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type gamelistAdminController struct {
+	pb.UnimplementedGamelistAdminServer
+
+	gamelistService  service.GameListService
+	scrapeJobService service.ScrapeJobService
+}
+
+func NewGamelistAdminController(
+	gamelistService service.GameListService,
+	scrapeJobService service.ScrapeJobService,
+) pb.GamelistAdminServer {
+	return &gamelistAdminController{
+		gamelistService:  gamelistService,
+		scrapeJobService: scrapeJobService,
+	}
+}
+
+// AdminAuthInterceptors only let through calls with "authorization: Bearer <token>"
+// metadata where the token is one of the given admin tokens
+func AdminAuthInterceptors(tokens []string) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := checkAdminToken(ctx, tokens); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+
+	stream := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := checkAdminToken(ss.Context(), tokens); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+
+	return unary, stream
+}
+
+func checkAdminToken(ctx context.Context, tokens []string) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	authHeader := md.Get("authorization")
+	if len(authHeader) == 0 {
+		return GRPCError(utilErrs.New(utilErrs.Unauthorized, nil, "no authorization metadata provided").WithReason(utilErrs.ReasonTokenMissing))
+	}
+
+	list := strings.Split(authHeader[0], " ")
+	if len(list) != 2 || list[0] != "Bearer" {
+		return GRPCError(utilErrs.New(utilErrs.Unauthorized, nil, "wrong authorization metadata format").WithReason(utilErrs.ReasonTokenInvalid))
+	}
+
+	for _, token := range tokens {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(list[1])) == 1 {
+			return nil
+		}
+	}
+
+	return GRPCError(utilErrs.New(utilErrs.Unauthorized, nil, "authentication failed").WithReason(utilErrs.ReasonTokenInvalid))
+}
+
+func (c *gamelistAdminController) CreateGame(ctx context.Context, req *pb.Game) (*pb.Game, error) {
+	game := req.ConvertToEntity()
+	game.ID = 0
+	if err := c.gamelistService.SaveGame(ctx, &game); err != nil {
+		return nil, GRPCError(err)
+	}
+
+	return pb.GameFromEntity(&game), nil
+}
+
+func (c *gamelistAdminController) GetGame(ctx context.Context, req *pb.IdRequest) (*pb.Game, error) {
+	game, err := c.gamelistService.GetGame(ctx, req.Id)
+	if err != nil {
+		return nil, GRPCError(err)
+	}
+
+	return pb.GameFromEntity(game), nil
+}
+
+func (c *gamelistAdminController) ListGames(ctx context.Context, req *pb.ListRequest) (*pb.GameList, error) {
+	games, err := c.gamelistService.GetGames(ctx, pageRequest(req))
+	if err != nil {
+		return nil, GRPCError(err)
+	}
+
+	list := &pb.GameList{Games: make([]*pb.Game, len(games.Items)), NextCursor: games.NextCursor}
+	for i := range games.Items {
+		list.Games[i] = pb.GameFromEntity(&games.Items[i])
+	}
+
+	return list, nil
+}
+
+func (c *gamelistAdminController) UpdateGame(ctx context.Context, req *pb.Game) (*pb.Game, error) {
+	game := req.ConvertToEntity()
+	if err := c.gamelistService.UpdateGame(ctx, &game); err != nil {
+		return nil, GRPCError(err)
+	}
+
+	return pb.GameFromEntity(&game), nil
+}
+
+func (c *gamelistAdminController) DeleteGame(ctx context.Context, req *pb.DeleteRequest) (*pb.Empty, error) {
+	if err := c.gamelistService.DeleteGame(ctx, req.Id, req.ReassignTo); err != nil {
+		return nil, GRPCError(err)
+	}
+
+	return &pb.Empty{}, nil
+}
+
+func (c *gamelistAdminController) RestoreGame(ctx context.Context, req *pb.IdRequest) (*pb.Empty, error) {
+	if err := c.gamelistService.RestoreGame(ctx, req.Id); err != nil {
+		return nil, GRPCError(err)
+	}
+
+	return &pb.Empty{}, nil
+}
+
+func (c *gamelistAdminController) CreateGenre(ctx context.Context, req *pb.CatalogItem) (*pb.CatalogItem, error) {
+	genre := entity.Genre{Name: req.Name}
+	if err := c.gamelistService.SaveGenre(ctx, &genre); err != nil {
+		return nil, GRPCError(err)
+	}
+
+	return &pb.CatalogItem{Id: genre.ID, Name: genre.Name}, nil
+}
+
+func (c *gamelistAdminController) ListGenres(ctx context.Context, req *pb.ListRequest) (*pb.CatalogItemList, error) {
+	genres, err := c.gamelistService.GetAllGenres(ctx, pageRequest(req))
+	if err != nil {
+		return nil, GRPCError(err)
+	}
+
+	return catalogItemList(genres, func(item *entity.Genre) *pb.CatalogItem {
+		return &pb.CatalogItem{Id: item.ID, Name: item.Name}
+	}), nil
+}
+
+func (c *gamelistAdminController) UpdateGenre(ctx context.Context, req *pb.CatalogItem) (*pb.CatalogItem, error) {
+	genre := entity.Genre{ID: req.Id, Name: req.Name}
+	if err := c.gamelistService.UpdateGenre(ctx, &genre); err != nil {
+		return nil, GRPCError(err)
+	}
+
+	return req, nil
+}
+
+func (c *gamelistAdminController) DeleteGenre(ctx context.Context, req *pb.DeleteRequest) (*pb.Empty, error) {
+	if err := c.gamelistService.DeleteGenre(ctx, req.Id, req.ReassignTo); err != nil {
+		return nil, GRPCError(err)
+	}
+
+	return &pb.Empty{}, nil
+}
+
+func (c *gamelistAdminController) RestoreGenre(ctx context.Context, req *pb.IdRequest) (*pb.Empty, error) {
+	if err := c.gamelistService.RestoreGenre(ctx, req.Id); err != nil {
+		return nil, GRPCError(err)
+	}
+
+	return &pb.Empty{}, nil
+}
+
+func (c *gamelistAdminController) CreatePlatform(ctx context.Context, req *pb.CatalogItem) (*pb.CatalogItem, error) {
+	platform := entity.Platform{Name: req.Name}
+	if err := c.gamelistService.SavePlatform(ctx, &platform); err != nil {
+		return nil, GRPCError(err)
+	}
+
+	return &pb.CatalogItem{Id: platform.ID, Name: platform.Name}, nil
+}
+
+func (c *gamelistAdminController) ListPlatforms(ctx context.Context, req *pb.ListRequest) (*pb.CatalogItemList, error) {
+	platforms, err := c.gamelistService.GetAllPlatforms(ctx, pageRequest(req))
+	if err != nil {
+		return nil, GRPCError(err)
+	}
+
+	return catalogItemList(platforms, func(item *entity.Platform) *pb.CatalogItem {
+		return &pb.CatalogItem{Id: item.ID, Name: item.Name}
+	}), nil
+}
+
+func (c *gamelistAdminController) UpdatePlatform(ctx context.Context, req *pb.CatalogItem) (*pb.CatalogItem, error) {
+	platform := entity.Platform{ID: req.Id, Name: req.Name}
+	if err := c.gamelistService.UpdatePlatform(ctx, &platform); err != nil {
+		return nil, GRPCError(err)
+	}
+
+	return req, nil
+}
+
+func (c *gamelistAdminController) DeletePlatform(ctx context.Context, req *pb.DeleteRequest) (*pb.Empty, error) {
+	if err := c.gamelistService.DeletePlatform(ctx, req.Id, req.ReassignTo); err != nil {
+		return nil, GRPCError(err)
+	}
+
+	return &pb.Empty{}, nil
+}
+
+func (c *gamelistAdminController) RestorePlatform(ctx context.Context, req *pb.IdRequest) (*pb.Empty, error) {
+	if err := c.gamelistService.RestorePlatform(ctx, req.Id); err != nil {
+		return nil, GRPCError(err)
+	}
+
+	return &pb.Empty{}, nil
+}
+
+func (c *gamelistAdminController) CreateListType(ctx context.Context, req *pb.CatalogItem) (*pb.CatalogItem, error) {
+	listType := entity.ListType{Name: req.Name}
+	if err := c.gamelistService.CreateListType(ctx, &listType); err != nil {
+		return nil, GRPCError(err)
+	}
+
+	return &pb.CatalogItem{Id: listType.ID, Name: listType.Name}, nil
+}
+
+func (c *gamelistAdminController) ListListTypes(ctx context.Context, req *pb.ListRequest) (*pb.CatalogItemList, error) {
+	types, err := c.gamelistService.GetAllListTypes(ctx, pageRequest(req))
+	if err != nil {
+		return nil, GRPCError(err)
+	}
+
+	return catalogItemList(types, func(item *entity.ListType) *pb.CatalogItem {
+		return &pb.CatalogItem{Id: item.ID, Name: item.Name}
+	}), nil
+}
+
+func (c *gamelistAdminController) UpdateListType(ctx context.Context, req *pb.CatalogItem) (*pb.CatalogItem, error) {
+	listType := entity.ListType{Model: entity.Model{ID: req.Id}, Name: req.Name}
+	if err := c.gamelistService.UpdateListType(ctx, &listType); err != nil {
+		return nil, GRPCError(err)
+	}
+
+	return req, nil
+}
+
+func (c *gamelistAdminController) DeleteListType(ctx context.Context, req *pb.DeleteRequest) (*pb.Empty, error) {
+	if err := c.gamelistService.DeleteListType(ctx, req.Id, req.ReassignTo); err != nil {
+		return nil, GRPCError(err)
+	}
+
+	return &pb.Empty{}, nil
+}
+
+func (c *gamelistAdminController) RestoreListType(ctx context.Context, req *pb.IdRequest) (*pb.Empty, error) {
+	if err := c.gamelistService.RestoreListType(ctx, req.Id); err != nil {
+		return nil, GRPCError(err)
+	}
+
+	return &pb.Empty{}, nil
+}
+
+func (c *gamelistAdminController) CreateSocialType(ctx context.Context, req *pb.CatalogItem) (*pb.CatalogItem, error) {
+	socialType := entity.SocialType{Name: req.Name}
+	if err := c.gamelistService.SaveSocialType(ctx, &socialType); err != nil {
+		return nil, GRPCError(err)
+	}
+
+	return &pb.CatalogItem{Id: socialType.ID, Name: socialType.Name}, nil
+}
+
+func (c *gamelistAdminController) ListSocialTypes(ctx context.Context, req *pb.ListRequest) (*pb.CatalogItemList, error) {
+	types, err := c.gamelistService.GetAllSocialTypes(ctx, pageRequest(req))
+	if err != nil {
+		return nil, GRPCError(err)
+	}
+
+	return catalogItemList(types, func(item *entity.SocialType) *pb.CatalogItem {
+		return &pb.CatalogItem{Id: item.ID, Name: item.Name}
+	}), nil
+}
+
+func (c *gamelistAdminController) UpdateSocialType(ctx context.Context, req *pb.CatalogItem) (*pb.CatalogItem, error) {
+	socialType := entity.SocialType{Model: entity.Model{ID: req.Id}, Name: req.Name}
+	if err := c.gamelistService.UpdateSocialType(ctx, &socialType); err != nil {
+		return nil, GRPCError(err)
+	}
+
+	return req, nil
+}
+
+func (c *gamelistAdminController) DeleteSocialType(ctx context.Context, req *pb.DeleteRequest) (*pb.Empty, error) {
+	if err := c.gamelistService.DeleteSocialType(ctx, req.Id, req.ReassignTo); err != nil {
+		return nil, GRPCError(err)
+	}
+
+	return &pb.Empty{}, nil
+}
+
+func (c *gamelistAdminController) RestoreSocialType(ctx context.Context, req *pb.IdRequest) (*pb.Empty, error) {
+	if err := c.gamelistService.RestoreSocialType(ctx, req.Id); err != nil {
+		return nil, GRPCError(err)
+	}
+
+	return &pb.Empty{}, nil
+}
+
+func (c *gamelistAdminController) StartScrapeJob(ctx context.Context, req *pb.Empty) (*pb.ScrapeJob, error) {
+	job, err := c.scrapeJobService.Start(ctx, entity.ScrapeTriggerManual)
+	if err != nil {
+		return nil, GRPCError(err)
+	}
+
+	return pb.ScrapeJobFromEntity(job), nil
+}
+
+func (c *gamelistAdminController) GetScrapeJob(ctx context.Context, req *pb.IdRequest) (*pb.ScrapeJob, error) {
+	job, err := c.scrapeJobService.GetJob(ctx, req.Id)
+	if err != nil {
+		return nil, GRPCError(err)
+	}
+
+	return pb.ScrapeJobFromEntity(job), nil
+}
+
+func (c *gamelistAdminController) ListScrapeJobs(ctx context.Context, req *pb.ListRequest) (*pb.ScrapeJobList, error) {
+	jobs, err := c.scrapeJobService.GetJobs(ctx, int(req.Limit))
+	if err != nil {
+		return nil, GRPCError(err)
+	}
+
+	list := &pb.ScrapeJobList{Jobs: make([]*pb.ScrapeJob, len(jobs))}
+	for i := range jobs {
+		list.Jobs[i] = pb.ScrapeJobFromEntity(&jobs[i])
+	}
+
+	return list, nil
+}
+
+func (c *gamelistAdminController) CancelScrapeJob(ctx context.Context, req *pb.IdRequest) (*pb.Empty, error) {
+	if err := c.scrapeJobService.Cancel(req.Id); err != nil {
+		return nil, GRPCError(err)
+	}
+
+	return &pb.Empty{}, nil
+}
+
+func (c *gamelistAdminController) ListGameDuplicates(ctx context.Context, req *pb.ListDuplicatesRequest) (*pb.GameDuplicateList, error) {
+	duplicates, err := c.gamelistService.GetGameDuplicates(ctx, req.Status, int(req.Limit))
+	if err != nil {
+		return nil, GRPCError(err)
+	}
+
+	list := &pb.GameDuplicateList{Duplicates: make([]*pb.GameDuplicate, len(duplicates))}
+	for i := range duplicates {
+		list.Duplicates[i] = pb.GameDuplicateFromEntity(&duplicates[i])
+	}
+
+	return list, nil
+}
+
+func (c *gamelistAdminController) ScanGameDuplicates(ctx context.Context, req *pb.Empty) (*pb.ScanDuplicatesResponse, error) {
+	found, err := c.gamelistService.ScanDuplicates(ctx)
+	if err != nil {
+		return nil, GRPCError(err)
+	}
+
+	return &pb.ScanDuplicatesResponse{Found: uint64(found)}, nil
+}
+
+func (c *gamelistAdminController) DismissGameDuplicate(ctx context.Context, req *pb.GameDuplicateRequest) (*pb.Empty, error) {
+	if err := c.gamelistService.DismissGameDuplicate(ctx, req.GameId, req.DuplicateId); err != nil {
+		return nil, GRPCError(err)
+	}
+
+	return &pb.Empty{}, nil
+}
+
+func (c *gamelistAdminController) MergeGames(ctx context.Context, req *pb.MergeGamesRequest) (*pb.Empty, error) {
+	if err := c.gamelistService.MergeGames(ctx, req.SurvivorId, req.DuplicateId); err != nil {
+		return nil, GRPCError(err)
+	}
+
+	return &pb.Empty{}, nil
+}
+
+func pageRequest(req *pb.ListRequest) entity.PageRequest {
+	return entity.PageRequest{Cursor: req.Cursor, Limit: int(req.Limit)}
+}
+
+func catalogItemList[T any](page *entity.Page[T], item func(*T) *pb.CatalogItem) *pb.CatalogItemList {
+	list := &pb.CatalogItemList{Items: make([]*pb.CatalogItem, len(page.Items)), NextCursor: page.NextCursor}
+	for i := range page.Items {
+		list.Items[i] = item(&page.Items[i])
+	}
+
+	return list
+}
@@ -0,0 +1,609 @@
+package controller
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/br3w0r/gamelist-backend/entity"
+	pb "github.com/br3w0r/gamelist-backend/proto"
+	"github.com/br3w0r/gamelist-backend/repository"
+	"github.com/br3w0r/gamelist-backend/service"
+	utilErrs "github.com/br3w0r/gamelist-backend/util/errors"
+	utilLogger "github.com/br3w0r/gamelist-backend/util/logger"
+	"github.com/br3w0r/gamelist-backend/util/metrics"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/smartystreets/goconvey/convey"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const testAdminToken = "test-admin-token"
+
+func newTestAdminClient(t *testing.T, repo *service.MockGamelistRepository) pb.GamelistAdminClient {
+	gamelistService := service.NewGameListService(repo, nil, nil, nil, nil)
+	scrapeJobService := service.NewScrapeJobService(repo, gamelistService)
+
+	lis := bufconn.Listen(1 << 20)
+	unary, stream := AdminAuthInterceptors([]string{testAdminToken})
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(unary), grpc.StreamInterceptor(stream))
+	pb.RegisterGamelistAdminServer(grpcServer, NewGamelistAdminController(gamelistService, scrapeJobService))
+	go grpcServer.Serve(lis) //nolint:errcheck
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return pb.NewGamelistAdminClient(conn)
+}
+
+func withToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func TestGamelistAdmin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := service.NewMockGamelistRepository(ctrl)
+	client := newTestAdminClient(t, repo)
+
+	convey.Convey("Calls without a valid admin token are rejected", t, func() {
+		_, err := client.ListGenres(context.Background(), &pb.ListRequest{})
+		convey.So(status.Code(err), convey.ShouldEqual, codes.Unauthenticated)
+
+		_, err = client.ListGenres(withToken("wrong"), &pb.ListRequest{})
+		convey.So(status.Code(err), convey.ShouldEqual, codes.Unauthenticated)
+	})
+
+	convey.Convey("Created catalog items get their ids", t, func() {
+		repo.EXPECT().SaveGenre(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, genre *entity.Genre) error {
+			genre.ID = 7
+			return nil
+		})
+
+		genre, err := client.CreateGenre(withToken(testAdminToken), &pb.CatalogItem{Name: "RPG"})
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(genre.Id, convey.ShouldEqual, 7)
+		convey.So(genre.Name, convey.ShouldEqual, "RPG")
+	})
+
+	convey.Convey("Service errors are mapped to gRPC codes", t, func() {
+		repo.EXPECT().DeleteGenre(gomock.Any(), uint64(42), uint64(0)).Return(utilErrs.New(utilErrs.NotFound, nil, "no such genre"))
+
+		_, err := client.DeleteGenre(withToken(testAdminToken), &pb.DeleteRequest{Id: 42})
+		convey.So(status.Code(err), convey.ShouldEqual, codes.NotFound)
+		convey.So(status.Convert(err).Message(), convey.ShouldEqual, "no such genre")
+	})
+
+	convey.Convey("Deletes pass the reassignment target", t, func() {
+		repo.EXPECT().DeleteListType(gomock.Any(), uint64(3), uint64(1)).Return(nil)
+
+		_, err := client.DeleteListType(withToken(testAdminToken), &pb.DeleteRequest{Id: 3, ReassignTo: 1})
+		convey.So(err, convey.ShouldBeNil)
+	})
+
+	convey.Convey("Games are updated with their platforms and genres", t, func() {
+		var updated entity.GameProperties
+		repo.EXPECT().UpdateGame(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, game *entity.GameProperties) error {
+			updated = *game
+			return nil
+		})
+
+		game, err := client.UpdateGame(withToken(testAdminToken), &pb.Game{
+			Id:           3,
+			Name:         "Doom",
+			YearReleased: 1993,
+			Platforms:    []string{"PC"},
+			Genres:       []string{"Shooter"},
+		})
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(game.Name, convey.ShouldEqual, "Doom")
+		convey.So(updated.ID, convey.ShouldEqual, 3)
+		convey.So(updated.Platforms, convey.ShouldResemble, []entity.Platform{{Name: "PC"}})
+		convey.So(updated.Genres, convey.ShouldResemble, []entity.Genre{{Name: "Shooter"}})
+	})
+}
+
+func serveTestRequest(handler gin.HandlerFunc, method string, path string, body string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Handle(method, "/items", handler)
+	router.Handle(method, "/items/:id", handler)
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestGenericHandlers(t *testing.T) {
+	type item struct {
+		Name string `json:"name" binding:"required,lte=5"`
+	}
+
+	convey.Convey("Create should validate bodies before calling the service", t, func() {
+		var created []item
+		create := Create(func(_ context.Context, obj item) error {
+			created = append(created, obj)
+			return nil
+		})
+
+		w := serveTestRequest(create, "POST", "/items", `{"name": "RPG"}`)
+		convey.So(w.Code, convey.ShouldEqual, http.StatusOK)
+		convey.So(w.Body.String(), convey.ShouldEqual, `{"message":"ok"}`)
+
+		w = serveTestRequest(create, "POST", "/items", `{"name": "Too long"}`)
+		convey.So(w.Code, convey.ShouldEqual, http.StatusBadRequest)
+
+		convey.So(created, convey.ShouldResemble, []item{{Name: "RPG"}})
+	})
+
+	convey.Convey("Get and Update should take the id from the path", t, func() {
+		get := Get(func(_ context.Context, id uint64) (*item, error) {
+			if id != 1 {
+				return nil, utilErrs.New(utilErrs.NotFound, nil, "no such item")
+			}
+			return &item{Name: "RPG"}, nil
+		})
+
+		w := serveTestRequest(get, "GET", "/items/1", "")
+		convey.So(w.Code, convey.ShouldEqual, http.StatusOK)
+		convey.So(w.Body.String(), convey.ShouldEqual, `{"name":"RPG"}`)
+
+		w = serveTestRequest(get, "GET", "/items/2", "")
+		convey.So(w.Code, convey.ShouldEqual, http.StatusNotFound)
+
+		w = serveTestRequest(get, "GET", "/items/rpg", "")
+		convey.So(w.Code, convey.ShouldEqual, http.StatusBadRequest)
+
+		var updatedID uint64
+		update := Update(func(_ context.Context, id uint64, obj *item) error {
+			updatedID = id
+			return nil
+		})
+
+		w = serveTestRequest(update, "PUT", "/items/3", `{"name": "FPS"}`)
+		convey.So(w.Code, convey.ShouldEqual, http.StatusOK)
+		convey.So(updatedID, convey.ShouldEqual, 3)
+	})
+
+	convey.Convey("List should pass the page query and send pages", t, func() {
+		var requested entity.PageRequest
+		list := List(func(_ context.Context, page entity.PageRequest) (*entity.Page[item], error) {
+			requested = page
+			return entity.NewPage([]item{{Name: "RPG"}, {Name: "FPS"}}, 1, func(*item) entity.Cursor {
+				return entity.Cursor{ID: 7}
+			}), nil
+		})
+
+		w := serveTestRequest(list, "GET", "/items?cursor=abc&limit=1", "")
+		convey.So(w.Code, convey.ShouldEqual, http.StatusOK)
+		convey.So(requested, convey.ShouldResemble, entity.PageRequest{Cursor: "abc", Limit: 1})
+		convey.So(w.Body.String(), convey.ShouldEqual, `{"items":[{"name":"RPG"}],"next_cursor":"`+entity.Cursor{ID: 7}.Encode()+`"}`)
+
+		w = serveTestRequest(list, "GET", "/items?limit=-1", "")
+		convey.So(w.Code, convey.ShouldEqual, http.StatusBadRequest)
+		convey.So(w.Body.String(), convey.ShouldContainSubstring, `"reason":"INVALID_PAGE_LIMIT"`)
+
+		bare := func(ctx *gin.Context) {
+			BareLists(ctx)
+			list(ctx)
+		}
+		w = serveTestRequest(bare, "GET", "/items?limit=1", "")
+		convey.So(w.Code, convey.ShouldEqual, http.StatusOK)
+		convey.So(w.Body.String(), convey.ShouldEqual, `[{"name":"RPG"}]`)
+	})
+
+	convey.Convey("Delete should send service errors", t, func() {
+		del := Delete(func(_ context.Context, id uint64) error {
+			return utilErrs.New(utilErrs.Conflict, nil, "item is in use").WithReason(utilErrs.ReasonItemInUse)
+		})
+
+		w := serveTestRequest(del, "DELETE", "/items/4", "")
+		convey.So(w.Code, convey.ShouldEqual, http.StatusConflict)
+		convey.So(w.Body.String(), convey.ShouldContainSubstring, `"reason":"ITEM_IN_USE"`)
+	})
+
+	convey.Convey("Functions should get contexts of requests and time out with them", t, func() {
+		get := Get(func(ctx context.Context, id uint64) (*item, error) {
+			<-ctx.Done()
+			return nil, utilErrs.FromDB(ctx.Err(), "failed to get item")
+		})
+
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.GET("/items/:id", get)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/items/1", nil).WithContext(ctx))
+		convey.So(w.Code, convey.ShouldEqual, http.StatusRequestTimeout)
+		convey.So(w.Body.String(), convey.ShouldContainSubstring, `"code":"TIMEOUT"`)
+		convey.So(w.Body.String(), convey.ShouldContainSubstring, `"message":"failed to get item: query timed out"`)
+	})
+}
+
+func TestErrorResponses(t *testing.T) {
+	type item struct {
+		Name  string `json:"name" binding:"required,lte=5"`
+		Count int    `json:"count" binding:"gte=1"`
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID)
+	router.POST("/items", Create(func(_ context.Context, obj item) error { return nil }))
+
+	send := func(body string, requestID string) (*httptest.ResponseRecorder, utilErrs.ErrorResponse) {
+		req := httptest.NewRequest("POST", "/items", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if requestID != "" {
+			req.Header.Set(REQUEST_ID_HEADER, requestID)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var response utilErrs.ErrorResponse
+		json.Unmarshal(w.Body.Bytes(), &response) //nolint:errcheck
+		return w, response
+	}
+
+	convey.Convey("Failed validation rules should be sent as field errors", t, func() {
+		w, response := send(`{"name": "Too long"}`, "req-1")
+		convey.So(w.Code, convey.ShouldEqual, http.StatusBadRequest)
+		convey.So(response.Code, convey.ShouldEqual, "BAD_INPUT")
+		convey.So(response.Reason, convey.ShouldEqual, utilErrs.ReasonValidationFailed)
+		convey.So(response.Fields, convey.ShouldResemble, []utilErrs.FieldError{
+			{Field: "name", Rule: "lte", Param: "5", Message: "name must be at most 5"},
+			{Field: "count", Rule: "gte", Param: "1", Message: "count must be at least 1"},
+		})
+
+		_, response = send(`{"name": "RPG", "count": "many"}`, "")
+		convey.So(response.Reason, convey.ShouldEqual, utilErrs.ReasonValidationFailed)
+		convey.So(response.Fields, convey.ShouldHaveLength, 1)
+		convey.So(response.Fields[0].Field, convey.ShouldEqual, "count")
+		convey.So(response.Fields[0].Rule, convey.ShouldEqual, "type")
+
+		_, response = send(`{"name": `, "")
+		convey.So(response.Reason, convey.ShouldEqual, utilErrs.ReasonMalformedRequest)
+		convey.So(response.Fields, convey.ShouldBeEmpty)
+	})
+
+	convey.Convey("Errors should have ids of their requests", t, func() {
+		w, response := send(`{}`, "req-1")
+		convey.So(w.Header().Get(REQUEST_ID_HEADER), convey.ShouldEqual, "req-1")
+		convey.So(response.RequestID, convey.ShouldEqual, "req-1")
+
+		w, response = send(`{}`, "not an id\n")
+		convey.So(response.RequestID, convey.ShouldHaveLength, 32)
+		convey.So(w.Header().Get(REQUEST_ID_HEADER), convey.ShouldEqual, response.RequestID)
+	})
+
+	convey.Convey("gRPC errors should have reasons and field errors as details", t, func() {
+		err := GRPCError(utilErrs.New(utilErrs.BadInput, nil, "request validation failed").
+			WithReason(utilErrs.ReasonValidationFailed).
+			WithFields([]utilErrs.FieldError{{Field: "name", Rule: "required", Message: "name is required"}}))
+
+		st := status.Convert(err)
+		convey.So(st.Code(), convey.ShouldEqual, codes.InvalidArgument)
+		convey.So(st.Details(), convey.ShouldHaveLength, 2)
+		convey.So(st.Details()[0].(*errdetails.ErrorInfo).Reason, convey.ShouldEqual, "VALIDATION_FAILED")
+		convey.So(st.Details()[1].(*errdetails.BadRequest).FieldViolations[0].Field, convey.ShouldEqual, "name")
+
+		st = status.Convert(GRPCError(utilErrs.New(utilErrs.Conflict, nil, "scrape job 1 is already running")))
+		convey.So(st.Code(), convey.ShouldEqual, codes.FailedPrecondition)
+		convey.So(st.Details()[0].(*errdetails.ErrorInfo).Reason, convey.ShouldEqual, "CONFLICT")
+
+		convey.Convey("Conflicts should get codes of their reasons", func() {
+			for reason, code := range map[utilErrs.Reason]codes.Code{
+				utilErrs.ReasonAlreadyExists:    codes.AlreadyExists,
+				utilErrs.ReasonNicknameTaken:    codes.AlreadyExists,
+				utilErrs.ReasonEmailTaken:       codes.AlreadyExists,
+				utilErrs.ReasonItemInUse:        codes.FailedPrecondition,
+				utilErrs.ReasonScrapeJobRunning: codes.FailedPrecondition,
+				utilErrs.ReasonConcurrentChange: codes.Aborted,
+			} {
+				err := GRPCError(utilErrs.New(utilErrs.Conflict, nil, "conflict").WithReason(reason))
+				convey.So(status.Code(err), convey.ShouldEqual, code)
+			}
+		})
+	})
+}
+
+func TestGameBatches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := service.NewMockGamelistRepository(ctrl)
+	controller := NewGameListController(service.NewGameListService(repo, nil, nil, nil, nil), nil, nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/games/all", func(ctx *gin.Context) { ctx.Set("nickname", "test") }, controller.GetAllGamesTyped)
+
+	send := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/games/all", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	games := func(ids ...uint64) []entity.TypedGameListProperties {
+		result := make([]entity.TypedGameListProperties, len(ids))
+		for i, id := range ids {
+			result[i].ID = id
+		}
+		return result
+	}
+
+	convey.Convey("v0 batches should be pages of games after the last one", t, func() {
+		repo.EXPECT().GetAllGamesTyped(gomock.Any(), "test", "", entity.Cursor{ID: 5}, 2).Return(games(6, 7, 8), nil)
+
+		w := send(`{"last": 5, "batch_size": 2}`)
+		convey.So(w.Code, convey.ShouldEqual, http.StatusOK)
+
+		var batch []entity.TypedGameListProperties
+		convey.So(json.Unmarshal(w.Body.Bytes(), &batch), convey.ShouldBeNil)
+		convey.So(batch, convey.ShouldHaveLength, 2)
+		convey.So(batch[1].ID, convey.ShouldEqual, 7)
+
+		repo.EXPECT().GetAllGamesTyped(gomock.Any(), "test", "", entity.Cursor{}, entity.GAMES_BATCH_SIZE_LIMIT).Return(games(), nil)
+		w = send(`{}`)
+		convey.So(w.Body.String(), convey.ShouldEqual, "[]")
+
+		repo.EXPECT().GetAllGamesTyped(gomock.Any(), "test", "", entity.Cursor{}, entity.GAMES_BATCH_SIZE_LIMIT).Return(games(), nil)
+		convey.So(send(`{"batch_size": 50}`).Code, convey.ShouldEqual, http.StatusOK)
+
+		w = send(`{"batch_size": -1}`)
+		convey.So(w.Code, convey.ShouldEqual, http.StatusBadRequest)
+		convey.So(w.Body.String(), convey.ShouldContainSubstring, `"reason":"INVALID_PAGE_LIMIT"`)
+	})
+}
+
+func TestEventsStream(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := service.NewMockGamelistRepository(ctrl)
+	repo.EXPECT().
+		WithTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, fn func(repository.GamelistRepository) error) error {
+			return fn(repo)
+		}).
+		AnyTimes()
+	bus := service.NewEventBus(repository.NewLocalEventBackend())
+	defer bus.Close()
+	gamelistService := service.NewGameListService(repo, nil, nil, nil, bus)
+	events := NewEventsController(gamelistService, bus)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	authorized := func(ctx *gin.Context) { ctx.Set("nickname", "viewer") }
+	router.GET("/events", authorized, events.Stream)
+	router.GET("/profiles/:nickname/events", authorized, events.Stream)
+	server := httptest.NewUnstartedServer(KeepResponseWriter(router))
+	// Streams shouldn't be cut by the write timeout
+	server.Config.WriteTimeout = 100 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	convey.Convey("Streams should send events of the watched profile", t, func() {
+		repo.EXPECT().GetProfileInfo(gomock.Any(), "test").Return(&entity.ProfileInfo{Nickname: "test"}, nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		req, _ := http.NewRequestWithContext(ctx, "GET", server.URL+"/profiles/test/events", nil)
+		resp, err := http.DefaultClient.Do(req)
+		convey.So(err, convey.ShouldBeNil)
+		defer resp.Body.Close()
+		convey.So(resp.Header.Get("Content-Type"), convey.ShouldStartWith, "text/event-stream")
+
+		lines := bufio.NewScanner(resp.Body)
+		next := func() string {
+			for lines.Scan() {
+				if lines.Text() != "" {
+					return lines.Text()
+				}
+			}
+			return ""
+		}
+		convey.So(next(), convey.ShouldEqual, "event:ready")
+		next()
+		time.Sleep(2 * server.Config.WriteTimeout)
+
+		repo.EXPECT().ListGame(gomock.Any(), "viewer", uint64(1), uint64(2)).Return(nil)
+		repo.EXPECT().ListGame(gomock.Any(), "test", uint64(3), uint64(2)).Return(nil)
+		convey.So(gamelistService.ListGame(context.Background(), "viewer", 1, 2), convey.ShouldBeNil)
+		convey.So(gamelistService.ListGame(context.Background(), "test", 3, 2), convey.ShouldBeNil)
+
+		convey.So(next(), convey.ShouldEqual, "event:"+entity.EventListUpdated)
+		convey.So(next(), convey.ShouldStartWith, `data:{"type":"list.updated","nickname":"test","game_id":3,"list_type":2,`)
+	})
+
+	convey.Convey("Streams of unknown profiles should be rejected", t, func() {
+		repo.EXPECT().GetProfileInfo(gomock.Any(), "nobody").Return(nil, utilErrs.New(utilErrs.NotFound, nil, "profile not found"))
+
+		resp, err := http.Get(server.URL + "/profiles/nobody/events")
+		convey.So(err, convey.ShouldBeNil)
+		resp.Body.Close()
+		convey.So(resp.StatusCode, convey.ShouldEqual, http.StatusNotFound)
+	})
+}
+
+func TestRequestLogs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := service.NewMockGamelistRepository(ctrl)
+	repo.EXPECT().SaveRefreshToken(gomock.Any(), "nickname", gomock.Any()).Return(nil)
+	jwtService := service.NewJWTService(repo)
+	tokens, err := jwtService.GenerateTokens(context.Background(), "nickname")
+	if err != nil {
+		t.Fatal(err)
+	}
+	controller := NewGameListController(nil, jwtService, nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID, AccessLog, Recovery)
+	router.GET("/ok", controller.Authorized, func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	router.GET("/fail", controller.Authorized, func(ctx *gin.Context) {
+		ErrorSender(ctx, errors.New("connection refused"))
+	})
+
+	var out bytes.Buffer
+	setup := func(levels map[string]slog.Level) {
+		out.Reset()
+		utilLogger.Setup(utilLogger.Config{Format: utilLogger.FORMAT_JSON, Output: &out, Levels: levels})
+	}
+	defer utilLogger.Setup(utilLogger.Config{})
+
+	send := func(path string) []map[string]interface{} {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Authorization", "Bearer "+tokens.Token)
+		req.Header.Set(REQUEST_ID_HEADER, "req-42")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		var lines []map[string]interface{}
+		for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+			if line == "" {
+				continue
+			}
+			var entry map[string]interface{}
+			json.Unmarshal([]byte(line), &entry) //nolint:errcheck
+			lines = append(lines, entry)
+		}
+		return lines
+	}
+
+	convey.Convey("Log lines should have the request id and the nickname", t, func() {
+		setup(nil)
+		lines := send("/ok")
+		convey.So(lines, convey.ShouldHaveLength, 1)
+		convey.So(lines[0]["msg"], convey.ShouldEqual, "request")
+		convey.So(lines[0]["subsystem"], convey.ShouldEqual, utilLogger.SubsystemHTTP)
+		convey.So(lines[0]["request_id"], convey.ShouldEqual, "req-42")
+		convey.So(lines[0]["nickname"], convey.ShouldEqual, "nickname")
+		convey.So(lines[0]["status"], convey.ShouldEqual, http.StatusOK)
+
+		setup(nil)
+		lines = send("/fail")
+		convey.So(lines, convey.ShouldHaveLength, 2)
+		convey.So(lines[0]["msg"], convey.ShouldEqual, "internal error")
+		convey.So(lines[0]["level"], convey.ShouldEqual, "ERROR")
+		convey.So(lines[0]["request_id"], convey.ShouldEqual, "req-42")
+		convey.So(lines[0]["nickname"], convey.ShouldEqual, "nickname")
+		convey.So(lines[1]["status"], convey.ShouldEqual, http.StatusInternalServerError)
+
+		convey.Convey("With levels of subsystems", func() {
+			setup(map[string]slog.Level{utilLogger.SubsystemHTTP: slog.LevelError})
+			convey.So(send("/ok"), convey.ShouldBeEmpty)
+			convey.So(send("/fail"), convey.ShouldHaveLength, 2)
+		})
+	})
+}
+
+func TestMetrics(t *testing.T) {
+	controller := NewGameListController(nil, nil, nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Metrics)
+	router.GET("/games/:id", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	reached := false
+	router.GET("/private", controller.Authorized, func(ctx *gin.Context) { reached = true })
+
+	send := func(path string) int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		return w.Code
+	}
+
+	convey.Convey("Requests should be counted by route and status", t, func() {
+		requests := metrics.HTTPRequests.WithLabelValues("GET", "/games/:id", "200")
+		before := testutil.ToFloat64(requests)
+		send("/games/1")
+		send("/games/2")
+		convey.So(testutil.ToFloat64(requests)-before, convey.ShouldEqual, 2)
+
+		unmatched := metrics.HTTPRequests.WithLabelValues("GET", "unmatched", "404")
+		before = testutil.ToFloat64(unmatched)
+		send("/nowhere/1")
+		convey.So(testutil.ToFloat64(unmatched)-before, convey.ShouldEqual, 1)
+	})
+
+	convey.Convey("Failed authorizations should be counted and stop the request", t, func() {
+		failures := metrics.AuthFailures.WithLabelValues(string(utilErrs.ReasonTokenMissing))
+		before := testutil.ToFloat64(failures)
+		convey.So(send("/private"), convey.ShouldEqual, http.StatusUnauthorized)
+		convey.So(reached, convey.ShouldBeFalse)
+		convey.So(testutil.ToFloat64(failures)-before, convey.ShouldEqual, 1)
+	})
+}
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Tracing)
+	router.GET("/games/:id", func(ctx *gin.Context) {
+		_, span := otel.Tracer("test").Start(ctx.Request.Context(), "handler")
+		span.End()
+		ctx.Status(http.StatusOK)
+	})
+
+	convey.Convey("Requests should continue traces of clients", t, func() {
+		req := httptest.NewRequest("GET", "/games/7", nil)
+		req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		spans := recorder.Ended()
+		convey.So(spans, convey.ShouldHaveLength, 2)
+		handler, request := spans[0], spans[1]
+		convey.So(request.Name(), convey.ShouldEqual, "GET /games/:id")
+		convey.So(request.SpanKind(), convey.ShouldEqual, trace.SpanKindServer)
+		convey.So(request.SpanContext().TraceID().String(), convey.ShouldEqual, "4bf92f3577b34da6a3ce929d0e0e4736")
+		convey.So(request.Parent().SpanID().String(), convey.ShouldEqual, "00f067aa0ba902b7")
+		convey.So(request.Attributes(), convey.ShouldContain, semconv.HTTPStatusCodeKey.Int(http.StatusOK))
+		convey.So(handler.Parent().SpanID(), convey.ShouldEqual, request.SpanContext().SpanID())
+	})
+}
@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"io"
+	"time"
+
+	"github.com/br3w0r/gamelist-backend/service"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// EVENTS_HEARTBEAT keeps idle streams open through proxies
+	EVENTS_HEARTBEAT = 25 * time.Second
+)
+
+type EventsController interface {
+	// Stream sends events of the profile of the nickname param, or of the
+	// authorized user without the param, as server-sent events
+	Stream(ctx *gin.Context)
+}
+
+type eventsController struct {
+	gamelistService service.GameListService
+	events          service.EventBus
+	heartbeat       time.Duration
+}
+
+func NewEventsController(gamelistService service.GameListService, events service.EventBus) EventsController {
+	return &eventsController{
+		gamelistService: gamelistService,
+		events:          events,
+		heartbeat:       EVENTS_HEARTBEAT,
+	}
+}
+
+func (c *eventsController) Stream(ctx *gin.Context) {
+	nickname := ctx.Param("nickname")
+	if nickname == "" {
+		nickname = ctx.MustGet("nickname").(string)
+	} else if _, err := c.gamelistService.GetProfileInfo(ctx.Request.Context(), nickname); err != nil {
+		ErrorSender(ctx, err)
+		return
+	}
+
+	events, unsubscribe := c.events.Subscribe(nickname)
+	defer unsubscribe()
+
+	heartbeat := time.NewTicker(c.heartbeat)
+	defer heartbeat.Stop()
+
+	// Dead clients are noticed by failed heartbeats instead
+	liftWriteDeadline(ctx)
+
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.SSEvent("ready", gin.H{"nickname": nickname})
+	ctx.Writer.Flush()
+
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			ctx.SSEvent(event.Type, event)
+			return true
+		case <-heartbeat.C:
+			_, err := io.WriteString(w, ": heartbeat\n\n")
+			return err == nil
+		case <-ctx.Request.Context().Done():
+			return false
+		}
+	})
+}
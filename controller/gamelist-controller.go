@@ -1,21 +1,27 @@
 package controller
 
 import (
+	"context"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"strings"
 
 	"github.com/br3w0r/gamelist-backend/entity"
 	"github.com/br3w0r/gamelist-backend/service"
 	utilErrs "github.com/br3w0r/gamelist-backend/util/errors"
+	"github.com/br3w0r/gamelist-backend/util/metrics"
 	"github.com/gin-gonic/gin"
 )
 
 type GameListController interface {
 	GetAllGames(ctx *gin.Context)
 	GetAllGamesTyped(ctx *gin.Context)
+	QueryGames(ctx *gin.Context)
 	GetMyGameList(ctx *gin.Context)
 	SearchGames(ctx *gin.Context)
 	GameDetails(ctx *gin.Context)
+	GetGame(ctx *gin.Context)
 
 	AcquireJWTPair(ctx *gin.Context)
 	RefreshJWTPair(ctx *gin.Context)
@@ -23,52 +29,46 @@ type GameListController interface {
 	DeleteAllRefreshTokens(ctx *gin.Context)
 	Authorized(ctx *gin.Context)
 
-	// Will be replaced with gRPC calls
-	PostGame(ctx *gin.Context)
-
-	PostListType(ctx *gin.Context)
 	GetAllListTypes(ctx *gin.Context)
 	ListGame(ctx *gin.Context)
+	PutListGame(ctx *gin.Context)
+	UnlistGame(ctx *gin.Context)
+	ImportGameList(ctx *gin.Context)
+	ExportGameList(ctx *gin.Context)
 
-	PostGenre(ctx *gin.Context)
 	GetAllGenres(ctx *gin.Context)
 
-	PostPlatform(ctx *gin.Context)
 	GetAllPlatforms(ctx *gin.Context)
 
 	PostProfile(ctx *gin.Context)
 	GetAllProfiles(ctx *gin.Context)
 
-	PostSocialType(ctx *gin.Context)
+	ExportAccount(ctx *gin.Context)
+	DeleteAccount(ctx *gin.Context)
+	RestoreAccount(ctx *gin.Context)
+
 	GetAllSocialtypes(ctx *gin.Context)
 }
 
 type gameListController struct {
 	gamelistService service.GameListService
 	jwtService      service.JWTService
+	accountService  service.AccountService
 }
 
-func NewGameListController(gamelistService service.GameListService, jwtService service.JWTService) GameListController {
+func NewGameListController(gamelistService service.GameListService, jwtService service.JWTService, accountService service.AccountService) GameListController {
 	return &gameListController{
 		gamelistService: gamelistService,
 		jwtService:      jwtService,
+		accountService:  accountService,
 	}
 }
 
-func (c *gameListController) PostGame(ctx *gin.Context) {
-	GenericPost(ctx, &entity.GameProperties{}, c.gamelistService.SaveGame)
-}
-
 func (c *gameListController) GetAllGames(ctx *gin.Context) {
-	games, err := c.gamelistService.GetAllGames()
-	if err != nil {
-		ErrorSender(ctx, err)
-		return
-	}
-
-	ctx.JSON(http.StatusOK, games)
+	List(c.gamelistService.GetGames)(ctx)
 }
 
+// GetAllGamesTyped responds with a batch of games to v0 clients, which predate pages
 func (c *gameListController) GetAllGamesTyped(ctx *gin.Context) {
 	nickname := ctx.MustGet("nickname").(string)
 	var request entity.GameBatchRequest
@@ -77,20 +77,36 @@ func (c *gameListController) GetAllGamesTyped(ctx *gin.Context) {
 		ErrorSender(ctx, utilErrs.JSONParseErr(err))
 		return
 	}
+	page := request.PageRequest()
+	if err := page.Validate(); err != nil {
+		ErrorSender(ctx, err)
+		return
+	}
 
-	games, err := c.gamelistService.GetAllGamesTyped(nickname, request.Last, request.BatchSize)
+	games, err := c.gamelistService.GetAllGamesTyped(ctx.Request.Context(), nickname, entity.GamesRequest{PageRequest: page})
 	if err != nil {
 		ErrorSender(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, games)
+	ctx.JSON(http.StatusOK, games.Items)
 }
 
-func (c *gameListController) GetMyGameList(ctx *gin.Context) {
+// QueryGames responds with a page of games of the query
+func (c *gameListController) QueryGames(ctx *gin.Context) {
 	nickname := ctx.MustGet("nickname").(string)
+	var request entity.GamesRequest
+	err := ctx.ShouldBindQuery(&request)
+	if err != nil {
+		ErrorSender(ctx, utilErrs.QueryParseErr(err))
+		return
+	}
+	if err := request.Validate(); err != nil {
+		ErrorSender(ctx, err)
+		return
+	}
 
-	games, err := c.gamelistService.GetUserGameList(nickname)
+	games, err := c.gamelistService.GetAllGamesTyped(ctx.Request.Context(), nickname, request)
 	if err != nil {
 		ErrorSender(ctx, err)
 		return
@@ -99,6 +115,14 @@ func (c *gameListController) GetMyGameList(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, games)
 }
 
+func (c *gameListController) GetMyGameList(ctx *gin.Context) {
+	nickname := ctx.MustGet("nickname").(string)
+
+	List(func(ctx context.Context, page entity.PageRequest) (*entity.Page[entity.TypedGameListProperties], error) {
+		return c.gamelistService.GetUserGameList(ctx, nickname, page)
+	})(ctx)
+}
+
 func (c *gameListController) SearchGames(ctx *gin.Context) {
 	var request entity.SearchRequest
 	err := ctx.ShouldBindJSON(&request)
@@ -107,7 +131,7 @@ func (c *gameListController) SearchGames(ctx *gin.Context) {
 		return
 	}
 
-	games, err := c.gamelistService.SearchGames(request.Name)
+	games, err := c.gamelistService.SearchGames(ctx.Request.Context(), request.Name)
 	if err != nil {
 		ErrorSender(ctx, err)
 		return
@@ -125,7 +149,7 @@ func (c *gameListController) GameDetails(ctx *gin.Context) {
 		return
 	}
 
-	gameDetails, err := c.gamelistService.GetGameDetails(nickname, request.Id)
+	gameDetails, err := c.gamelistService.GetGameDetails(ctx.Request.Context(), nickname, request.Id)
 	if err != nil {
 		ErrorSender(ctx, err)
 		return
@@ -134,18 +158,17 @@ func (c *gameListController) GameDetails(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gameDetails)
 }
 
-func (c *gameListController) PostListType(ctx *gin.Context) {
-	GenericPost(ctx, &entity.ListType{}, c.gamelistService.CreateListType)
+// GetGame responds with details of the game of the :id path parameter
+func (c *gameListController) GetGame(ctx *gin.Context) {
+	nickname := ctx.MustGet("nickname").(string)
+
+	Get(func(ctx context.Context, id uint64) (*entity.GameDetailsResponse, error) {
+		return c.gamelistService.GetGameDetails(ctx, nickname, id)
+	})(ctx)
 }
 
 func (c *gameListController) GetAllListTypes(ctx *gin.Context) {
-	types, err := c.gamelistService.GetAllListTypes()
-	if err != nil {
-		ErrorSender(ctx, err)
-		return
-	}
-
-	ctx.JSON(http.StatusOK, types)
+	List(c.gamelistService.GetAllListTypes)(ctx)
 }
 
 func (c *gameListController) ListGame(ctx *gin.Context) {
@@ -158,7 +181,7 @@ func (c *gameListController) ListGame(ctx *gin.Context) {
 		return
 	}
 
-	err = c.gamelistService.ListGame(nickname, gameList.GameId, gameList.ListType)
+	err = c.gamelistService.ListGame(ctx.Request.Context(), nickname, gameList.GameId, gameList.ListType)
 	if err != nil {
 		ErrorSender(ctx, err)
 		return
@@ -167,46 +190,151 @@ func (c *gameListController) ListGame(ctx *gin.Context) {
 	ResponseOK(ctx)
 }
 
-func (c *gameListController) PostGenre(ctx *gin.Context) {
-	GenericPost(ctx, &entity.Genre{}, c.gamelistService.SaveGenre)
+// PutListGame sets the list type of the game of the :id path parameter
+func (c *gameListController) PutListGame(ctx *gin.Context) {
+	nickname := ctx.MustGet("nickname").(string)
+
+	Update(func(ctx context.Context, id uint64, request *entity.ListTypeRequest) error {
+		return c.gamelistService.ListGame(ctx, nickname, id, request.ListType)
+	})(ctx)
+}
+
+// UnlistGame removes the game of the :id path parameter from the list
+func (c *gameListController) UnlistGame(ctx *gin.Context) {
+	nickname := ctx.MustGet("nickname").(string)
+
+	Delete(func(ctx context.Context, id uint64) error {
+		return c.gamelistService.ListGame(ctx, nickname, id, 0)
+	})(ctx)
 }
 
-func (c *gameListController) GetAllGenres(ctx *gin.Context) {
-	genres, err := c.gamelistService.GetAllGenres()
+func (c *gameListController) ImportGameList(ctx *gin.Context) {
+	nickname := ctx.MustGet("nickname").(string)
+
+	var request entity.ImportRequest
+	err := ctx.ShouldBindQuery(&request)
+	if err != nil {
+		ErrorSender(ctx, utilErrs.QueryParseErr(err))
+		return
+	}
+
+	body := http.MaxBytesReader(ctx.Writer, ctx.Request.Body, importBodyLimit)
+	entries, err := service.DecodeListEntries(listFormat(ctx, request.Format), body)
 	if err != nil {
 		ErrorSender(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, genres)
-}
+	result, err := c.gamelistService.ImportGameList(ctx.Request.Context(), nickname, entries, request.DryRun)
+	if err != nil {
+		ErrorSender(ctx, err)
+		return
+	}
 
-func (c *gameListController) PostPlatform(ctx *gin.Context) {
-	GenericPost(ctx, &entity.Platform{}, c.gamelistService.SavePlatform)
+	ctx.JSON(http.StatusOK, result)
 }
 
-func (c *gameListController) GetAllPlatforms(ctx *gin.Context) {
-	platforms, err := c.gamelistService.GetAllPlatforms()
+func (c *gameListController) ExportGameList(ctx *gin.Context) {
+	nickname := ctx.MustGet("nickname").(string)
+
+	var request entity.ExportRequest
+	err := ctx.ShouldBindQuery(&request)
+	if err != nil {
+		ErrorSender(ctx, utilErrs.QueryParseErr(err))
+		return
+	}
+
+	format := request.Format
+	if format == "" {
+		format = entity.ListFormatJSON
+	}
+	contentType, ok := listContentTypes[format]
+	if !ok {
+		ErrorSender(ctx, utilErrs.Newf(utilErrs.BadInput, nil, "unsupported list format \"%s\": expected csv, json or xml", format).WithReason(utilErrs.ReasonInvalidListFile))
+		return
+	}
+
+	entries, err := c.gamelistService.ExportGameList(ctx.Request.Context(), nickname)
 	if err != nil {
 		ErrorSender(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, platforms)
+	ctx.Header("Content-Type", contentType)
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"gamelist.%s\"", format))
+	ctx.Status(http.StatusOK)
+	if err := service.EncodeListEntries(format, ctx.Writer, nickname, entries); err != nil {
+		httpLog.ErrorContext(requestContext(ctx), "failed to export game list", "error", err)
+	}
+}
+
+func (c *gameListController) GetAllGenres(ctx *gin.Context) {
+	List(c.gamelistService.GetAllGenres)(ctx)
+}
+
+func (c *gameListController) GetAllPlatforms(ctx *gin.Context) {
+	List(c.gamelistService.GetAllPlatforms)(ctx)
 }
 
 func (c *gameListController) PostProfile(ctx *gin.Context) {
-	GenericPost(ctx, &entity.Profile{}, c.gamelistService.CreateProfile)
+	Create(c.gamelistService.CreateProfile)(ctx)
 }
 
 func (c *gameListController) GetAllProfiles(ctx *gin.Context) {
-	profiles, err := c.gamelistService.GetAllProfiles()
+	List(c.gamelistService.GetAllProfiles)(ctx)
+}
+
+func (c *gameListController) ExportAccount(ctx *gin.Context) {
+	nickname := ctx.MustGet("nickname").(string)
+
+	archive, err := c.accountService.ExportAccount(ctx.Request.Context(), nickname)
+	if err != nil {
+		ErrorSender(ctx, err)
+		return
+	}
+
+	ctx.Header("Content-Disposition", "attachment; filename=\"gamelist-account.json\"")
+	ctx.JSON(http.StatusOK, archive)
+}
+
+func (c *gameListController) DeleteAccount(ctx *gin.Context) {
+	nickname := ctx.MustGet("nickname").(string)
+
+	var request entity.AccountDeletionRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ErrorSender(ctx, utilErrs.JSONParseErr(err))
+		return
+	}
+
+	_, err = c.gamelistService.CheckLogin(ctx.Request.Context(), entity.LoginProfile{
+		Nickname: nickname,
+		Password: request.Password,
+	})
+	if err != nil {
+		ErrorSender(ctx, err)
+		return
+	}
+
+	deletion, err := c.accountService.RequestDeletion(ctx.Request.Context(), nickname)
+	if err != nil {
+		ErrorSender(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, deletion)
+}
+
+func (c *gameListController) RestoreAccount(ctx *gin.Context) {
+	nickname := ctx.MustGet("nickname").(string)
+
+	err := c.accountService.CancelDeletion(ctx.Request.Context(), nickname)
 	if err != nil {
 		ErrorSender(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, profiles)
+	ResponseOK(ctx)
 }
 
 func (c *gameListController) AcquireJWTPair(ctx *gin.Context) {
@@ -217,18 +345,20 @@ func (c *gameListController) AcquireJWTPair(ctx *gin.Context) {
 		return
 	}
 
-	profile, err := c.gamelistService.CheckLogin(login)
+	profile, err := c.gamelistService.CheckLogin(ctx.Request.Context(), login)
 	if err != nil {
+		countAuthFailure(err)
 		ErrorSender(ctx, err)
 		return
 	}
 
-	pair, err := c.jwtService.GenerateTokens(profile.Nickname)
+	pair, err := c.jwtService.GenerateTokens(ctx.Request.Context(), profile.Nickname)
 	if err != nil {
 		ErrorSender(ctx, err)
 		return
 	}
 
+	metrics.TokensIssued.WithLabelValues("login").Inc()
 	ctx.JSON(http.StatusOK, pair)
 }
 
@@ -240,12 +370,16 @@ func (c *gameListController) RefreshJWTPair(ctx *gin.Context) {
 		return
 	}
 
-	pair, err := c.jwtService.RefreshTokens(refresh.RefreshToken)
+	pair, err := c.jwtService.RefreshTokens(ctx.Request.Context(), refresh.RefreshToken)
 	if err != nil {
+		metrics.TokenRefreshes.WithLabelValues("failure").Inc()
+		countAuthFailure(err)
 		ErrorSender(ctx, err)
 		return
 	}
 
+	metrics.TokenRefreshes.WithLabelValues("success").Inc()
+	metrics.TokensIssued.WithLabelValues("refresh").Inc()
 	ctx.JSON(http.StatusOK, pair)
 }
 
@@ -257,7 +391,7 @@ func (c *gameListController) RevokeRefreshToken(ctx *gin.Context) {
 		return
 	}
 
-	err = c.jwtService.RevokeRefreshToken(request.RefreshToken)
+	err = c.jwtService.RevokeRefreshToken(ctx.Request.Context(), request.RefreshToken)
 	if err != nil {
 		ErrorSender(ctx, err)
 		return
@@ -268,7 +402,7 @@ func (c *gameListController) RevokeRefreshToken(ctx *gin.Context) {
 
 func (c *gameListController) DeleteAllRefreshTokens(ctx *gin.Context) {
 	nickname := ctx.MustGet("nickname").(string)
-	err := c.jwtService.DeleteAllUserRefreshTokens(nickname)
+	err := c.jwtService.DeleteAllUserRefreshTokens(ctx.Request.Context(), nickname)
 	if err != nil {
 		ErrorSender(ctx, err)
 		return
@@ -281,36 +415,43 @@ func (c *gameListController) Authorized(ctx *gin.Context) {
 	var token string
 	authHeader, ok := ctx.Request.Header["Authorization"]
 	if !ok {
-		ErrorSender(ctx, utilErrs.New(utilErrs.Unauthorized, nil, "no authorization header provided"))
+		unauthorized(ctx, utilErrs.New(utilErrs.Unauthorized, nil, "no authorization header provided").WithReason(utilErrs.ReasonTokenMissing))
 		return
 	}
 
 	list := strings.Split(authHeader[0], " ")
 	if len(list) != 2 || list[0] != "Bearer" {
-		ErrorSender(ctx, utilErrs.New(utilErrs.Unauthorized, nil, "wrong authorization header format"))
+		unauthorized(ctx, utilErrs.New(utilErrs.Unauthorized, nil, "wrong authorization header format").WithReason(utilErrs.ReasonTokenInvalid))
 		return
 	}
 
 	token = list[1]
 	nickname, err := c.jwtService.Authenticate(token)
 	if err != nil {
-		ErrorSender(ctx, utilErrs.New(utilErrs.Unauthorized, nil, "authentication failed"))
+		unauthorized(ctx, utilErrs.New(utilErrs.Unauthorized, nil, "authentication failed").WithReason(utilErrs.ReasonTokenInvalid))
 		return
 	}
 
 	ctx.Set("nickname", nickname)
+	logWith(ctx, slog.String("nickname", nickname))
+}
+
+func (c *gameListController) GetAllSocialtypes(ctx *gin.Context) {
+	List(c.gamelistService.GetAllSocialTypes)(ctx)
 }
 
-func (c *gameListController) PostSocialType(ctx *gin.Context) {
-	GenericPost(ctx, &entity.SocialType{}, c.gamelistService.SaveSocialType)
+// unauthorized sends the error of a failed authorization and stops the handlers
+// of the request
+func unauthorized(ctx *gin.Context, err *utilErrs.Error) {
+	countAuthFailure(err)
+	ErrorSender(ctx, err)
+	ctx.Abort()
 }
 
-func (c *gameListController) GetAllSocialtypes(ctx *gin.Context) {
-	types, err := c.gamelistService.GetAllSocialTypes()
-	if err != nil {
-		ErrorSender(ctx, err)
-		return
+// countAuthFailure counts failures of clients to log in or to authorize. Failures
+// of the server aren't counted.
+func countAuthFailure(err error) {
+	if utilErr, ok := err.(*utilErrs.Error); ok && utilErr.Code() != utilErrs.Internal {
+		metrics.AuthFailures.WithLabelValues(string(utilErr.Reason())).Inc()
 	}
-
-	ctx.JSON(http.StatusOK, types)
 }
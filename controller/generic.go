@@ -0,0 +1,142 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/br3w0r/gamelist-backend/entity"
+	utilErrs "github.com/br3w0r/gamelist-backend/util/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// Generic handlers wrap standard service functions. Bodies are validated by their
+// binding tags and errors are sent with ErrorSender. Functions get the context of the
+// request, so their queries are canceled when the client goes away. Get and List respond with
+// the object or the page of objects, other handlers respond with ResponseOK.
+
+const (
+	// bareListsKey is set on routes which predate pages and list items alone
+	bareListsKey = "bare_lists"
+)
+
+// BareLists makes List respond with the items of the page alone, as lists were sent
+// before they were paginated
+func BareLists(ctx *gin.Context) {
+	ctx.Set(bareListsKey, true)
+}
+
+// Create binds the JSON body to T and passes it to f
+func Create[T any](f func(ctx context.Context, obj T) error) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var obj T
+		if err := ctx.ShouldBindJSON(&obj); err != nil {
+			ErrorSender(ctx, utilErrs.JSONParseErr(err))
+			return
+		}
+
+		if err := f(ctx.Request.Context(), obj); err != nil {
+			ErrorSender(ctx, err)
+			return
+		}
+
+		ResponseOK(ctx)
+	}
+}
+
+// Get responds with the object of the :id path parameter
+func Get[T any](f func(ctx context.Context, id uint64) (*T, error)) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := paramID(ctx)
+		if err != nil {
+			ErrorSender(ctx, err)
+			return
+		}
+
+		obj, err := f(ctx.Request.Context(), id)
+		if err != nil {
+			ErrorSender(ctx, err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, obj)
+	}
+}
+
+// List responds with a page of objects of the cursor and limit query parameters
+func List[T any](f func(ctx context.Context, page entity.PageRequest) (*entity.Page[T], error)) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var request entity.PageRequest
+		if err := ctx.ShouldBindQuery(&request); err != nil {
+			ErrorSender(ctx, utilErrs.QueryParseErr(err))
+			return
+		}
+		if err := request.Validate(); err != nil {
+			ErrorSender(ctx, err)
+			return
+		}
+
+		page, err := f(ctx.Request.Context(), request)
+		if err != nil {
+			ErrorSender(ctx, err)
+			return
+		}
+
+		if ctx.GetBool(bareListsKey) {
+			ctx.JSON(http.StatusOK, page.Items)
+			return
+		}
+		ctx.JSON(http.StatusOK, page)
+	}
+}
+
+// Update binds the JSON body to T and passes it to f with the :id path parameter
+func Update[T any](f func(ctx context.Context, id uint64, obj *T) error) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := paramID(ctx)
+		if err != nil {
+			ErrorSender(ctx, err)
+			return
+		}
+
+		var obj T
+		if err := ctx.ShouldBindJSON(&obj); err != nil {
+			ErrorSender(ctx, utilErrs.JSONParseErr(err))
+			return
+		}
+
+		if err := f(ctx.Request.Context(), id, &obj); err != nil {
+			ErrorSender(ctx, err)
+			return
+		}
+
+		ResponseOK(ctx)
+	}
+}
+
+// Delete passes the :id path parameter to f
+func Delete(f func(ctx context.Context, id uint64) error) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := paramID(ctx)
+		if err != nil {
+			ErrorSender(ctx, err)
+			return
+		}
+
+		if err := f(ctx.Request.Context(), id); err != nil {
+			ErrorSender(ctx, err)
+			return
+		}
+
+		ResponseOK(ctx)
+	}
+}
+
+func paramID(ctx *gin.Context) (uint64, error) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return 0, utilErrs.Newf(utilErrs.BadInput, err, "wrong id \"%s\"", ctx.Param("id"))
+	}
+
+	return id, nil
+}
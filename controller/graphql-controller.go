@@ -0,0 +1,38 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/br3w0r/gamelist-backend/graph"
+	utilErrs "github.com/br3w0r/gamelist-backend/util/errors"
+	"github.com/gin-gonic/gin"
+)
+
+type GraphQLController interface {
+	// Query executes a GraphQL request of an authorized user
+	Query(ctx *gin.Context)
+}
+
+type graphQLController struct {
+	executor graph.Executor
+}
+
+func NewGraphQLController(executor graph.Executor) GraphQLController {
+	return &graphQLController{
+		executor: executor,
+	}
+}
+
+func (c *graphQLController) Query(ctx *gin.Context) {
+	nickname := ctx.MustGet("nickname").(string)
+
+	var request graph.Request
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ErrorSender(ctx, utilErrs.JSONParseErr(err))
+		return
+	}
+
+	// Errors of queries are sent in the result as GraphQL clients expect
+	ctx.JSON(http.StatusOK, c.executor.Execute(ctx.Request.Context(), nickname, request))
+}
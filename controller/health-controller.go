@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/br3w0r/gamelist-backend/entity"
+	"github.com/br3w0r/gamelist-backend/service"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	HEALTHZ_PATH = "/healthz"
+	READYZ_PATH  = "/readyz"
+)
+
+type HealthController interface {
+	// Healthz responds with 200 while the process serves requests
+	Healthz(ctx *gin.Context)
+	// Readyz responds with 200 if the server may get traffic and with 503 if a check
+	// failed or the server is draining. Both have the report of the checks.
+	Readyz(ctx *gin.Context)
+}
+
+type healthController struct {
+	healthService service.HealthService
+}
+
+func NewHealthController(healthService service.HealthService) HealthController {
+	return &healthController{
+		healthService: healthService,
+	}
+}
+
+func (c *healthController) Healthz(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, c.healthService.Liveness())
+}
+
+func (c *healthController) Readyz(ctx *gin.Context) {
+	report := c.healthService.Readiness(ctx.Request.Context())
+
+	status := http.StatusOK
+	if report.Status != entity.HealthOK {
+		status = http.StatusServiceUnavailable
+	}
+	ctx.Header("Cache-Control", "no-store")
+	ctx.JSON(status, report)
+}
@@ -1,21 +1,222 @@
 package controller
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
 	"reflect"
+	"regexp"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/br3w0r/gamelist-backend/entity"
 	utilErrs "github.com/br3w0r/gamelist-backend/util/errors"
 	utilLogger "github.com/br3w0r/gamelist-backend/util/logger"
+	"github.com/br3w0r/gamelist-backend/util/metrics"
+	"github.com/br3w0r/gamelist-backend/util/tracing"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	importBodyLimit = 5 << 20
+
+	REQUEST_ID_HEADER = "X-Request-ID"
+	ERROR_DOMAIN      = "gamelist"
 )
 
 var (
-	errType reflect.Type = errorType()
+	// Request ids of clients are kept if they look like ids
+	requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+	httpLog  = utilLogger.For(utilLogger.SubsystemHTTP)
+	adminLog = utilLogger.For(utilLogger.SubsystemAdmin)
+
+	httpTracer = tracing.Tracer("controller")
 )
 
-const (
-	errPost = "failed to process post request"
+func init() {
+	// Field errors are reported with names of fields in requests rather than in structs
+	if validate, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		validate.RegisterTagNameFunc(requestFieldName)
+	}
+}
+
+// requestFieldName is the json or form name of the field
+func requestFieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name := strings.Split(field.Tag.Get(tag), ",")[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+
+	return field.Name
+}
+
+// RequestID takes the id of the request from the X-Request-ID header or generates
+// a new one. The id is sent back in the header and in bodies of errors, and it's
+// attached to log lines of the request.
+func RequestID(ctx *gin.Context) {
+	requestID := ctx.GetHeader(REQUEST_ID_HEADER)
+	if !requestIDPattern.MatchString(requestID) {
+		id := make([]byte, 16)
+		rand.Read(id) //nolint:errcheck
+		requestID = hex.EncodeToString(id)
+	}
+
+	ctx.Set("request_id", requestID)
+	ctx.Header(REQUEST_ID_HEADER, requestID)
+	logWith(ctx, slog.String("request_id", requestID))
+}
+
+// AccessLog logs served requests. Server errors are logged as errors and client
+// errors as warnings, probes of health are debug lines.
+func AccessLog(ctx *gin.Context) {
+	start := time.Now()
+	ctx.Next()
+
+	status := ctx.Writer.Status()
+	level := slog.LevelInfo
+	switch {
+	case ctx.FullPath() == HEALTHZ_PATH || ctx.FullPath() == READYZ_PATH:
+		// Probes come every few seconds and failed checks are logged by the health service
+		level = slog.LevelDebug
+	case status >= http.StatusInternalServerError:
+		level = slog.LevelError
+	case status >= http.StatusBadRequest:
+		level = slog.LevelWarn
+	}
+
+	httpLog.LogAttrs(requestContext(ctx), level, "request",
+		slog.String("method", ctx.Request.Method),
+		slog.String("path", ctx.Request.URL.Path),
+		slog.Int("status", status),
+		slog.Duration("latency", time.Since(start)),
+		slog.String("client_ip", ctx.ClientIP()),
+		slog.Int("size", ctx.Writer.Size()),
+	)
+}
+
+// Metrics counts served requests and observes their latencies by route and status
+func Metrics(ctx *gin.Context) {
+	start := time.Now()
+	metrics.HTTPRequestsInFlight.Inc()
+	defer metrics.HTTPRequestsInFlight.Dec()
+
+	ctx.Next()
+
+	// Unmatched paths would give a label per path
+	route := ctx.FullPath()
+	if route == "" {
+		route = "unmatched"
+	}
+	status := strconv.Itoa(ctx.Writer.Status())
+	metrics.HTTPRequests.WithLabelValues(ctx.Request.Method, route, status).Inc()
+	metrics.HTTPRequestDuration.WithLabelValues(ctx.Request.Method, route, status).Observe(time.Since(start).Seconds())
+}
+
+// Tracing starts the span of the request, which continues the trace of the client if
+// its headers have one. Ids of traces are attached to log lines of requests.
+func Tracing(ctx *gin.Context) {
+	parent := otel.GetTextMapPropagator().Extract(ctx.Request.Context(), propagation.HeaderCarrier(ctx.Request.Header))
+
+	route := ctx.FullPath()
+	if route == "" {
+		route = "unmatched"
+	}
+	reqCtx, span := httpTracer.Start(parent, ctx.Request.Method+" "+route,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.HTTPMethodKey.String(ctx.Request.Method),
+			semconv.HTTPRouteKey.String(route),
+			semconv.HTTPTargetKey.String(ctx.Request.URL.Path),
+			semconv.HTTPClientIPKey.String(ctx.ClientIP()),
+		),
+	)
+	defer span.End()
+
+	ctx.Request = ctx.Request.WithContext(reqCtx)
+	if span.SpanContext().IsValid() {
+		logWith(ctx, slog.String("trace_id", span.SpanContext().TraceID().String()))
+	}
+
+	ctx.Next()
+
+	status := ctx.Writer.Status()
+	span.SetAttributes(semconv.HTTPStatusCodeKey.Int(status))
+	if status >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, http.StatusText(status))
+	}
+}
+
+// Recovery sends 500 on panics of handlers and logs them with their stacks
+var Recovery = gin.CustomRecoveryWithWriter(io.Discard, func(ctx *gin.Context, recovered interface{}) {
+	httpLog.ErrorContext(requestContext(ctx), "panic", "panic", recovered, "stack", string(debug.Stack()))
+	ctx.AbortWithStatus(http.StatusInternalServerError)
+})
+
+// logWith attaches the attributes to log lines of the request
+func logWith(ctx *gin.Context, attrs ...slog.Attr) {
+	if ctx.Request != nil {
+		ctx.Request = ctx.Request.WithContext(utilLogger.WithAttrs(ctx.Request.Context(), attrs...))
+	}
+}
+
+// requestContext is the context of the request with its log attributes
+func requestContext(ctx *gin.Context) context.Context {
+	if ctx.Request == nil {
+		return context.Background()
+	}
+
+	return ctx.Request.Context()
+}
+
+type responseWriterKey struct{}
+
+// KeepResponseWriter passes the writer of net/http to handlers in the context of requests.
+// Writers of gin don't unwrap to it, so http.ResponseController can't reach it otherwise.
+func KeepResponseWriter(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), responseWriterKey{}, w)))
+	})
+}
+
+// liftWriteDeadline lets streams outlive the write timeout of the server
+func liftWriteDeadline(ctx *gin.Context) {
+	w, ok := requestContext(ctx).Value(responseWriterKey{}).(http.ResponseWriter)
+	if !ok {
+		return
+	}
+
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		httpLog.WarnContext(requestContext(ctx), "failed to lift write deadline", "error", err)
+	}
+}
+
+var (
+	listContentTypes = map[string]string{
+		entity.ListFormatCSV:  "text/csv; charset=utf-8",
+		entity.ListFormatJSON: "application/json; charset=utf-8",
+		entity.ListFormatXML:  "application/xml; charset=utf-8",
+	}
 )
 
 func ErrorSender(ctx *gin.Context, err error) {
@@ -26,62 +227,80 @@ func ErrorSender(ctx *gin.Context, err error) {
 		utilErr = utilErrs.New(utilErrs.Internal, err, "unknown error")
 	}
 
+	utilErr = utilErr.WithRequestID(ctx.GetString("request_id"))
 	data := utilErr.JSON(true)
 	ctx.Data(utilErr.Code().ToHTTP(), "application/json", data)
 
 	if utilErr.Code() == utilErrs.Internal {
-		utilLogger.Logger.Write([]byte(fmt.Sprintf("INTERNAL ERROR: \"%s\"; cause: %v\n", utilErr.Error(), utilErr.Cause()))) //nolint:errcheck
+		httpLog.ErrorContext(requestContext(ctx), "internal error", "error", utilErr.Error(), "cause", utilErr.Cause())
 	}
 }
 
-func ResponseOK(ctx *gin.Context) {
-	ctx.JSON(http.StatusOK, gin.H{
-		"message": "ok",
-	})
-}
+// GRPCError converts err to a gRPC status error. Internal errors are logged.
+func GRPCError(err error) error {
+	var utilErr *utilErrs.Error
+	if castErr, ok := err.(*utilErrs.Error); ok {
+		utilErr = castErr
+	} else {
+		utilErr = utilErrs.New(utilErrs.Internal, err, "unknown error")
+	}
 
-func errorType() reflect.Type {
-	var err error
-	return reflect.ValueOf(&err).Elem().Type()
-}
+	if utilErr.Code() == utilErrs.Internal {
+		adminLog.Error("internal error", "error", utilErr.Error(), "cause", utilErr.Cause())
+	}
 
-// Generic POST function.
-// <obj> of type *T - pointer to object to be saved;
-// <f> of type func(T) error - standard service save function
-func GenericPost(ctx *gin.Context, obj interface{}, f interface{}) {
-	// Check if f is function
-	fv := reflect.ValueOf(f)
-	if fv.Kind() != reflect.Func {
-		err := fmt.Errorf("the kind of <f> is %v when must be reflect.Func", fv.Kind())
-		ErrorSender(ctx, utilErrs.New(utilErrs.Internal, err, errPost))
-		return
+	// Reasons and field errors are sent as details
+	info := &errdetails.ErrorInfo{Reason: string(utilErr.Reason()), Domain: ERROR_DOMAIN}
+	st, err := status.New(utilErr.GRPCCode(), utilErr.Error()).WithDetails(info)
+	if err != nil {
+		return status.Error(utilErr.GRPCCode(), utilErr.Error())
+	}
+	if fields := utilErr.Fields(); len(fields) > 0 {
+		violations := make([]*errdetails.BadRequest_FieldViolation, len(fields))
+		for i := range fields {
+			violations[i] = &errdetails.BadRequest_FieldViolation{Field: fields[i].Field, Description: fields[i].Message}
+		}
+		if withFields, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations}); err == nil {
+			st = withFields
+		}
 	}
 
-	// obj's value and obj's and f's types initialization
-	objVal := reflect.ValueOf(obj).Elem()
-	objType := objVal.Type()
-	fType := reflect.TypeOf(f)
+	return st.Err()
+}
 
-	// Check if f's type equals standard service save function
-	standardFunc := reflect.FuncOf([]reflect.Type{objType}, []reflect.Type{errType}, false)
-	if fType != standardFunc {
-		err := fmt.Errorf("<GenericPost>: the type of <f> is %v while it must be %v", fType, standardFunc)
-		ErrorSender(ctx, utilErrs.New(utilErrs.Internal, err, errPost))
-		return
+// Deprecated marks responses of deprecated routes with Deprecation (RFC 9745) and
+// Sunset (RFC 8594) headers and links the successor of the routes if it's set
+func Deprecated(deprecatedAt time.Time, sunset time.Time, successor string) gin.HandlerFunc {
+	deprecation := fmt.Sprint("@", deprecatedAt.Unix())
+	sunsetDate := sunset.UTC().Format(http.TimeFormat)
+
+	return func(ctx *gin.Context) {
+		ctx.Header("Deprecation", deprecation)
+		if !sunset.IsZero() {
+			ctx.Header("Sunset", sunsetDate)
+		}
+		if successor != "" {
+			ctx.Header("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", successor))
+		}
 	}
+}
 
-	// Do the job
-	err := ctx.ShouldBindJSON(obj)
-	if err != nil {
-		ErrorSender(ctx, utilErrs.JSONParseErr(err))
-		return
+func ResponseOK(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, entity.MessageResponse{Message: "ok"})
+}
+
+// listFormat is the format of a list file given in the query or by its Content-Type
+func listFormat(ctx *gin.Context, format string) string {
+	if format != "" {
+		return strings.ToLower(format)
 	}
 
-	eVal := fv.Call([]reflect.Value{objVal})[0].Interface()
-	if eVal != nil {
-		ErrorSender(ctx, eVal.(error))
-		return
+	switch ctx.ContentType() {
+	case "text/csv":
+		return entity.ListFormatCSV
+	case "application/xml", "text/xml":
+		return entity.ListFormatXML
 	}
 
-	ResponseOK(ctx)
+	return entity.ListFormatJSON
 }
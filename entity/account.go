@@ -0,0 +1,44 @@
+package entity
+
+import "time"
+
+// AccountArchive is everything stored about a user
+type AccountArchive struct {
+	ExportedAt time.Time        `json:"exported_at"`
+	Profile    AccountProfile   `json:"profile"`
+	Socials    []AccountSocial  `json:"socials"`
+	Games      []ListEntry      `json:"games"`
+	Sessions   []AccountSession `json:"sessions"`
+}
+
+type AccountProfile struct {
+	ID                  uint64     `json:"id"`
+	Nickname            string     `json:"nickname"`
+	Email               string     `json:"email"`
+	Description         string     `json:"description"`
+	GamesListed         uint       `json:"games_listed"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+	DeletionRequestedAt *time.Time `json:"deletion_requested_at,omitempty"`
+}
+
+type AccountSocial struct {
+	Type string `json:"type"`
+	Data string `json:"data"`
+}
+
+// AccountSession is a refresh token issued to the user. Its value isn't exported.
+type AccountSession struct {
+	ID        uint64     `json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+type AccountDeletionRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+type AccountDeletion struct {
+	RequestedAt time.Time `json:"requested_at"`
+	PurgeAt     time.Time `json:"purge_at"`
+}
@@ -33,11 +33,41 @@ type GameListRequest struct {
 	ListType uint64 `json:"list_type"`
 }
 
+// ListTypeRequest sets the list type of a game of the path. 0 removes the game from the list.
+type ListTypeRequest struct {
+	ListType uint64 `json:"list_type"`
+}
+
 type TypedGameListProperties struct {
 	GameProperties
 	ListTypeID uint64 `json:"user_list"`
 }
 
+// GAMES_BATCH_SIZE_LIMIT is the max size of v0 batches of games, which is kept apart
+// from page limits
+const GAMES_BATCH_SIZE_LIMIT = 10
+
+// GameBatchRequest is the body of v0 /games/all, which takes games after the last one
+// the client has
+type GameBatchRequest struct {
+	Last      uint64 `json:"last"`
+	BatchSize int    `json:"batch_size"`
+}
+
+// PageRequest is the page of games of the batch. Missing and too large batch sizes
+// get GAMES_BATCH_SIZE_LIMIT, negative ones are left to be rejected.
+func (r GameBatchRequest) PageRequest() PageRequest {
+	page := PageRequest{Limit: r.BatchSize}
+	if r.BatchSize == 0 || r.BatchSize > GAMES_BATCH_SIZE_LIMIT {
+		page.Limit = GAMES_BATCH_SIZE_LIMIT
+	}
+	if r.Last != 0 {
+		page.Cursor = Cursor{ID: r.Last}.Encode()
+	}
+
+	return page
+}
+
 type SearchRequest struct {
 	Name string `json:"name"`
 }
@@ -51,13 +81,13 @@ type GameDetailsRequest struct {
 	Id uint64 `json:"id"`
 }
 
+// MessageResponse is sent by requests which have nothing to respond with
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
 type GameDetailsResponse struct {
 	Game      TypedGameListProperties `json:"game"`
 	Platforms []Platform              `json:"platforms"`
 	Genres    []Genre                 `json:"genres"`
 }
-
-type GameBatchRequest struct {
-	Last      uint64 `json:"last"`
-	BatchSize int    `json:"batch_size"`
-}
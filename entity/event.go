@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+const (
+	EventListUpdated    = "list.updated"
+	EventListImported   = "list.imported"
+	EventProfileUpdated = "profile.updated"
+)
+
+// Event is a change of a user's data pushed to the user's subscribers
+type Event struct {
+	Type     string `json:"type"`
+	Nickname string `json:"nickname"`
+	GameID   uint64 `json:"game_id,omitempty"`
+	// ListType of list.updated events, 0 if the game is unlisted
+	ListType *uint64 `json:"list_type,omitempty"`
+	// Imported is the number of games listed by list.imported events
+	Imported int       `json:"imported,omitempty"`
+	Time     time.Time `json:"time"`
+}
@@ -48,6 +48,8 @@ type Profile struct {
 	Email         string         `gorm:"unique;not null" json:"email" binding:"required"`
 	Password      string         `json:"password" binding:"gte=6,lte=70"`
 	RefreshTokens []RefreshToken `gorm:"foreignKey:ProfileID" json:"-"`
+	// The profile is purged once the deletion grace period passes
+	DeletionRequestedAt *time.Time `json:"-"`
 }
 
 func (*Profile) TableName() string {
@@ -107,3 +109,72 @@ type ListType struct {
 func (*ListType) TableName() string {
 	return "list_type"
 }
+
+type GameExternalID struct {
+	Source     string    `gorm:"primaryKey" json:"source"`
+	ExternalID string    `gorm:"primaryKey" json:"external_id"`
+	CreatedAt  time.Time `json:"-"`
+	UpdatedAt  time.Time `json:"-"`
+	GameID     uint64    `json:"game_id"`
+}
+
+func (*GameExternalID) TableName() string {
+	return "game_external_id"
+}
+
+type ScrapeJob struct {
+	ID         uint64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	CreatedAt  time.Time   `json:"-"`
+	UpdatedAt  time.Time   `json:"-"`
+	Trigger    string      `gorm:"varchar(20)" json:"trigger"`
+	Status     string      `gorm:"varchar(20)" json:"status"`
+	StartedAt  time.Time   `json:"started_at"`
+	FinishedAt *time.Time  `json:"finished_at"`
+	Stats      IngestStats `gorm:"embedded" json:"stats"`
+	Error      string      `json:"error,omitempty"`
+	// HeartbeatAt is renewed while the job runs, jobs of stopped servers stop beating
+	HeartbeatAt time.Time `json:"-"`
+}
+
+func (*ScrapeJob) TableName() string {
+	return "scrape_job"
+}
+
+// GameFieldSource is the source which last set the game's field
+type GameFieldSource struct {
+	GameID uint64 `gorm:"primaryKey" json:"game_id"`
+	Field  string `gorm:"primaryKey" json:"field"`
+	Source string `json:"source"`
+}
+
+func (*GameFieldSource) TableName() string {
+	return "game_field_source"
+}
+
+// GameDuplicate is a pair of games which are likely the same game.
+// GameID is always less than DuplicateID.
+type GameDuplicate struct {
+	GameID      uint64         `gorm:"primaryKey" json:"-"`
+	Game        GameProperties `gorm:"foreignKey:GameID" json:"game"`
+	DuplicateID uint64         `gorm:"primaryKey" json:"-"`
+	Duplicate   GameProperties `gorm:"foreignKey:DuplicateID" json:"duplicate"`
+	CreatedAt   time.Time      `json:"-"`
+	UpdatedAt   time.Time      `json:"-"`
+	Score       float64        `json:"score"`
+	Status      string         `gorm:"varchar(20)" json:"status"`
+}
+
+func (*GameDuplicate) TableName() string {
+	return "game_duplicate"
+}
+
+// GameRedirect keeps ids of merged games resolvable
+type GameRedirect struct {
+	OldID     uint64 `gorm:"primaryKey"`
+	NewID     uint64 `gorm:"not null"`
+	CreatedAt time.Time
+}
+
+func (*GameRedirect) TableName() string {
+	return "game_redirect"
+}
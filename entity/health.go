@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+const (
+	HealthOK       = "ok"
+	HealthFailed   = "failed"
+	HealthDraining = "draining"
+)
+
+// HealthReport is the response of health and readiness probes
+type HealthReport struct {
+	Status    string                 `json:"status"`
+	StartedAt time.Time              `json:"started_at"`
+	Checks    map[string]HealthCheck `json:"checks,omitempty"`
+}
+
+// HealthCheck is the result of checking a dependency of the server
+type HealthCheck struct {
+	Status     string                 `json:"status"`
+	DurationMs float64                `json:"duration_ms"`
+	Error      string                 `json:"error,omitempty"`
+	Details    map[string]interface{} `json:"details,omitempty"`
+}
@@ -0,0 +1,55 @@
+package entity
+
+const (
+	ListFormatCSV  = "csv"
+	ListFormatJSON = "json"
+	ListFormatXML  = "xml"
+)
+
+const (
+	// The entry matched a single game and is imported
+	ImportMatched = "matched"
+	// The entry is similar to several games and must be resolved by setting game_id
+	ImportAmbiguous = "ambiguous"
+	ImportUnmatched = "unmatched"
+	// The entry can't be imported as is, e.g. its list type is unknown
+	ImportInvalid = "invalid"
+)
+
+// ListEntry is a game of a user's list in import and export files.
+// XML entries follow MyAnimeList export fields.
+type ListEntry struct {
+	GameID       uint64 `json:"game_id,omitempty" xml:"series_gamedb_id,omitempty"`
+	Title        string `json:"title" xml:"series_title"`
+	YearReleased uint16 `json:"year_released,omitempty" xml:"series_year,omitempty"`
+	ListType     string `json:"list_type" xml:"my_status"`
+}
+
+type GameTitle struct {
+	ID           uint64 `json:"id"`
+	Name         string `json:"name"`
+	YearReleased uint16 `json:"year_released"`
+}
+
+type ImportEntry struct {
+	ListEntry
+	ListTypeID uint64      `json:"-"`
+	Status     string      `json:"status"`
+	Candidates []GameTitle `json:"candidates,omitempty"`
+	Error      string      `json:"error,omitempty"`
+}
+
+type ImportResult struct {
+	Imported int `json:"imported"`
+	// Review lists entries which weren't imported
+	Review []ImportEntry `json:"review"`
+}
+
+type ImportRequest struct {
+	Format string `form:"format"`
+	DryRun bool   `form:"dry_run"`
+}
+
+type ExportRequest struct {
+	Format string `form:"format"`
+}
@@ -0,0 +1,194 @@
+package entity
+
+import (
+	"strings"
+
+	utilErrs "github.com/br3w0r/gamelist-backend/util/errors"
+)
+
+type IngestResult uint8
+
+const (
+	IngestInserted IngestResult = iota + 1
+	IngestUpdated
+	IngestSkipped
+)
+
+type IngestStats struct {
+	Inserted uint64 `json:"inserted"`
+	Updated  uint64 `json:"updated"`
+	Skipped  uint64 `json:"skipped"`
+	Failed   uint64 `json:"failed"`
+}
+
+func (s *IngestStats) Add(result IngestResult) {
+	switch result {
+	case IngestInserted:
+		s.Inserted++
+	case IngestUpdated:
+		s.Updated++
+	case IngestSkipped:
+		s.Skipped++
+	}
+}
+
+func (s *IngestStats) Merge(other IngestStats) {
+	s.Inserted += other.Inserted
+	s.Updated += other.Updated
+	s.Skipped += other.Skipped
+	s.Failed += other.Failed
+}
+
+func (s *IngestStats) Total() uint64 {
+	return s.Inserted + s.Updated + s.Skipped + s.Failed
+}
+
+const (
+	ScrapeTriggerBoot     = "boot"
+	ScrapeTriggerSchedule = "schedule"
+	ScrapeTriggerManual   = "manual"
+
+	ScrapeJobRunning   = "running"
+	ScrapeJobSucceeded = "succeeded"
+	ScrapeJobFailed    = "failed"
+	ScrapeJobCancelled = "cancelled"
+)
+
+// SourcedGame is a game as it's known to one of the game sources
+type SourcedGame struct {
+	Source     string
+	ExternalID string
+	Game       GameProperties
+}
+
+// GameFeedItem is a game in file and HTTP feeds
+type GameFeedItem struct {
+	ExternalID   string   `json:"external_id"`
+	Name         string   `json:"name"`
+	ImageURL     string   `json:"image_url"`
+	YearReleased uint16   `json:"year_released"`
+	Platforms    []string `json:"platforms"`
+	Genres       []string `json:"genres"`
+}
+
+func (i *GameFeedItem) Sourced(source string) SourcedGame {
+	game := GameProperties{
+		Name:         i.Name,
+		ImageURL:     i.ImageURL,
+		YearReleased: i.YearReleased,
+		Platforms:    make([]Platform, len(i.Platforms)),
+		Genres:       make([]Genre, len(i.Genres)),
+	}
+	for j := range i.Platforms {
+		game.Platforms[j] = Platform{Name: i.Platforms[j]}
+	}
+	for j := range i.Genres {
+		game.Genres[j] = Genre{Name: i.Genres[j]}
+	}
+
+	externalID := i.ExternalID
+	if externalID == "" {
+		externalID = i.Name
+	}
+
+	return SourcedGame{
+		Source:     source,
+		ExternalID: externalID,
+		Game:       game,
+	}
+}
+
+const (
+	GameFieldName         = "name"
+	GameFieldImageURL     = "image_url"
+	GameFieldYearReleased = "year_released"
+	GameFieldPlatforms    = "platforms"
+	GameFieldGenres       = "genres"
+
+	// SourcePriorityDefault is the key of the order used for fields without their own
+	SourcePriorityDefault = "*"
+)
+
+var (
+	GameFields = []string{
+		GameFieldName,
+		GameFieldImageURL,
+		GameFieldYearReleased,
+		GameFieldPlatforms,
+		GameFieldGenres,
+	}
+)
+
+// SourcePriority orders game sources per field, earlier sources win.
+// Sources missing from the order lose to the listed ones and win over each other.
+type SourcePriority map[string][]string
+
+// ParseSourcePriority parses "<field>=<source>,<source>;..." specs,
+// e.g. "*=scraper,feed;image_url=feed,scraper"
+func ParseSourcePriority(spec string) (SourcePriority, error) {
+	priority := SourcePriority{}
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		kv := strings.SplitN(entry, "=", 2)
+		if len(kv) != 2 {
+			return nil, utilErrs.Newf(utilErrs.BadInput, nil, "wrong source priority \"%s\": expected <field>=<sources>", entry)
+		}
+
+		field := strings.TrimSpace(kv[0])
+		if field != SourcePriorityDefault && !isGameField(field) {
+			return nil, utilErrs.Newf(utilErrs.BadInput, nil, "wrong source priority \"%s\": unknown game field %s", entry, field)
+		}
+
+		for _, source := range strings.Split(kv[1], ",") {
+			if source = strings.TrimSpace(source); source != "" {
+				priority[field] = append(priority[field], source)
+			}
+		}
+	}
+
+	return priority, nil
+}
+
+// Wins reports whether the incoming source may overwrite a field set by the current one
+func (p SourcePriority) Wins(field string, incoming string, current string) bool {
+	if current == "" || incoming == current {
+		return true
+	}
+
+	return p.rank(field, incoming) <= p.rank(field, current)
+}
+
+func (p SourcePriority) rank(field string, source string) int {
+	order, ok := p[field]
+	if !ok {
+		order = p[SourcePriorityDefault]
+	}
+
+	for i := range order {
+		if order[i] == source {
+			return i
+		}
+	}
+
+	return len(order)
+}
+
+func isGameField(field string) bool {
+	for i := range GameFields {
+		if GameFields[i] == field {
+			return true
+		}
+	}
+
+	return false
+}
+
+const (
+	DuplicatePending   = "pending"
+	DuplicateMerged    = "merged"
+	DuplicateDismissed = "dismissed"
+)
@@ -0,0 +1,145 @@
+package entity
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	utilErrs "github.com/br3w0r/gamelist-backend/util/errors"
+)
+
+// Collections with their own page size limits
+const (
+	PageDefault   = "*"
+	PageGames     = "games"
+	PageUserGames = "my-games"
+	// List types, genres, platforms and social types
+	PageCatalog  = "catalog"
+	PageProfiles = "profiles"
+)
+
+const (
+	PAGE_SIZE_LIMIT = 100
+)
+
+// Cursor points at the last item of a page by the key the collection is sorted by and its id
+type Cursor struct {
+	Key string `json:"k,omitempty"`
+	ID  uint64 `json:"id"`
+}
+
+// Encode makes an opaque cursor for responses
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor decodes a cursor of Encode. The empty cursor is the first page.
+func DecodeCursor(cursor string) (Cursor, error) {
+	var c Cursor
+	if cursor == "" {
+		return c, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil {
+		err = json.Unmarshal(data, &c)
+	}
+	if err != nil {
+		return c, utilErrs.Newf(utilErrs.BadInput, err, "wrong cursor \"%s\"", cursor).WithReason(utilErrs.ReasonInvalidCursor)
+	}
+
+	return c, nil
+}
+
+type PageRequest struct {
+	Cursor string `form:"cursor" json:"cursor"`
+	Limit  int    `form:"limit" json:"limit"`
+}
+
+// Validate checks the limit of the page, 0 is the max size of the collection
+func (p PageRequest) Validate() error {
+	if p.Limit < 0 {
+		return utilErrs.Newf(utilErrs.BadInput, nil, "wrong page limit %d: must not be negative", p.Limit).WithReason(utilErrs.ReasonInvalidPageLimit)
+	}
+	return nil
+}
+
+// GamesRequest is a page of games. Query filters games by the beginning of their names.
+type GamesRequest struct {
+	PageRequest
+	Query string `form:"q" json:"q"`
+}
+
+type Page[T any] struct {
+	Items []T `json:"items"`
+	// NextCursor is empty on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// NewPage makes a page of at most limit items. Items should be fetched with
+// one extra item, which tells that there's a next page.
+func NewPage[T any](items []T, limit int, cursor func(*T) Cursor) *Page[T] {
+	page := &Page[T]{Items: items}
+	if limit > 0 && len(items) > limit {
+		page.Items = items[:limit]
+		page.NextCursor = cursor(&page.Items[limit-1]).Encode()
+	}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+
+	return page
+}
+
+// PageLimits are max page sizes of collections
+type PageLimits map[string]int
+
+// ParsePageLimits parses "<collection>=<size>;..." specs, e.g. "*=100;games=50"
+func ParsePageLimits(spec string) (PageLimits, error) {
+	limits := PageLimits{}
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		kv := strings.SplitN(entry, "=", 2)
+		if len(kv) != 2 {
+			return nil, utilErrs.Newf(utilErrs.BadInput, nil, "wrong page limit \"%s\": expected <collection>=<size>", entry).WithReason(utilErrs.ReasonInvalidPageLimit)
+		}
+
+		collection := strings.TrimSpace(kv[0])
+		switch collection {
+		case PageDefault, PageGames, PageUserGames, PageCatalog, PageProfiles:
+		default:
+			return nil, utilErrs.Newf(utilErrs.BadInput, nil, "wrong page limit \"%s\": unknown collection %s", entry, collection).WithReason(utilErrs.ReasonInvalidPageLimit)
+		}
+
+		size, err := strconv.Atoi(strings.TrimSpace(kv[1]))
+		if err != nil || size <= 0 {
+			return nil, utilErrs.Newf(utilErrs.BadInput, err, "wrong page limit \"%s\": expected a positive size", entry).WithReason(utilErrs.ReasonInvalidPageLimit)
+		}
+		limits[collection] = size
+	}
+
+	return limits, nil
+}
+
+// Clamp returns the page size of the collection for the requested limit.
+// Missing and too large limits get the max size.
+func (l PageLimits) Clamp(collection string, limit int) int {
+	max, ok := l[collection]
+	if !ok {
+		max, ok = l[PageDefault]
+	}
+	if !ok {
+		max = PAGE_SIZE_LIMIT
+	}
+
+	if limit <= 0 || limit > max {
+		return max
+	}
+	return limit
+}
@@ -0,0 +1,131 @@
+package graph
+
+import (
+	"strconv"
+
+	"github.com/br3w0r/gamelist-backend/entity"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// complexity estimates the cost of an operation. Every field costs 1, and
+// selections of paged fields cost as much times as many items their pages may have.
+type complexity struct {
+	schema    *graphql.Schema
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+	limits    entity.PageLimits
+}
+
+// Complexity of the operation of the name, or of the only operation if the name is empty.
+// The document must be validated first.
+func Complexity(schema *graphql.Schema, doc *ast.Document, operationName string, variables map[string]interface{}, limits entity.PageLimits) int {
+	c := &complexity{
+		schema:    schema,
+		fragments: map[string]*ast.FragmentDefinition{},
+		variables: variables,
+		limits:    limits,
+	}
+
+	var operation *ast.OperationDefinition
+	for _, definition := range doc.Definitions {
+		switch definition := definition.(type) {
+		case *ast.FragmentDefinition:
+			c.fragments[definition.Name.Value] = definition
+		case *ast.OperationDefinition:
+			if operationName == "" || (definition.Name != nil && definition.Name.Value == operationName) {
+				operation = definition
+			}
+		}
+	}
+	if operation == nil {
+		return 0
+	}
+
+	var root graphql.Type = schema.QueryType()
+	if operation.Operation == ast.OperationTypeMutation {
+		root = schema.MutationType()
+	}
+
+	return c.selectionSet(root, operation.SelectionSet, map[string]bool{})
+}
+
+func (c *complexity) selectionSet(parent graphql.Type, set *ast.SelectionSet, spread map[string]bool) int {
+	if set == nil {
+		return 0
+	}
+
+	cost := 0
+	for _, selection := range set.Selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			cost += c.field(parent, selection, spread)
+		case *ast.InlineFragment:
+			t := parent
+			if selection.TypeCondition != nil {
+				t = c.schema.Type(selection.TypeCondition.Name.Value)
+			}
+			cost += c.selectionSet(t, selection.SelectionSet, spread)
+		case *ast.FragmentSpread:
+			name := selection.Name.Value
+			fragment, ok := c.fragments[name]
+			if !ok || spread[name] {
+				continue
+			}
+
+			spread[name] = true
+			cost += c.selectionSet(c.schema.Type(fragment.TypeCondition.Name.Value), fragment.SelectionSet, spread)
+			delete(spread, name)
+		}
+	}
+
+	return cost
+}
+
+func (c *complexity) field(parent graphql.Type, field *ast.Field, spread map[string]bool) int {
+	var fields graphql.FieldDefinitionMap
+	switch parent := parent.(type) {
+	case *graphql.Object:
+		fields = parent.Fields()
+	case *graphql.Interface:
+		fields = parent.Fields()
+	}
+
+	definition, ok := fields[field.Name.Value]
+	if !ok {
+		// Introspection fields
+		return 1
+	}
+
+	items := 1
+	if collection, ok := pagedFields[parent.Name()+"."+field.Name.Value]; ok {
+		items = c.limits.Clamp(collection, c.intArgument(field, "first"))
+	}
+
+	named, _ := graphql.GetNamed(definition.Type).(graphql.Type)
+	return 1 + items*c.selectionSet(named, field.SelectionSet, spread)
+}
+
+// intArgument is the value of the argument or 0 if it isn't set
+func (c *complexity) intArgument(field *ast.Field, name string) int {
+	for _, argument := range field.Arguments {
+		if argument.Name.Value != name {
+			continue
+		}
+
+		switch value := argument.Value.(type) {
+		case *ast.IntValue:
+			n, _ := strconv.Atoi(value.Value)
+			return n
+		case *ast.Variable:
+			switch n := c.variables[value.Name.Value].(type) {
+			case int:
+				return n
+			case float64:
+				return int(n)
+			}
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,151 @@
+// Package graph serves the gamelist domain as a GraphQL graph of games, catalog
+// items, profiles and their lists. Resolvers batch lookups with loaders.
+package graph
+
+import (
+	"context"
+
+	"github.com/br3w0r/gamelist-backend/entity"
+	"github.com/br3w0r/gamelist-backend/service"
+	utilErrs "github.com/br3w0r/gamelist-backend/util/errors"
+	utilLogger "github.com/br3w0r/gamelist-backend/util/logger"
+	"github.com/br3w0r/gamelist-backend/util/tracing"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	MAX_COMPLEXITY = 5000
+)
+
+var (
+	graphqlLog  = utilLogger.For(utilLogger.SubsystemGraphQL)
+	graphTracer = tracing.Tracer("graph")
+)
+
+type Request struct {
+	Query         string                 `json:"query" binding:"required"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+type Executor interface {
+	// Execute runs the query for the viewer of the nickname. Queries more complex
+	// than the limit are rejected before any resolver runs.
+	Execute(ctx context.Context, nickname string, request Request) *graphql.Result
+	Schema() *graphql.Schema
+}
+
+type executor struct {
+	schema          graphql.Schema
+	gamelistService service.GameListService
+	limits          entity.PageLimits
+	maxComplexity   int
+}
+
+// NewExecutor makes an executor of the gamelist schema. Limits of 0 and below fall back to MAX_COMPLEXITY.
+func NewExecutor(gamelistService service.GameListService, limits entity.PageLimits, maxComplexity int) (Executor, error) {
+	schema, err := NewSchema(gamelistService)
+	if err != nil {
+		return nil, utilErrs.New(utilErrs.Internal, err, "failed to build GraphQL schema")
+	}
+	if maxComplexity <= 0 {
+		maxComplexity = MAX_COMPLEXITY
+	}
+
+	return &executor{
+		schema:          schema,
+		gamelistService: gamelistService,
+		limits:          limits,
+		maxComplexity:   maxComplexity,
+	}, nil
+}
+
+func (e *executor) Schema() *graphql.Schema {
+	return &e.schema
+}
+
+func (e *executor) Execute(ctx context.Context, nickname string, request Request) *graphql.Result {
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(request.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+
+	validation := graphql.ValidateDocument(&e.schema, doc, nil)
+	if !validation.IsValid {
+		return &graphql.Result{Errors: validation.Errors}
+	}
+
+	cost := Complexity(&e.schema, doc, request.OperationName, request.Variables, e.limits)
+	if cost > e.maxComplexity {
+		err := utilErrs.Newf(utilErrs.BadInput, nil, "query complexity %d exceeds the limit of %d", cost, e.maxComplexity).
+			WithReason(utilErrs.ReasonQueryTooComplex)
+		return &graphql.Result{Errors: formatErrors(ctx, []gqlerrors.FormattedError{gqlerrors.FormatError(err)})}
+	}
+
+	ctx, span := graphTracer.Start(ctx, "graphql.execute", trace.WithAttributes(
+		attribute.String("graphql.operation.name", request.OperationName),
+		attribute.Int("graphql.complexity", cost),
+	))
+	defer span.End()
+
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        e.schema,
+		AST:           doc,
+		OperationName: request.OperationName,
+		Args:          request.Variables,
+		Context:       newRequestContext(ctx, e.gamelistService, nickname),
+	})
+	result.Errors = formatErrors(ctx, result.Errors)
+
+	return result
+}
+
+// formatErrors adds codes of errors returned by services and logs internal errors
+func formatErrors(ctx context.Context, errs []gqlerrors.FormattedError) []gqlerrors.FormattedError {
+	for i := range errs {
+		utilErr := originalError(errs[i].OriginalError())
+		if utilErr == nil {
+			continue
+		}
+
+		if errs[i].Extensions == nil {
+			errs[i].Extensions = map[string]interface{}{}
+		}
+		errs[i].Extensions["code"] = utilErr.Code().String()
+		errs[i].Extensions["reason"] = string(utilErr.Reason())
+		if fields := utilErr.Fields(); len(fields) > 0 {
+			errs[i].Extensions["fields"] = fields
+		}
+
+		if utilErr.Code() == utilErrs.Internal {
+			graphqlLog.ErrorContext(ctx, "internal error", "error", utilErr.Error(), "cause", utilErr.Cause())
+		}
+	}
+
+	return errs
+}
+
+// originalError finds the service error wrapped by located and formatted errors
+func originalError(err error) *utilErrs.Error {
+	for err != nil {
+		switch e := err.(type) {
+		case *utilErrs.Error:
+			return e
+		case gqlerrors.FormattedError:
+			err = e.OriginalError()
+		case *gqlerrors.Error:
+			err = e.OriginalError
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
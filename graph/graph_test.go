@@ -0,0 +1,181 @@
+package graph
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/br3w0r/gamelist-backend/entity"
+	"github.com/br3w0r/gamelist-backend/service"
+	utilErrs "github.com/br3w0r/gamelist-backend/util/errors"
+	"github.com/golang/mock/gomock"
+	"github.com/smartystreets/goconvey/convey"
+)
+
+// sameIDs matches id slices in any order
+type sameIDs []uint64
+
+func (m sameIDs) Matches(x interface{}) bool {
+	ids, ok := x.([]uint64)
+	if !ok || len(ids) != len(m) {
+		return false
+	}
+
+	sorted := append([]uint64{}, ids...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	for i := range m {
+		if sorted[i] != m[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (m sameIDs) String() string {
+	return "has the same ids"
+}
+
+func TestLoader(t *testing.T) {
+	convey.Convey("Loader should fetch queued keys at once and cache them", t, func() {
+		var fetches [][]int
+		loader := NewLoader(context.Background(), func(_ context.Context, keys []int) (map[int]string, error) {
+			fetches = append(fetches, keys)
+			return map[int]string{1: "one", 2: "two"}, nil
+		})
+
+		one, two, three := loader.Load(1), loader.Load(2), loader.Load(3)
+		loader.Load(1)
+
+		value, found, err := two()
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(found, convey.ShouldBeTrue)
+		convey.So(value, convey.ShouldEqual, "two")
+
+		value, _, _ = one()
+		convey.So(value, convey.ShouldEqual, "one")
+		_, found, _ = three()
+		convey.So(found, convey.ShouldBeFalse)
+
+		value, _, _ = loader.Load(1)()
+		convey.So(value, convey.ShouldEqual, "one")
+		convey.So(fetches, convey.ShouldResemble, [][]int{{1, 2, 3}})
+	})
+}
+
+func TestExecutor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := service.NewMockGamelistRepository(ctrl)
+	executor, err := NewExecutor(service.NewGameListService(repo, nil, nil, nil, nil), nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	games := []entity.TypedGameListProperties{
+		{GameProperties: entity.GameProperties{Model: entity.Model{ID: 1}, Name: "Doom"}, ListTypeID: 2},
+		{GameProperties: entity.GameProperties{Model: entity.Model{ID: 2}, Name: "Quake"}},
+	}
+
+	convey.Convey("Nested fields of a page should be loaded in batches", t, func() {
+		repo.EXPECT().GetAllGamesTyped(gomock.Any(), "viewer", "", entity.Cursor{}, 2).Return(games, nil)
+		repo.EXPECT().GetPlatformsOfGames(gomock.Any(), sameIDs{1, 2}).Return(map[uint64][]entity.Platform{
+			1: {{ID: 1, Name: "PC"}},
+			2: {{ID: 1, Name: "PC"}, {ID: 2, Name: "N64"}},
+		}, nil)
+		repo.EXPECT().GetGenresOfGames(gomock.Any(), sameIDs{1, 2}).Return(map[uint64][]entity.Genre{
+			1: {{ID: 3, Name: "Shooter"}},
+		}, nil)
+		repo.EXPECT().GetListTypesByIDs(gomock.Any(), []uint64{2}).Return(map[uint64]entity.ListType{
+			2: {Model: entity.Model{ID: 2}, Name: "Completed"},
+		}, nil)
+
+		result := executor.Execute(context.Background(), "viewer", Request{
+			Query: `query Games($first: Int) {
+				games(first: $first) {
+					items { id name listType { name } platforms { name } genres { name } }
+					nextCursor
+				}
+			}`,
+			Variables: map[string]interface{}{"first": 2.0},
+		})
+		convey.So(result.Errors, convey.ShouldBeEmpty)
+
+		page := result.Data.(map[string]interface{})["games"].(map[string]interface{})
+		convey.So(page["nextCursor"], convey.ShouldBeNil)
+
+		items := page["items"].([]interface{})
+		convey.So(items, convey.ShouldHaveLength, 2)
+		convey.So(items[0], convey.ShouldResemble, map[string]interface{}{
+			"id":        "1",
+			"name":      "Doom",
+			"listType":  map[string]interface{}{"name": "Completed"},
+			"platforms": []interface{}{map[string]interface{}{"name": "PC"}},
+			"genres":    []interface{}{map[string]interface{}{"name": "Shooter"}},
+		})
+		convey.So(items[1].(map[string]interface{})["listType"], convey.ShouldBeNil)
+		convey.So(items[1].(map[string]interface{})["platforms"], convey.ShouldHaveLength, 2)
+		convey.So(items[1].(map[string]interface{})["genres"], convey.ShouldBeEmpty)
+	})
+
+	convey.Convey("Too complex queries should be rejected before resolving", t, func() {
+		result := executor.Execute(context.Background(), "viewer", Request{
+			Query: `{ profiles { items { games { items { game { name platforms { name } } } } } } }`,
+		})
+		convey.So(result.Data, convey.ShouldBeNil)
+		convey.So(result.Errors, convey.ShouldHaveLength, 1)
+		convey.So(result.Errors[0].Message, convey.ShouldContainSubstring, "complexity")
+		convey.So(result.Errors[0].Extensions["code"], convey.ShouldEqual, utilErrs.BadInput.String())
+
+		repo.EXPECT().GetAllProfiles(gomock.Any(), entity.Cursor{}, 5).Return(nil, nil)
+		result = executor.Execute(context.Background(), "viewer", Request{
+			Query: `{ profiles(first: 5) { items { games(first: 10) { items { game { name } } } } } }`,
+		})
+		convey.So(result.Errors, convey.ShouldBeEmpty)
+	})
+
+	convey.Convey("Lists of several profiles should be loaded with one query", t, func() {
+		repo.EXPECT().GetAllProfiles(gomock.Any(), entity.Cursor{}, 3).Return([]entity.ProfileInfo{
+			{Model: entity.Model{ID: 1}, Nickname: "first"},
+			{Model: entity.Model{ID: 2}, Nickname: "second"},
+			{Model: entity.Model{ID: 3}, Nickname: "third"},
+		}, nil)
+		repo.EXPECT().GetGameListsOfProfiles(gomock.Any(), sameIDs{1, 2, 3}, entity.Cursor{}, 1).Return(map[uint64][]entity.TypedGameListProperties{
+			1: games,
+			3: games[1:],
+		}, nil).Times(1)
+
+		result := executor.Execute(context.Background(), "viewer", Request{
+			Query: `{ profiles(first: 3) { items { nickname games(first: 1) { items { game { name } } nextCursor } } } }`,
+		})
+		convey.So(result.Errors, convey.ShouldBeEmpty)
+
+		profiles := result.Data.(map[string]interface{})["profiles"].(map[string]interface{})["items"].([]interface{})
+		convey.So(profiles, convey.ShouldHaveLength, 3)
+
+		first := profiles[0].(map[string]interface{})["games"].(map[string]interface{})
+		convey.So(first["items"], convey.ShouldResemble, []interface{}{map[string]interface{}{
+			"game": map[string]interface{}{"name": "Doom"},
+		}})
+		convey.So(first["nextCursor"], convey.ShouldNotBeNil)
+
+		second := profiles[1].(map[string]interface{})["games"].(map[string]interface{})
+		convey.So(second["items"], convey.ShouldBeEmpty)
+		convey.So(second["nextCursor"], convey.ShouldBeNil)
+
+		third := profiles[2].(map[string]interface{})["games"].(map[string]interface{})
+		convey.So(third["items"], convey.ShouldResemble, []interface{}{map[string]interface{}{
+			"game": map[string]interface{}{"name": "Quake"},
+		}})
+		convey.So(third["nextCursor"], convey.ShouldBeNil)
+	})
+
+	convey.Convey("Service errors should have their codes", t, func() {
+		repo.EXPECT().GetGamesByIDs(gomock.Any(), []uint64{7}).Return(nil, utilErrs.New(utilErrs.Internal, nil, "failed to get games"))
+
+		result := executor.Execute(context.Background(), "viewer", Request{Query: `{ game(id: 7) { name } }`})
+		convey.So(result.Errors, convey.ShouldHaveLength, 1)
+		convey.So(result.Errors[0].Message, convey.ShouldEqual, "failed to get games")
+		convey.So(result.Errors[0].Extensions["code"], convey.ShouldEqual, utilErrs.Internal.String())
+	})
+}
@@ -0,0 +1,71 @@
+package graph
+
+import (
+	"context"
+	"sync"
+)
+
+type loaded[V any] struct {
+	value V
+	found bool
+	err   error
+}
+
+// Loader batches lookups of keys requested by resolvers of a single query. Load only
+// queues the key, so resolvers of a whole level of the query queue their keys before
+// the first thunk fetches all of them at once. Results are cached for the query.
+type Loader[K comparable, V any] struct {
+	// ctx is the context of the query, fetches are canceled with it
+	ctx   context.Context
+	fetch func(ctx context.Context, keys []K) (map[K]V, error)
+
+	mu      sync.Mutex
+	pending []K
+	queued  map[K]bool
+	cache   map[K]loaded[V]
+}
+
+func NewLoader[K comparable, V any](ctx context.Context, fetch func(ctx context.Context, keys []K) (map[K]V, error)) *Loader[K, V] {
+	return &Loader[K, V]{
+		ctx:    ctx,
+		fetch:  fetch,
+		queued: map[K]bool{},
+		cache:  map[K]loaded[V]{},
+	}
+}
+
+// Load queues the key and returns a thunk of its value. Unknown keys get
+// the zero value and found == false.
+func (l *Loader[K, V]) Load(key K) func() (value V, found bool, err error) {
+	l.mu.Lock()
+	if _, ok := l.cache[key]; !ok && !l.queued[key] {
+		l.queued[key] = true
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (V, bool, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if _, ok := l.cache[key]; !ok {
+			l.dispatch()
+		}
+
+		result := l.cache[key]
+		return result.value, result.found, result.err
+	}
+}
+
+// dispatch fetches pending keys. It must be called with the lock held.
+func (l *Loader[K, V]) dispatch() {
+	keys := l.pending
+	l.pending = nil
+	l.queued = map[K]bool{}
+
+	values, err := l.fetch(l.ctx, keys)
+	for _, key := range keys {
+		value, found := values[key]
+		l.cache[key] = loaded[V]{value: value, found: found, err: err}
+	}
+}
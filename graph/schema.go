@@ -0,0 +1,504 @@
+package graph
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/br3w0r/gamelist-backend/entity"
+	"github.com/br3w0r/gamelist-backend/service"
+	utilErrs "github.com/br3w0r/gamelist-backend/util/errors"
+	"github.com/graphql-go/graphql"
+)
+
+type contextKey struct{}
+
+// requestContext is the viewer of a query with loaders of the query
+type requestContext struct {
+	nickname string
+
+	games           *Loader[uint64, entity.GameProperties]
+	platforms       *Loader[uint64, []entity.Platform]
+	genres          *Loader[uint64, []entity.Genre]
+	viewerListTypes *Loader[uint64, uint64]
+	listTypes       *Loader[uint64, entity.ListType]
+	socials         *Loader[uint64, []entity.Social]
+	profileGames    *Loader[profilePage, *entity.Page[entity.TypedGameListProperties]]
+}
+
+// profilePage is a page of a profile's list
+type profilePage struct {
+	profileID uint64
+	page      entity.PageRequest
+}
+
+func newRequestContext(ctx context.Context, gamelistService service.GameListService, nickname string) context.Context {
+	return context.WithValue(ctx, contextKey{}, &requestContext{
+		nickname:  nickname,
+		games:     NewLoader(ctx, gamelistService.GetGamesByIDs),
+		platforms: NewLoader(ctx, gamelistService.GetPlatformsOfGames),
+		genres:    NewLoader(ctx, gamelistService.GetGenresOfGames),
+		viewerListTypes: NewLoader(ctx, func(ctx context.Context, ids []uint64) (map[uint64]uint64, error) {
+			return gamelistService.GetListTypesOfGames(ctx, nickname, ids)
+		}),
+		listTypes: NewLoader(ctx, gamelistService.GetListTypesByIDs),
+		socials:   NewLoader(ctx, gamelistService.GetSocialsOfProfiles),
+		profileGames: NewLoader(ctx, func(ctx context.Context, keys []profilePage) (map[profilePage]*entity.Page[entity.TypedGameListProperties], error) {
+			return loadProfileGames(ctx, gamelistService, keys)
+		}),
+	})
+}
+
+// loadProfileGames gets the lists of the profiles with one call per distinct page
+func loadProfileGames(ctx context.Context, gamelistService service.GameListService, keys []profilePage) (map[profilePage]*entity.Page[entity.TypedGameListProperties], error) {
+	profiles := make(map[entity.PageRequest][]uint64)
+	for _, key := range keys {
+		profiles[key.page] = append(profiles[key.page], key.profileID)
+	}
+
+	result := make(map[profilePage]*entity.Page[entity.TypedGameListProperties], len(keys))
+	for page, ids := range profiles {
+		lists, err := gamelistService.GetGameListsOfProfiles(ctx, ids, page)
+		if err != nil {
+			return nil, err
+		}
+		for id, list := range lists {
+			result[profilePage{profileID: id, page: page}] = list
+		}
+	}
+
+	return result, nil
+}
+
+func fromContext(ctx context.Context) *requestContext {
+	return ctx.Value(contextKey{}).(*requestContext)
+}
+
+// gameNode is a game with the list type of the viewer if it's known
+type gameNode struct {
+	entity.GameProperties
+	listTypeID    uint64
+	listTypeKnown bool
+}
+
+// listEntry is a game of a profile's list
+type listEntry struct {
+	owner      string
+	game       entity.GameProperties
+	listTypeID uint64
+}
+
+type connection struct {
+	items      interface{}
+	nextCursor string
+}
+
+func newConnection[T any, N any](page *entity.Page[T], node func(*T) N) connection {
+	items := make([]N, len(page.Items))
+	for i := range page.Items {
+		items[i] = node(&page.Items[i])
+	}
+
+	return connection{items: items, nextCursor: page.NextCursor}
+}
+
+// prop is a field of the source S
+func prop[S any](t graphql.Output, get func(source S) interface{}) *graphql.Field {
+	return &graphql.Field{
+		Type: t,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return get(p.Source.(S)), nil
+		},
+	}
+}
+
+// load resolves to the value of the loader thunk, or null for unknown keys
+func load[K comparable, V any](loader *Loader[K, V], key K, node func(V) interface{}) func() (interface{}, error) {
+	thunk := loader.Load(key)
+	return func() (interface{}, error) {
+		value, found, err := thunk()
+		if err != nil || !found {
+			return nil, err
+		}
+		return node(value), nil
+	}
+}
+
+func pageArgs(p graphql.ResolveParams) (entity.PageRequest, error) {
+	var page entity.PageRequest
+	if first, ok := p.Args["first"].(int); ok {
+		if first < 0 {
+			return page, utilErrs.New(utilErrs.BadInput, nil, "first must not be negative").WithReason(utilErrs.ReasonInvalidPageLimit)
+		}
+		page.Limit = first
+	}
+	if after, ok := p.Args["after"].(string); ok {
+		page.Cursor = after
+	}
+
+	return page, nil
+}
+
+func idArg(p graphql.ResolveParams) (uint64, error) {
+	raw, _ := p.Args["id"].(string)
+	id, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return 0, utilErrs.Newf(utilErrs.BadInput, err, "wrong id \"%s\"", raw)
+	}
+
+	return id, nil
+}
+
+// connectionOf is a page of items of the type
+func connectionOf(t graphql.Output) *graphql.Object {
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: t.Name() + "Connection",
+		Fields: graphql.Fields{
+			"items": prop(graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(t))), func(c connection) interface{} {
+				return c.items
+			}),
+			"nextCursor": {
+				Type:        graphql.String,
+				Description: "Cursor of the next page, null on the last page",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					if c := p.Source.(connection); c.nextCursor != "" {
+						return c.nextCursor, nil
+					}
+					return nil, nil
+				},
+			},
+		},
+	})
+}
+
+var pageArgsConfig = graphql.FieldConfigArgument{
+	"first": {Type: graphql.Int, Description: "Page size, limited by the server"},
+	"after": {Type: graphql.String, Description: "nextCursor of the previous page"},
+}
+
+// pagedFields are fields with pages of the collections. They're weighted by their
+// page sizes in the query complexity.
+var pagedFields = map[string]string{
+	"Query.profiles":    entity.PageProfiles,
+	"Query.games":       entity.PageGames,
+	"Query.genres":      entity.PageCatalog,
+	"Query.platforms":   entity.PageCatalog,
+	"Query.listTypes":   entity.PageCatalog,
+	"Query.socialTypes": entity.PageCatalog,
+	"Profile.games":     entity.PageUserGames,
+}
+
+// NewSchema builds the schema of games, catalog items, profiles and their lists
+func NewSchema(gamelistService service.GameListService) (graphql.Schema, error) {
+	named := func() graphql.Fields {
+		return graphql.Fields{
+			"id":   prop(graphql.NewNonNull(graphql.ID), func(m namedItem) interface{} { return m.id }),
+			"name": prop(graphql.NewNonNull(graphql.String), func(m namedItem) interface{} { return m.name }),
+		}
+	}
+	genreType := graphql.NewObject(graphql.ObjectConfig{Name: "Genre", Fields: named()})
+	platformType := graphql.NewObject(graphql.ObjectConfig{Name: "Platform", Fields: named()})
+	listTypeType := graphql.NewObject(graphql.ObjectConfig{Name: "ListType", Fields: named()})
+	socialTypeType := graphql.NewObject(graphql.ObjectConfig{Name: "SocialType", Fields: named()})
+
+	gameType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Game",
+		Fields: graphql.Fields{
+			"id":           prop(graphql.NewNonNull(graphql.ID), func(g *gameNode) interface{} { return g.ID }),
+			"name":         prop(graphql.NewNonNull(graphql.String), func(g *gameNode) interface{} { return g.Name }),
+			"imageUrl":     prop(graphql.NewNonNull(graphql.String), func(g *gameNode) interface{} { return g.ImageURL }),
+			"yearReleased": prop(graphql.NewNonNull(graphql.Int), func(g *gameNode) interface{} { return int(g.YearReleased) }),
+			"listType": {
+				Type:        listTypeType,
+				Description: "List type of the game in the viewer's list, null if it isn't listed",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					rc := fromContext(p.Context)
+					game := p.Source.(*gameNode)
+					if game.listTypeKnown {
+						if game.listTypeID == 0 {
+							return nil, nil
+						}
+						return load(rc.listTypes, game.listTypeID, newListType), nil
+					}
+
+					thunk := rc.viewerListTypes.Load(game.ID)
+					return func() (interface{}, error) {
+						id, found, err := thunk()
+						if err != nil || !found {
+							return nil, err
+						}
+						return load(rc.listTypes, id, newListType)()
+					}, nil
+				},
+			},
+			"platforms": {
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(platformType))),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return loadList(fromContext(p.Context).platforms, p.Source.(*gameNode).ID, func(platform entity.Platform) namedItem {
+						return namedItem{id: platform.ID, name: platform.Name}
+					}), nil
+				},
+			},
+			"genres": {
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(genreType))),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return loadList(fromContext(p.Context).genres, p.Source.(*gameNode).ID, func(genre entity.Genre) namedItem {
+						return namedItem{id: genre.ID, name: genre.Name}
+					}), nil
+				},
+			},
+		},
+	})
+
+	listEntryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "ListEntry",
+		Fields: graphql.Fields{
+			"game": {
+				Type: graphql.NewNonNull(gameType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					entry := p.Source.(*listEntry)
+					game := &gameNode{GameProperties: entry.game}
+					// Entries of the viewer's list have the viewer's list types
+					if entry.owner == fromContext(p.Context).nickname {
+						game.listTypeID = entry.listTypeID
+						game.listTypeKnown = true
+					}
+					return game, nil
+				},
+			},
+			"listType": {
+				Type: graphql.NewNonNull(listTypeType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return load(fromContext(p.Context).listTypes, p.Source.(*listEntry).listTypeID, newListType), nil
+				},
+			},
+		},
+	})
+
+	socialType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Social",
+		Fields: graphql.Fields{
+			"type": prop(graphql.NewNonNull(socialTypeType), func(s entity.Social) interface{} {
+				return namedItem{id: s.Type.ID, name: s.Type.Name}
+			}),
+			"data": prop(graphql.NewNonNull(graphql.String), func(s entity.Social) interface{} { return s.Data }),
+		},
+	})
+
+	profileType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Profile",
+		Fields: graphql.Fields{
+			"id":          prop(graphql.NewNonNull(graphql.ID), func(p *entity.ProfileInfo) interface{} { return p.ID }),
+			"nickname":    prop(graphql.NewNonNull(graphql.String), func(p *entity.ProfileInfo) interface{} { return p.Nickname }),
+			"description": prop(graphql.NewNonNull(graphql.String), func(p *entity.ProfileInfo) interface{} { return p.Description }),
+			"gamesListed": prop(graphql.NewNonNull(graphql.Int), func(p *entity.ProfileInfo) interface{} { return int(p.GamesListed) }),
+			"socials": {
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(socialType))),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return loadList(fromContext(p.Context).socials, p.Source.(*entity.ProfileInfo).ID, func(social entity.Social) entity.Social {
+						return social
+					}), nil
+				},
+			},
+			"games": {
+				Type:        graphql.NewNonNull(connectionOf(listEntryType)),
+				Description: "Games of the profile's list ordered by name",
+				Args:        pageArgsConfig,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					page, err := pageArgs(p)
+					if err != nil {
+						return nil, err
+					}
+
+					profile := p.Source.(*entity.ProfileInfo)
+					thunk := fromContext(p.Context).profileGames.Load(profilePage{profileID: profile.ID, page: page})
+					return func() (interface{}, error) {
+						games, found, err := thunk()
+						if err != nil {
+							return nil, err
+						}
+						if !found {
+							games = &entity.Page[entity.TypedGameListProperties]{}
+						}
+
+						return newConnection(games, func(game *entity.TypedGameListProperties) *listEntry {
+							return &listEntry{owner: profile.Nickname, game: game.GameProperties, listTypeID: game.ListTypeID}
+						}), nil
+					}, nil
+				},
+			},
+		},
+	})
+
+	catalog := func(t graphql.Output, get func(context.Context, entity.PageRequest) (connection, error)) *graphql.Field {
+		return &graphql.Field{
+			Type:        graphql.NewNonNull(connectionOf(t)),
+			Description: "Page ordered by id",
+			Args:        pageArgsConfig,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				page, err := pageArgs(p)
+				if err != nil {
+					return nil, err
+				}
+				return get(p.Context, page)
+			},
+		}
+	}
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"me": {
+				Type:        graphql.NewNonNull(profileType),
+				Description: "Profile of the viewer",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return gamelistService.GetProfileInfo(p.Context, fromContext(p.Context).nickname)
+				},
+			},
+			"profile": {
+				Type: profileType,
+				Args: graphql.FieldConfigArgument{
+					"nickname": {Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					profile, err := gamelistService.GetProfileInfo(p.Context, p.Args["nickname"].(string))
+					if err != nil {
+						if utilErr, ok := err.(*utilErrs.Error); ok && utilErr.Code() == utilErrs.NotFound {
+							return nil, nil
+						}
+						return nil, err
+					}
+					return profile, nil
+				},
+			},
+			"profiles": {
+				Type:        graphql.NewNonNull(connectionOf(profileType)),
+				Description: "Page of profiles ordered by id",
+				Args:        pageArgsConfig,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					page, err := pageArgs(p)
+					if err != nil {
+						return nil, err
+					}
+
+					profiles, err := gamelistService.GetAllProfiles(p.Context, page)
+					if err != nil {
+						return nil, err
+					}
+
+					return newConnection(profiles, func(profile *entity.ProfileInfo) *entity.ProfileInfo {
+						return profile
+					}), nil
+				},
+			},
+			"game": {
+				Type: gameType,
+				Args: graphql.FieldConfigArgument{
+					"id": {Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					id, err := idArg(p)
+					if err != nil {
+						return nil, err
+					}
+
+					return load(fromContext(p.Context).games, id, func(game entity.GameProperties) interface{} {
+						return &gameNode{GameProperties: game}
+					}), nil
+				},
+			},
+			"games": {
+				Type:        graphql.NewNonNull(connectionOf(gameType)),
+				Description: "Page of games ordered by id",
+				Args: graphql.FieldConfigArgument{
+					"q":     {Type: graphql.String, Description: "Leaves games which names start with it"},
+					"first": pageArgsConfig["first"],
+					"after": pageArgsConfig["after"],
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					page, err := pageArgs(p)
+					if err != nil {
+						return nil, err
+					}
+					query, _ := p.Args["q"].(string)
+
+					games, err := gamelistService.GetAllGamesTyped(p.Context, fromContext(p.Context).nickname, entity.GamesRequest{
+						PageRequest: page,
+						Query:       query,
+					})
+					if err != nil {
+						return nil, err
+					}
+
+					return newConnection(games, func(game *entity.TypedGameListProperties) *gameNode {
+						return &gameNode{GameProperties: game.GameProperties, listTypeID: game.ListTypeID, listTypeKnown: true}
+					}), nil
+				},
+			},
+			"genres": catalog(genreType, func(ctx context.Context, page entity.PageRequest) (connection, error) {
+				genres, err := gamelistService.GetAllGenres(ctx, page)
+				if err != nil {
+					return connection{}, err
+				}
+				return newConnection(genres, func(genre *entity.Genre) namedItem {
+					return namedItem{id: genre.ID, name: genre.Name}
+				}), nil
+			}),
+			"platforms": catalog(platformType, func(ctx context.Context, page entity.PageRequest) (connection, error) {
+				platforms, err := gamelistService.GetAllPlatforms(ctx, page)
+				if err != nil {
+					return connection{}, err
+				}
+				return newConnection(platforms, func(platform *entity.Platform) namedItem {
+					return namedItem{id: platform.ID, name: platform.Name}
+				}), nil
+			}),
+			"listTypes": catalog(listTypeType, func(ctx context.Context, page entity.PageRequest) (connection, error) {
+				listTypes, err := gamelistService.GetAllListTypes(ctx, page)
+				if err != nil {
+					return connection{}, err
+				}
+				return newConnection(listTypes, func(listType *entity.ListType) interface{} {
+					return newListType(*listType)
+				}), nil
+			}),
+			"socialTypes": catalog(socialTypeType, func(ctx context.Context, page entity.PageRequest) (connection, error) {
+				socialTypes, err := gamelistService.GetAllSocialTypes(ctx, page)
+				if err != nil {
+					return connection{}, err
+				}
+				return newConnection(socialTypes, func(socialType *entity.SocialType) namedItem {
+					return namedItem{id: socialType.ID, name: socialType.Name}
+				}), nil
+			}),
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+}
+
+// namedItem is a genre, a platform, a list type or a social type
+type namedItem struct {
+	id   uint64
+	name string
+}
+
+func newListType(listType entity.ListType) interface{} {
+	return namedItem{id: listType.ID, name: listType.Name}
+}
+
+// loadList resolves to the list of the loader thunk, which is empty for unknown keys
+func loadList[K comparable, V any, N any](loader *Loader[K, []V], key K, node func(V) N) func() (interface{}, error) {
+	thunk := loader.Load(key)
+	return func() (interface{}, error) {
+		values, _, err := thunk()
+		if err != nil {
+			return nil, err
+		}
+
+		nodes := make([]N, len(values))
+		for i, value := range values {
+			nodes[i] = node(value)
+		}
+		return nodes, nil
+	}
+}
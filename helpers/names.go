@@ -0,0 +1,109 @@
+package helpers
+
+import (
+	"strings"
+	"unicode"
+)
+
+var (
+	romanNumerals = map[string]string{
+		"ii": "2", "iii": "3", "iv": "4", "v": "5", "vi": "6",
+		"vii": "7", "viii": "8", "ix": "9", "x": "10",
+	}
+
+	// Words which don't tell one release from another
+	editionWords = map[string]bool{
+		"edition": true, "goty": true, "remastered": true, "definitive": true,
+		"complete": true, "deluxe": true, "enhanced": true, "ultimate": true,
+	}
+)
+
+// NormalizeGameName brings different spellings of a game's name to the same form:
+// "The Witcher® 3: Wild Hunt - GOTY Edition" becomes "witcher 3 wild hunt"
+func NormalizeGameName(name string) string {
+	return strings.Join(normalizedWords(name), " ")
+}
+
+// BaseGameName is the normalized name without a subtitle:
+// "The Witcher 3: Wild Hunt" becomes "witcher 3"
+func BaseGameName(name string) string {
+	if i := strings.IndexAny(name, ":("); i > 0 {
+		name = name[:i]
+	}
+	if i := strings.Index(name, " - "); i > 0 {
+		name = name[:i]
+	}
+
+	return NormalizeGameName(name)
+}
+
+// NameSimilarity is a similarity of two normalized names from 0 to 1
+// based on the Levenshtein distance
+func NameSimilarity(a string, b string) float64 {
+	if a == b {
+		return 1
+	}
+
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+func normalizedWords(name string) []string {
+	name = strings.ToLower(strings.ReplaceAll(name, "&", " and "))
+	fields := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+
+	words := make([]string, 0, len(fields))
+	for i, word := range fields {
+		word = strings.ReplaceAll(word, "'", "")
+		if word == "" || editionWords[word] || (i == 0 && word == "the") {
+			continue
+		}
+		if arabic, ok := romanNumerals[word]; ok {
+			word = arabic
+		}
+		words = append(words, word)
+	}
+
+	// "Game of the Year" is an edition too
+	if n := len(words); n >= 4 && strings.Join(words[n-4:], " ") == "game of the year" {
+		words = words[:n-4]
+	}
+
+	return words
+}
+
+func levenshtein(a []rune, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(minInt(prev[j]+1, curr[j-1]+1), prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,16 @@
+-- +goose Up
+create table game_external_id (
+    source varchar(50) NOT NULL,
+    external_id varchar(256) NOT NULL,
+    created_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    game_id int NOT NULL,
+    constraint game_external_id_game_properties_fk
+        FOREIGN KEY (game_id)
+        references game_properties(id),
+
+    PRIMARY KEY (source, external_id)
+);
+-- +goose Down
+drop table if exists game_external_id;
@@ -0,0 +1,17 @@
+-- +goose Up
+create table scrape_job (
+    id SERIAL PRIMARY KEY,
+    created_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    trigger varchar(20) NOT NULL,
+    status varchar(20) NOT NULL,
+    started_at timestamp NOT NULL,
+    finished_at timestamp,
+    inserted int DEFAULT 0 NOT NULL,
+    updated int DEFAULT 0 NOT NULL,
+    skipped int DEFAULT 0 NOT NULL,
+    failed int DEFAULT 0 NOT NULL,
+    error text
+);
+-- +goose Down
+drop table if exists scrape_job;
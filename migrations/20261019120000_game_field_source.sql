@@ -0,0 +1,14 @@
+-- +goose Up
+create table game_field_source (
+    game_id int NOT NULL,
+    constraint game_field_source_game_properties_fk
+        FOREIGN KEY (game_id)
+        references game_properties(id),
+
+    field varchar(20) NOT NULL,
+    source varchar(50) NOT NULL,
+
+    PRIMARY KEY (game_id, field)
+);
+-- +goose Down
+drop table if exists game_field_source;
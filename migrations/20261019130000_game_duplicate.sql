@@ -0,0 +1,36 @@
+-- +goose Up
+create table game_duplicate (
+    game_id int NOT NULL,
+    constraint game_duplicate_game_fk
+        FOREIGN KEY (game_id)
+        references game_properties(id),
+
+    duplicate_id int NOT NULL,
+    constraint game_duplicate_duplicate_fk
+        FOREIGN KEY (duplicate_id)
+        references game_properties(id),
+
+    created_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    score real NOT NULL,
+    status varchar(20) NOT NULL,
+
+    PRIMARY KEY (game_id, duplicate_id)
+);
+
+create table game_redirect (
+    old_id int PRIMARY KEY NOT NULL,
+    constraint game_redirect_old_fk
+        FOREIGN KEY (old_id)
+        references game_properties(id),
+
+    new_id int NOT NULL,
+    constraint game_redirect_new_fk
+        FOREIGN KEY (new_id)
+        references game_properties(id),
+
+    created_at timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+-- +goose Down
+drop table if exists game_redirect;
+drop table if exists game_duplicate;
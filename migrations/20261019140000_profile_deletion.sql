@@ -0,0 +1,43 @@
+-- +goose Up
+alter table profile add column deletion_requested_at timestamp;
+
+alter table social
+    drop constraint social_profile_fk,
+    add constraint social_profile_fk
+        FOREIGN KEY (profile_id)
+        references profile(id)
+        ON DELETE CASCADE;
+
+alter table profile_game
+    drop constraint profile_game_profile_pk,
+    add constraint profile_game_profile_pk
+        FOREIGN KEY (profile_id)
+        references profile(id)
+        ON DELETE CASCADE;
+
+alter table refresh_token
+    drop constraint refresh_token_profile_fk,
+    add constraint refresh_token_profile_fk
+        FOREIGN KEY (profile_id)
+        references profile(id)
+        ON DELETE CASCADE;
+-- +goose Down
+alter table refresh_token
+    drop constraint refresh_token_profile_fk,
+    add constraint refresh_token_profile_fk
+        FOREIGN KEY (profile_id)
+        references profile(id);
+
+alter table profile_game
+    drop constraint profile_game_profile_pk,
+    add constraint profile_game_profile_pk
+        FOREIGN KEY (profile_id)
+        references profile(id);
+
+alter table social
+    drop constraint social_profile_fk,
+    add constraint social_profile_fk
+        FOREIGN KEY (profile_id)
+        references profile(id);
+
+alter table profile drop column if exists deletion_requested_at;
@@ -0,0 +1,8 @@
+-- +goose Up
+-- Names of deleted and merged games are free to take, restoring a game whose
+-- name was taken meanwhile fails
+alter table game_properties drop constraint game_properties_name_key;
+create unique index game_properties_name_key on game_properties (name) where deleted_at is null;
+-- +goose Down
+drop index game_properties_name_key;
+alter table game_properties add constraint game_properties_name_key unique (name);
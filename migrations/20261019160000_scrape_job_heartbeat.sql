@@ -0,0 +1,13 @@
+-- +goose Up
+-- Running jobs beat while their server runs, so instances tell jobs of stopped servers
+-- from jobs of other instances. Only one job may run across all instances.
+alter table scrape_job add column heartbeat_at timestamp;
+update scrape_job set heartbeat_at = coalesce(finished_at, started_at);
+alter table scrape_job alter column heartbeat_at set DEFAULT CURRENT_TIMESTAMP;
+alter table scrape_job alter column heartbeat_at set NOT NULL;
+update scrape_job set status = 'failed', finished_at = CURRENT_TIMESTAMP, error = 'superseded by a newer running job'
+    where status = 'running' and id <> (select max(id) from scrape_job where status = 'running');
+create unique index scrape_job_running_key on scrape_job (status) where status = 'running';
+-- +goose Down
+drop index scrape_job_running_key;
+alter table scrape_job drop column heartbeat_at;
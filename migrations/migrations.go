@@ -0,0 +1,34 @@
+// Package migrations has goose migrations of the database. They're embedded, so the
+// server knows which version of the schema it expects.
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+//go:embed *.sql
+var FS embed.FS
+
+// Latest is the version of the newest migration, e.g. 20261019140000
+func Latest() (int64, error) {
+	files, err := fs.Glob(FS, "*.sql")
+	if err != nil {
+		return 0, err
+	}
+
+	var latest int64
+	for _, file := range files {
+		version, err := strconv.ParseInt(strings.SplitN(file, "_", 2)[0], 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		if version > latest {
+			latest = version
+		}
+	}
+
+	return latest, nil
+}
@@ -0,0 +1,1289 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// GamelistAdminClient is the client API for GamelistAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type GamelistAdminClient interface {
+	CreateGame(ctx context.Context, in *Game, opts ...grpc.CallOption) (*Game, error)
+	GetGame(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*Game, error)
+	ListGames(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*GameList, error)
+	UpdateGame(ctx context.Context, in *Game, opts ...grpc.CallOption) (*Game, error)
+	DeleteGame(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*Empty, error)
+	RestoreGame(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*Empty, error)
+	CreateGenre(ctx context.Context, in *CatalogItem, opts ...grpc.CallOption) (*CatalogItem, error)
+	ListGenres(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*CatalogItemList, error)
+	UpdateGenre(ctx context.Context, in *CatalogItem, opts ...grpc.CallOption) (*CatalogItem, error)
+	DeleteGenre(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*Empty, error)
+	RestoreGenre(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*Empty, error)
+	CreatePlatform(ctx context.Context, in *CatalogItem, opts ...grpc.CallOption) (*CatalogItem, error)
+	ListPlatforms(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*CatalogItemList, error)
+	UpdatePlatform(ctx context.Context, in *CatalogItem, opts ...grpc.CallOption) (*CatalogItem, error)
+	DeletePlatform(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*Empty, error)
+	RestorePlatform(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*Empty, error)
+	CreateListType(ctx context.Context, in *CatalogItem, opts ...grpc.CallOption) (*CatalogItem, error)
+	ListListTypes(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*CatalogItemList, error)
+	UpdateListType(ctx context.Context, in *CatalogItem, opts ...grpc.CallOption) (*CatalogItem, error)
+	DeleteListType(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*Empty, error)
+	RestoreListType(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*Empty, error)
+	CreateSocialType(ctx context.Context, in *CatalogItem, opts ...grpc.CallOption) (*CatalogItem, error)
+	ListSocialTypes(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*CatalogItemList, error)
+	UpdateSocialType(ctx context.Context, in *CatalogItem, opts ...grpc.CallOption) (*CatalogItem, error)
+	DeleteSocialType(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*Empty, error)
+	RestoreSocialType(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*Empty, error)
+	StartScrapeJob(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ScrapeJob, error)
+	GetScrapeJob(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*ScrapeJob, error)
+	ListScrapeJobs(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ScrapeJobList, error)
+	CancelScrapeJob(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*Empty, error)
+	ListGameDuplicates(ctx context.Context, in *ListDuplicatesRequest, opts ...grpc.CallOption) (*GameDuplicateList, error)
+	ScanGameDuplicates(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ScanDuplicatesResponse, error)
+	DismissGameDuplicate(ctx context.Context, in *GameDuplicateRequest, opts ...grpc.CallOption) (*Empty, error)
+	MergeGames(ctx context.Context, in *MergeGamesRequest, opts ...grpc.CallOption) (*Empty, error)
+}
+
+type gamelistAdminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGamelistAdminClient(cc grpc.ClientConnInterface) GamelistAdminClient {
+	return &gamelistAdminClient{cc}
+}
+
+func (c *gamelistAdminClient) CreateGame(ctx context.Context, in *Game, opts ...grpc.CallOption) (*Game, error) {
+	out := new(Game)
+	err := c.cc.Invoke(ctx, "/proto.GamelistAdmin/CreateGame", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamelistAdminClient) GetGame(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*Game, error) {
+	out := new(Game)
+	err := c.cc.Invoke(ctx, "/proto.GamelistAdmin/GetGame", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamelistAdminClient) ListGames(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*GameList, error) {
+	out := new(GameList)
+	err := c.cc.Invoke(ctx, "/proto.GamelistAdmin/ListGames", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamelistAdminClient) UpdateGame(ctx context.Context, in *Game, opts ...grpc.CallOption) (*Game, error) {
+	out := new(Game)
+	err := c.cc.Invoke(ctx, "/proto.GamelistAdmin/UpdateGame", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamelistAdminClient) DeleteGame(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/proto.GamelistAdmin/DeleteGame", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamelistAdminClient) RestoreGame(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/proto.GamelistAdmin/RestoreGame", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamelistAdminClient) CreateGenre(ctx context.Context, in *CatalogItem, opts ...grpc.CallOption) (*CatalogItem, error) {
+	out := new(CatalogItem)
+	err := c.cc.Invoke(ctx, "/proto.GamelistAdmin/CreateGenre", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamelistAdminClient) ListGenres(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*CatalogItemList, error) {
+	out := new(CatalogItemList)
+	err := c.cc.Invoke(ctx, "/proto.GamelistAdmin/ListGenres", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamelistAdminClient) UpdateGenre(ctx context.Context, in *CatalogItem, opts ...grpc.CallOption) (*CatalogItem, error) {
+	out := new(CatalogItem)
+	err := c.cc.Invoke(ctx, "/proto.GamelistAdmin/UpdateGenre", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamelistAdminClient) DeleteGenre(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/proto.GamelistAdmin/DeleteGenre", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamelistAdminClient) RestoreGenre(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/proto.GamelistAdmin/RestoreGenre", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamelistAdminClient) CreatePlatform(ctx context.Context, in *CatalogItem, opts ...grpc.CallOption) (*CatalogItem, error) {
+	out := new(CatalogItem)
+	err := c.cc.Invoke(ctx, "/proto.GamelistAdmin/CreatePlatform", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamelistAdminClient) ListPlatforms(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*CatalogItemList, error) {
+	out := new(CatalogItemList)
+	err := c.cc.Invoke(ctx, "/proto.GamelistAdmin/ListPlatforms", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamelistAdminClient) UpdatePlatform(ctx context.Context, in *CatalogItem, opts ...grpc.CallOption) (*CatalogItem, error) {
+	out := new(CatalogItem)
+	err := c.cc.Invoke(ctx, "/proto.GamelistAdmin/UpdatePlatform", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamelistAdminClient) DeletePlatform(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/proto.GamelistAdmin/DeletePlatform", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamelistAdminClient) RestorePlatform(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/proto.GamelistAdmin/RestorePlatform", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamelistAdminClient) CreateListType(ctx context.Context, in *CatalogItem, opts ...grpc.CallOption) (*CatalogItem, error) {
+	out := new(CatalogItem)
+	err := c.cc.Invoke(ctx, "/proto.GamelistAdmin/CreateListType", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamelistAdminClient) ListListTypes(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*CatalogItemList, error) {
+	out := new(CatalogItemList)
+	err := c.cc.Invoke(ctx, "/proto.GamelistAdmin/ListListTypes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamelistAdminClient) UpdateListType(ctx context.Context, in *CatalogItem, opts ...grpc.CallOption) (*CatalogItem, error) {
+	out := new(CatalogItem)
+	err := c.cc.Invoke(ctx, "/proto.GamelistAdmin/UpdateListType", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamelistAdminClient) DeleteListType(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/proto.GamelistAdmin/DeleteListType", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamelistAdminClient) RestoreListType(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/proto.GamelistAdmin/RestoreListType", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamelistAdminClient) CreateSocialType(ctx context.Context, in *CatalogItem, opts ...grpc.CallOption) (*CatalogItem, error) {
+	out := new(CatalogItem)
+	err := c.cc.Invoke(ctx, "/proto.GamelistAdmin/CreateSocialType", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamelistAdminClient) ListSocialTypes(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*CatalogItemList, error) {
+	out := new(CatalogItemList)
+	err := c.cc.Invoke(ctx, "/proto.GamelistAdmin/ListSocialTypes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamelistAdminClient) UpdateSocialType(ctx context.Context, in *CatalogItem, opts ...grpc.CallOption) (*CatalogItem, error) {
+	out := new(CatalogItem)
+	err := c.cc.Invoke(ctx, "/proto.GamelistAdmin/UpdateSocialType", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamelistAdminClient) DeleteSocialType(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/proto.GamelistAdmin/DeleteSocialType", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamelistAdminClient) RestoreSocialType(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/proto.GamelistAdmin/RestoreSocialType", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamelistAdminClient) StartScrapeJob(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ScrapeJob, error) {
+	out := new(ScrapeJob)
+	err := c.cc.Invoke(ctx, "/proto.GamelistAdmin/StartScrapeJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamelistAdminClient) GetScrapeJob(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*ScrapeJob, error) {
+	out := new(ScrapeJob)
+	err := c.cc.Invoke(ctx, "/proto.GamelistAdmin/GetScrapeJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamelistAdminClient) ListScrapeJobs(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ScrapeJobList, error) {
+	out := new(ScrapeJobList)
+	err := c.cc.Invoke(ctx, "/proto.GamelistAdmin/ListScrapeJobs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamelistAdminClient) CancelScrapeJob(ctx context.Context, in *IdRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/proto.GamelistAdmin/CancelScrapeJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamelistAdminClient) ListGameDuplicates(ctx context.Context, in *ListDuplicatesRequest, opts ...grpc.CallOption) (*GameDuplicateList, error) {
+	out := new(GameDuplicateList)
+	err := c.cc.Invoke(ctx, "/proto.GamelistAdmin/ListGameDuplicates", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamelistAdminClient) ScanGameDuplicates(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ScanDuplicatesResponse, error) {
+	out := new(ScanDuplicatesResponse)
+	err := c.cc.Invoke(ctx, "/proto.GamelistAdmin/ScanGameDuplicates", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamelistAdminClient) DismissGameDuplicate(ctx context.Context, in *GameDuplicateRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/proto.GamelistAdmin/DismissGameDuplicate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gamelistAdminClient) MergeGames(ctx context.Context, in *MergeGamesRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/proto.GamelistAdmin/MergeGames", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GamelistAdminServer is the server API for GamelistAdmin service.
+// All implementations must embed UnimplementedGamelistAdminServer
+// for forward compatibility
+type GamelistAdminServer interface {
+	CreateGame(context.Context, *Game) (*Game, error)
+	GetGame(context.Context, *IdRequest) (*Game, error)
+	ListGames(context.Context, *ListRequest) (*GameList, error)
+	UpdateGame(context.Context, *Game) (*Game, error)
+	DeleteGame(context.Context, *DeleteRequest) (*Empty, error)
+	RestoreGame(context.Context, *IdRequest) (*Empty, error)
+	CreateGenre(context.Context, *CatalogItem) (*CatalogItem, error)
+	ListGenres(context.Context, *ListRequest) (*CatalogItemList, error)
+	UpdateGenre(context.Context, *CatalogItem) (*CatalogItem, error)
+	DeleteGenre(context.Context, *DeleteRequest) (*Empty, error)
+	RestoreGenre(context.Context, *IdRequest) (*Empty, error)
+	CreatePlatform(context.Context, *CatalogItem) (*CatalogItem, error)
+	ListPlatforms(context.Context, *ListRequest) (*CatalogItemList, error)
+	UpdatePlatform(context.Context, *CatalogItem) (*CatalogItem, error)
+	DeletePlatform(context.Context, *DeleteRequest) (*Empty, error)
+	RestorePlatform(context.Context, *IdRequest) (*Empty, error)
+	CreateListType(context.Context, *CatalogItem) (*CatalogItem, error)
+	ListListTypes(context.Context, *ListRequest) (*CatalogItemList, error)
+	UpdateListType(context.Context, *CatalogItem) (*CatalogItem, error)
+	DeleteListType(context.Context, *DeleteRequest) (*Empty, error)
+	RestoreListType(context.Context, *IdRequest) (*Empty, error)
+	CreateSocialType(context.Context, *CatalogItem) (*CatalogItem, error)
+	ListSocialTypes(context.Context, *ListRequest) (*CatalogItemList, error)
+	UpdateSocialType(context.Context, *CatalogItem) (*CatalogItem, error)
+	DeleteSocialType(context.Context, *DeleteRequest) (*Empty, error)
+	RestoreSocialType(context.Context, *IdRequest) (*Empty, error)
+	StartScrapeJob(context.Context, *Empty) (*ScrapeJob, error)
+	GetScrapeJob(context.Context, *IdRequest) (*ScrapeJob, error)
+	ListScrapeJobs(context.Context, *ListRequest) (*ScrapeJobList, error)
+	CancelScrapeJob(context.Context, *IdRequest) (*Empty, error)
+	ListGameDuplicates(context.Context, *ListDuplicatesRequest) (*GameDuplicateList, error)
+	ScanGameDuplicates(context.Context, *Empty) (*ScanDuplicatesResponse, error)
+	DismissGameDuplicate(context.Context, *GameDuplicateRequest) (*Empty, error)
+	MergeGames(context.Context, *MergeGamesRequest) (*Empty, error)
+	mustEmbedUnimplementedGamelistAdminServer()
+}
+
+// UnimplementedGamelistAdminServer must be embedded to have forward compatible implementations.
+type UnimplementedGamelistAdminServer struct {
+}
+
+func (UnimplementedGamelistAdminServer) CreateGame(context.Context, *Game) (*Game, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateGame not implemented")
+}
+func (UnimplementedGamelistAdminServer) GetGame(context.Context, *IdRequest) (*Game, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGame not implemented")
+}
+func (UnimplementedGamelistAdminServer) ListGames(context.Context, *ListRequest) (*GameList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListGames not implemented")
+}
+func (UnimplementedGamelistAdminServer) UpdateGame(context.Context, *Game) (*Game, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateGame not implemented")
+}
+func (UnimplementedGamelistAdminServer) DeleteGame(context.Context, *DeleteRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteGame not implemented")
+}
+func (UnimplementedGamelistAdminServer) RestoreGame(context.Context, *IdRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreGame not implemented")
+}
+func (UnimplementedGamelistAdminServer) CreateGenre(context.Context, *CatalogItem) (*CatalogItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateGenre not implemented")
+}
+func (UnimplementedGamelistAdminServer) ListGenres(context.Context, *ListRequest) (*CatalogItemList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListGenres not implemented")
+}
+func (UnimplementedGamelistAdminServer) UpdateGenre(context.Context, *CatalogItem) (*CatalogItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateGenre not implemented")
+}
+func (UnimplementedGamelistAdminServer) DeleteGenre(context.Context, *DeleteRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteGenre not implemented")
+}
+func (UnimplementedGamelistAdminServer) RestoreGenre(context.Context, *IdRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreGenre not implemented")
+}
+func (UnimplementedGamelistAdminServer) CreatePlatform(context.Context, *CatalogItem) (*CatalogItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePlatform not implemented")
+}
+func (UnimplementedGamelistAdminServer) ListPlatforms(context.Context, *ListRequest) (*CatalogItemList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPlatforms not implemented")
+}
+func (UnimplementedGamelistAdminServer) UpdatePlatform(context.Context, *CatalogItem) (*CatalogItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdatePlatform not implemented")
+}
+func (UnimplementedGamelistAdminServer) DeletePlatform(context.Context, *DeleteRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePlatform not implemented")
+}
+func (UnimplementedGamelistAdminServer) RestorePlatform(context.Context, *IdRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestorePlatform not implemented")
+}
+func (UnimplementedGamelistAdminServer) CreateListType(context.Context, *CatalogItem) (*CatalogItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateListType not implemented")
+}
+func (UnimplementedGamelistAdminServer) ListListTypes(context.Context, *ListRequest) (*CatalogItemList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListListTypes not implemented")
+}
+func (UnimplementedGamelistAdminServer) UpdateListType(context.Context, *CatalogItem) (*CatalogItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateListType not implemented")
+}
+func (UnimplementedGamelistAdminServer) DeleteListType(context.Context, *DeleteRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteListType not implemented")
+}
+func (UnimplementedGamelistAdminServer) RestoreListType(context.Context, *IdRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreListType not implemented")
+}
+func (UnimplementedGamelistAdminServer) CreateSocialType(context.Context, *CatalogItem) (*CatalogItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSocialType not implemented")
+}
+func (UnimplementedGamelistAdminServer) ListSocialTypes(context.Context, *ListRequest) (*CatalogItemList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSocialTypes not implemented")
+}
+func (UnimplementedGamelistAdminServer) UpdateSocialType(context.Context, *CatalogItem) (*CatalogItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateSocialType not implemented")
+}
+func (UnimplementedGamelistAdminServer) DeleteSocialType(context.Context, *DeleteRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSocialType not implemented")
+}
+func (UnimplementedGamelistAdminServer) RestoreSocialType(context.Context, *IdRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreSocialType not implemented")
+}
+func (UnimplementedGamelistAdminServer) StartScrapeJob(context.Context, *Empty) (*ScrapeJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartScrapeJob not implemented")
+}
+func (UnimplementedGamelistAdminServer) GetScrapeJob(context.Context, *IdRequest) (*ScrapeJob, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetScrapeJob not implemented")
+}
+func (UnimplementedGamelistAdminServer) ListScrapeJobs(context.Context, *ListRequest) (*ScrapeJobList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListScrapeJobs not implemented")
+}
+func (UnimplementedGamelistAdminServer) CancelScrapeJob(context.Context, *IdRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelScrapeJob not implemented")
+}
+func (UnimplementedGamelistAdminServer) ListGameDuplicates(context.Context, *ListDuplicatesRequest) (*GameDuplicateList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListGameDuplicates not implemented")
+}
+func (UnimplementedGamelistAdminServer) ScanGameDuplicates(context.Context, *Empty) (*ScanDuplicatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ScanGameDuplicates not implemented")
+}
+func (UnimplementedGamelistAdminServer) DismissGameDuplicate(context.Context, *GameDuplicateRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DismissGameDuplicate not implemented")
+}
+func (UnimplementedGamelistAdminServer) MergeGames(context.Context, *MergeGamesRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MergeGames not implemented")
+}
+func (UnimplementedGamelistAdminServer) mustEmbedUnimplementedGamelistAdminServer() {}
+
+// UnsafeGamelistAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GamelistAdminServer will
+// result in compilation errors.
+type UnsafeGamelistAdminServer interface {
+	mustEmbedUnimplementedGamelistAdminServer()
+}
+
+func RegisterGamelistAdminServer(s grpc.ServiceRegistrar, srv GamelistAdminServer) {
+	s.RegisterService(&GamelistAdmin_ServiceDesc, srv)
+}
+
+func _GamelistAdmin_CreateGame_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Game)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamelistAdminServer).CreateGame(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.GamelistAdmin/CreateGame",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamelistAdminServer).CreateGame(ctx, req.(*Game))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GamelistAdmin_GetGame_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamelistAdminServer).GetGame(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.GamelistAdmin/GetGame",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamelistAdminServer).GetGame(ctx, req.(*IdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GamelistAdmin_ListGames_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamelistAdminServer).ListGames(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.GamelistAdmin/ListGames",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamelistAdminServer).ListGames(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GamelistAdmin_UpdateGame_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Game)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamelistAdminServer).UpdateGame(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.GamelistAdmin/UpdateGame",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamelistAdminServer).UpdateGame(ctx, req.(*Game))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GamelistAdmin_DeleteGame_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamelistAdminServer).DeleteGame(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.GamelistAdmin/DeleteGame",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamelistAdminServer).DeleteGame(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GamelistAdmin_RestoreGame_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamelistAdminServer).RestoreGame(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.GamelistAdmin/RestoreGame",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamelistAdminServer).RestoreGame(ctx, req.(*IdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GamelistAdmin_CreateGenre_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CatalogItem)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamelistAdminServer).CreateGenre(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.GamelistAdmin/CreateGenre",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamelistAdminServer).CreateGenre(ctx, req.(*CatalogItem))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GamelistAdmin_ListGenres_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamelistAdminServer).ListGenres(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.GamelistAdmin/ListGenres",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamelistAdminServer).ListGenres(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GamelistAdmin_UpdateGenre_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CatalogItem)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamelistAdminServer).UpdateGenre(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.GamelistAdmin/UpdateGenre",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamelistAdminServer).UpdateGenre(ctx, req.(*CatalogItem))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GamelistAdmin_DeleteGenre_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamelistAdminServer).DeleteGenre(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.GamelistAdmin/DeleteGenre",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamelistAdminServer).DeleteGenre(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GamelistAdmin_RestoreGenre_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamelistAdminServer).RestoreGenre(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.GamelistAdmin/RestoreGenre",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamelistAdminServer).RestoreGenre(ctx, req.(*IdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GamelistAdmin_CreatePlatform_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CatalogItem)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamelistAdminServer).CreatePlatform(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.GamelistAdmin/CreatePlatform",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamelistAdminServer).CreatePlatform(ctx, req.(*CatalogItem))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GamelistAdmin_ListPlatforms_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamelistAdminServer).ListPlatforms(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.GamelistAdmin/ListPlatforms",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamelistAdminServer).ListPlatforms(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GamelistAdmin_UpdatePlatform_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CatalogItem)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamelistAdminServer).UpdatePlatform(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.GamelistAdmin/UpdatePlatform",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamelistAdminServer).UpdatePlatform(ctx, req.(*CatalogItem))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GamelistAdmin_DeletePlatform_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamelistAdminServer).DeletePlatform(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.GamelistAdmin/DeletePlatform",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamelistAdminServer).DeletePlatform(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GamelistAdmin_RestorePlatform_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamelistAdminServer).RestorePlatform(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.GamelistAdmin/RestorePlatform",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamelistAdminServer).RestorePlatform(ctx, req.(*IdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GamelistAdmin_CreateListType_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CatalogItem)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamelistAdminServer).CreateListType(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.GamelistAdmin/CreateListType",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamelistAdminServer).CreateListType(ctx, req.(*CatalogItem))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GamelistAdmin_ListListTypes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamelistAdminServer).ListListTypes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.GamelistAdmin/ListListTypes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamelistAdminServer).ListListTypes(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GamelistAdmin_UpdateListType_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CatalogItem)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamelistAdminServer).UpdateListType(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.GamelistAdmin/UpdateListType",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamelistAdminServer).UpdateListType(ctx, req.(*CatalogItem))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GamelistAdmin_DeleteListType_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamelistAdminServer).DeleteListType(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.GamelistAdmin/DeleteListType",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamelistAdminServer).DeleteListType(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GamelistAdmin_RestoreListType_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamelistAdminServer).RestoreListType(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.GamelistAdmin/RestoreListType",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamelistAdminServer).RestoreListType(ctx, req.(*IdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GamelistAdmin_CreateSocialType_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CatalogItem)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamelistAdminServer).CreateSocialType(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.GamelistAdmin/CreateSocialType",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamelistAdminServer).CreateSocialType(ctx, req.(*CatalogItem))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GamelistAdmin_ListSocialTypes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamelistAdminServer).ListSocialTypes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.GamelistAdmin/ListSocialTypes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamelistAdminServer).ListSocialTypes(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GamelistAdmin_UpdateSocialType_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CatalogItem)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamelistAdminServer).UpdateSocialType(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.GamelistAdmin/UpdateSocialType",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamelistAdminServer).UpdateSocialType(ctx, req.(*CatalogItem))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GamelistAdmin_DeleteSocialType_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamelistAdminServer).DeleteSocialType(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.GamelistAdmin/DeleteSocialType",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamelistAdminServer).DeleteSocialType(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GamelistAdmin_RestoreSocialType_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamelistAdminServer).RestoreSocialType(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.GamelistAdmin/RestoreSocialType",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamelistAdminServer).RestoreSocialType(ctx, req.(*IdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GamelistAdmin_StartScrapeJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamelistAdminServer).StartScrapeJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.GamelistAdmin/StartScrapeJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamelistAdminServer).StartScrapeJob(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GamelistAdmin_GetScrapeJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamelistAdminServer).GetScrapeJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.GamelistAdmin/GetScrapeJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamelistAdminServer).GetScrapeJob(ctx, req.(*IdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GamelistAdmin_ListScrapeJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamelistAdminServer).ListScrapeJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.GamelistAdmin/ListScrapeJobs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamelistAdminServer).ListScrapeJobs(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GamelistAdmin_CancelScrapeJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamelistAdminServer).CancelScrapeJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.GamelistAdmin/CancelScrapeJob",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamelistAdminServer).CancelScrapeJob(ctx, req.(*IdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GamelistAdmin_ListGameDuplicates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDuplicatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamelistAdminServer).ListGameDuplicates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.GamelistAdmin/ListGameDuplicates",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamelistAdminServer).ListGameDuplicates(ctx, req.(*ListDuplicatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GamelistAdmin_ScanGameDuplicates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamelistAdminServer).ScanGameDuplicates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.GamelistAdmin/ScanGameDuplicates",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamelistAdminServer).ScanGameDuplicates(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GamelistAdmin_DismissGameDuplicate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GameDuplicateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamelistAdminServer).DismissGameDuplicate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.GamelistAdmin/DismissGameDuplicate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamelistAdminServer).DismissGameDuplicate(ctx, req.(*GameDuplicateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GamelistAdmin_MergeGames_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MergeGamesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GamelistAdminServer).MergeGames(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.GamelistAdmin/MergeGames",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GamelistAdminServer).MergeGames(ctx, req.(*MergeGamesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// GamelistAdmin_ServiceDesc is the grpc.ServiceDesc for GamelistAdmin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var GamelistAdmin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "proto.GamelistAdmin",
+	HandlerType: (*GamelistAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateGame",
+			Handler:    _GamelistAdmin_CreateGame_Handler,
+		},
+		{
+			MethodName: "GetGame",
+			Handler:    _GamelistAdmin_GetGame_Handler,
+		},
+		{
+			MethodName: "ListGames",
+			Handler:    _GamelistAdmin_ListGames_Handler,
+		},
+		{
+			MethodName: "UpdateGame",
+			Handler:    _GamelistAdmin_UpdateGame_Handler,
+		},
+		{
+			MethodName: "DeleteGame",
+			Handler:    _GamelistAdmin_DeleteGame_Handler,
+		},
+		{
+			MethodName: "RestoreGame",
+			Handler:    _GamelistAdmin_RestoreGame_Handler,
+		},
+		{
+			MethodName: "CreateGenre",
+			Handler:    _GamelistAdmin_CreateGenre_Handler,
+		},
+		{
+			MethodName: "ListGenres",
+			Handler:    _GamelistAdmin_ListGenres_Handler,
+		},
+		{
+			MethodName: "UpdateGenre",
+			Handler:    _GamelistAdmin_UpdateGenre_Handler,
+		},
+		{
+			MethodName: "DeleteGenre",
+			Handler:    _GamelistAdmin_DeleteGenre_Handler,
+		},
+		{
+			MethodName: "RestoreGenre",
+			Handler:    _GamelistAdmin_RestoreGenre_Handler,
+		},
+		{
+			MethodName: "CreatePlatform",
+			Handler:    _GamelistAdmin_CreatePlatform_Handler,
+		},
+		{
+			MethodName: "ListPlatforms",
+			Handler:    _GamelistAdmin_ListPlatforms_Handler,
+		},
+		{
+			MethodName: "UpdatePlatform",
+			Handler:    _GamelistAdmin_UpdatePlatform_Handler,
+		},
+		{
+			MethodName: "DeletePlatform",
+			Handler:    _GamelistAdmin_DeletePlatform_Handler,
+		},
+		{
+			MethodName: "RestorePlatform",
+			Handler:    _GamelistAdmin_RestorePlatform_Handler,
+		},
+		{
+			MethodName: "CreateListType",
+			Handler:    _GamelistAdmin_CreateListType_Handler,
+		},
+		{
+			MethodName: "ListListTypes",
+			Handler:    _GamelistAdmin_ListListTypes_Handler,
+		},
+		{
+			MethodName: "UpdateListType",
+			Handler:    _GamelistAdmin_UpdateListType_Handler,
+		},
+		{
+			MethodName: "DeleteListType",
+			Handler:    _GamelistAdmin_DeleteListType_Handler,
+		},
+		{
+			MethodName: "RestoreListType",
+			Handler:    _GamelistAdmin_RestoreListType_Handler,
+		},
+		{
+			MethodName: "CreateSocialType",
+			Handler:    _GamelistAdmin_CreateSocialType_Handler,
+		},
+		{
+			MethodName: "ListSocialTypes",
+			Handler:    _GamelistAdmin_ListSocialTypes_Handler,
+		},
+		{
+			MethodName: "UpdateSocialType",
+			Handler:    _GamelistAdmin_UpdateSocialType_Handler,
+		},
+		{
+			MethodName: "DeleteSocialType",
+			Handler:    _GamelistAdmin_DeleteSocialType_Handler,
+		},
+		{
+			MethodName: "RestoreSocialType",
+			Handler:    _GamelistAdmin_RestoreSocialType_Handler,
+		},
+		{
+			MethodName: "StartScrapeJob",
+			Handler:    _GamelistAdmin_StartScrapeJob_Handler,
+		},
+		{
+			MethodName: "GetScrapeJob",
+			Handler:    _GamelistAdmin_GetScrapeJob_Handler,
+		},
+		{
+			MethodName: "ListScrapeJobs",
+			Handler:    _GamelistAdmin_ListScrapeJobs_Handler,
+		},
+		{
+			MethodName: "CancelScrapeJob",
+			Handler:    _GamelistAdmin_CancelScrapeJob_Handler,
+		},
+		{
+			MethodName: "ListGameDuplicates",
+			Handler:    _GamelistAdmin_ListGameDuplicates_Handler,
+		},
+		{
+			MethodName: "ScanGameDuplicates",
+			Handler:    _GamelistAdmin_ScanGameDuplicates_Handler,
+		},
+		{
+			MethodName: "DismissGameDuplicate",
+			Handler:    _GamelistAdmin_DismissGameDuplicate_Handler,
+		},
+		{
+			MethodName: "MergeGames",
+			Handler:    _GamelistAdmin_MergeGames_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "admin.proto",
+}
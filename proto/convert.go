@@ -30,3 +30,83 @@ func (g *GameProperties) ConvertToEntity() entity.GameProperties {
 		Genres:       genres,
 	}
 }
+
+// SourceID returns a stable id of the game on the scraped site.
+// Falls back to the game's name for scrapers that don't send external ids.
+func (g *GameProperties) SourceID() string {
+	if g.ExternalId != "" {
+		return g.ExternalId
+	}
+	return g.Name
+}
+
+func (g *Game) ConvertToEntity() entity.GameProperties {
+	platforms := make([]entity.Platform, len(g.Platforms))
+	for i := range g.Platforms {
+		platforms[i] = entity.Platform{Name: g.Platforms[i]}
+	}
+	genres := make([]entity.Genre, len(g.Genres))
+	for i := range g.Genres {
+		genres[i] = entity.Genre{Name: g.Genres[i]}
+	}
+
+	return entity.GameProperties{
+		Model:        entity.Model{ID: g.Id},
+		Name:         g.Name,
+		Platforms:    platforms,
+		YearReleased: uint16(g.YearReleased),
+		ImageURL:     g.ImageUrl,
+		Genres:       genres,
+	}
+}
+
+func GameFromEntity(game *entity.GameProperties) *Game {
+	platforms := make([]string, len(game.Platforms))
+	for i := range game.Platforms {
+		platforms[i] = game.Platforms[i].Name
+	}
+	genres := make([]string, len(game.Genres))
+	for i := range game.Genres {
+		genres[i] = game.Genres[i].Name
+	}
+
+	return &Game{
+		Id:           game.ID,
+		Name:         game.Name,
+		ImageUrl:     game.ImageURL,
+		YearReleased: uint32(game.YearReleased),
+		Platforms:    platforms,
+		Genres:       genres,
+	}
+}
+
+func ScrapeJobFromEntity(job *entity.ScrapeJob) *ScrapeJob {
+	var finishedAt int64
+	if job.FinishedAt != nil {
+		finishedAt = job.FinishedAt.Unix()
+	}
+
+	return &ScrapeJob{
+		Id:         job.ID,
+		Trigger:    job.Trigger,
+		Status:     job.Status,
+		StartedAt:  job.StartedAt.Unix(),
+		FinishedAt: finishedAt,
+		Stats: &ScrapeStats{
+			Inserted: job.Stats.Inserted,
+			Updated:  job.Stats.Updated,
+			Skipped:  job.Stats.Skipped,
+			Failed:   job.Stats.Failed,
+		},
+		Error: job.Error,
+	}
+}
+
+func GameDuplicateFromEntity(duplicate *entity.GameDuplicate) *GameDuplicate {
+	return &GameDuplicate{
+		Game:      GameFromEntity(&duplicate.Game),
+		Duplicate: GameFromEntity(&duplicate.Duplicate),
+		Score:     duplicate.Score,
+		Status:    duplicate.Status,
+	}
+}
@@ -30,6 +30,8 @@ type GameProperties struct {
 	YearReleased uint32   `protobuf:"varint,3,opt,name=year_released,json=yearReleased,proto3" json:"year_released,omitempty"`
 	ImageUrl     string   `protobuf:"bytes,4,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
 	Genres       []*Named `protobuf:"bytes,5,rep,name=genres,proto3" json:"genres,omitempty"`
+	// Stable ID of the game on the scraped site. Older scrapers may leave it empty.
+	ExternalId string `protobuf:"bytes,6,opt,name=external_id,json=externalId,proto3" json:"external_id,omitempty"`
 }
 
 func (x *GameProperties) Reset() {
@@ -99,6 +101,61 @@ func (x *GameProperties) GetGenres() []*Named {
 	return nil
 }
 
+func (x *GameProperties) GetExternalId() string {
+	if x != nil {
+		return x.ExternalId
+	}
+	return ""
+}
+
+type ScrapeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// External id of the last received game. The scraper resumes right after it.
+	After string `protobuf:"bytes,1,opt,name=after,proto3" json:"after,omitempty"`
+}
+
+func (x *ScrapeRequest) Reset() {
+	*x = ScrapeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gamelist_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ScrapeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScrapeRequest) ProtoMessage() {}
+
+func (x *ScrapeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gamelist_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScrapeRequest.ProtoReflect.Descriptor instead.
+func (*ScrapeRequest) Descriptor() ([]byte, []int) {
+	return file_gamelist_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ScrapeRequest) GetAfter() string {
+	if x != nil {
+		return x.After
+	}
+	return ""
+}
+
 type Named struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -110,7 +167,7 @@ type Named struct {
 func (x *Named) Reset() {
 	*x = Named{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_gamelist_proto_msgTypes[1]
+		mi := &file_gamelist_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -123,7 +180,7 @@ func (x *Named) String() string {
 func (*Named) ProtoMessage() {}
 
 func (x *Named) ProtoReflect() protoreflect.Message {
-	mi := &file_gamelist_proto_msgTypes[1]
+	mi := &file_gamelist_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -136,7 +193,7 @@ func (x *Named) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Named.ProtoReflect.Descriptor instead.
 func (*Named) Descriptor() ([]byte, []int) {
-	return file_gamelist_proto_rawDescGZIP(), []int{1}
+	return file_gamelist_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *Named) GetName() string {
@@ -155,7 +212,7 @@ type Empty struct {
 func (x *Empty) Reset() {
 	*x = Empty{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_gamelist_proto_msgTypes[2]
+		mi := &file_gamelist_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -168,7 +225,7 @@ func (x *Empty) String() string {
 func (*Empty) ProtoMessage() {}
 
 func (x *Empty) ProtoReflect() protoreflect.Message {
-	mi := &file_gamelist_proto_msgTypes[2]
+	mi := &file_gamelist_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -181,14 +238,14 @@ func (x *Empty) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Empty.ProtoReflect.Descriptor instead.
 func (*Empty) Descriptor() ([]byte, []int) {
-	return file_gamelist_proto_rawDescGZIP(), []int{2}
+	return file_gamelist_proto_rawDescGZIP(), []int{3}
 }
 
 var File_gamelist_proto protoreflect.FileDescriptor
 
 var file_gamelist_proto_rawDesc = []byte{
 	0x0a, 0x0e, 0x67, 0x61, 0x6d, 0x65, 0x6c, 0x69, 0x73, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x12, 0x05, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xb8, 0x01, 0x0a, 0x0e, 0x47, 0x61, 0x6d, 0x65,
+	0x12, 0x05, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xd9, 0x01, 0x0a, 0x0e, 0x47, 0x61, 0x6d, 0x65,
 	0x50, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x69, 0x65, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
 	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2a,
 	0x0a, 0x09, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
@@ -200,12 +257,17 @@ var file_gamelist_proto_rawDesc = []byte{
 	0x28, 0x09, 0x52, 0x08, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x55, 0x72, 0x6c, 0x12, 0x24, 0x0a, 0x06,
 	0x67, 0x65, 0x6e, 0x72, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x70,
 	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4e, 0x61, 0x6d, 0x65, 0x64, 0x52, 0x06, 0x67, 0x65, 0x6e, 0x72,
-	0x65, 0x73, 0x22, 0x1b, 0x0a, 0x05, 0x4e, 0x61, 0x6d, 0x65, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e,
-	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22,
-	0x07, 0x0a, 0x05, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x32, 0x42, 0x0a, 0x0a, 0x47, 0x61, 0x6d, 0x65,
-	0x53, 0x63, 0x72, 0x61, 0x70, 0x65, 0x12, 0x34, 0x0a, 0x0b, 0x53, 0x63, 0x72, 0x61, 0x70, 0x65,
-	0x47, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x45, 0x6d,
-	0x70, 0x74, 0x79, 0x1a, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x61, 0x6d, 0x65,
+	0x65, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x65, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x5f, 0x69,
+	0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x65, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61,
+	0x6c, 0x49, 0x64, 0x22, 0x25, 0x0a, 0x0d, 0x53, 0x63, 0x72, 0x61, 0x70, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x66, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x61, 0x66, 0x74, 0x65, 0x72, 0x22, 0x1b, 0x0a, 0x05, 0x4e, 0x61,
+	0x6d, 0x65, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x07, 0x0a, 0x05, 0x45, 0x6d, 0x70, 0x74, 0x79,
+	0x32, 0x4a, 0x0a, 0x0a, 0x47, 0x61, 0x6d, 0x65, 0x53, 0x63, 0x72, 0x61, 0x70, 0x65, 0x12, 0x3c,
+	0x0a, 0x0b, 0x53, 0x63, 0x72, 0x61, 0x70, 0x65, 0x47, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x14, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x53, 0x63, 0x72, 0x61, 0x70, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x61, 0x6d, 0x65,
 	0x50, 0x72, 0x6f, 0x70, 0x65, 0x72, 0x74, 0x69, 0x65, 0x73, 0x30, 0x01, 0x42, 0x2b, 0x5a, 0x29,
 	0x62, 0x69, 0x74, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x2e, 0x6f, 0x72, 0x67, 0x2f, 0x62, 0x72,
 	0x33, 0x77, 0x30, 0x72, 0x2f, 0x67, 0x61, 0x6d, 0x65, 0x6c, 0x69, 0x73, 0x74, 0x2d, 0x70, 0x72,
@@ -225,16 +287,17 @@ func file_gamelist_proto_rawDescGZIP() []byte {
 	return file_gamelist_proto_rawDescData
 }
 
-var file_gamelist_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_gamelist_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
 var file_gamelist_proto_goTypes = []interface{}{
 	(*GameProperties)(nil), // 0: proto.GameProperties
-	(*Named)(nil),          // 1: proto.Named
-	(*Empty)(nil),          // 2: proto.Empty
+	(*ScrapeRequest)(nil),  // 1: proto.ScrapeRequest
+	(*Named)(nil),          // 2: proto.Named
+	(*Empty)(nil),          // 3: proto.Empty
 }
 var file_gamelist_proto_depIdxs = []int32{
-	1, // 0: proto.GameProperties.platforms:type_name -> proto.Named
-	1, // 1: proto.GameProperties.genres:type_name -> proto.Named
-	2, // 2: proto.GameScrape.ScrapeGames:input_type -> proto.Empty
+	2, // 0: proto.GameProperties.platforms:type_name -> proto.Named
+	2, // 1: proto.GameProperties.genres:type_name -> proto.Named
+	1, // 2: proto.GameScrape.ScrapeGames:input_type -> proto.ScrapeRequest
 	0, // 3: proto.GameScrape.ScrapeGames:output_type -> proto.GameProperties
 	3, // [3:4] is the sub-list for method output_type
 	2, // [2:3] is the sub-list for method input_type
@@ -262,7 +325,7 @@ func file_gamelist_proto_init() {
 			}
 		}
 		file_gamelist_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Named); i {
+			switch v := v.(*ScrapeRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -274,6 +337,18 @@ func file_gamelist_proto_init() {
 			}
 		}
 		file_gamelist_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Named); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_gamelist_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*Empty); i {
 			case 0:
 				return &v.state
@@ -292,7 +367,7 @@ func file_gamelist_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_gamelist_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   4,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
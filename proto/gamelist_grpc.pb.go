@@ -18,7 +18,7 @@ const _ = grpc.SupportPackageIsVersion7
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type GameScrapeClient interface {
-	ScrapeGames(ctx context.Context, in *Empty, opts ...grpc.CallOption) (GameScrape_ScrapeGamesClient, error)
+	ScrapeGames(ctx context.Context, in *ScrapeRequest, opts ...grpc.CallOption) (GameScrape_ScrapeGamesClient, error)
 }
 
 type gameScrapeClient struct {
@@ -29,7 +29,7 @@ func NewGameScrapeClient(cc grpc.ClientConnInterface) GameScrapeClient {
 	return &gameScrapeClient{cc}
 }
 
-func (c *gameScrapeClient) ScrapeGames(ctx context.Context, in *Empty, opts ...grpc.CallOption) (GameScrape_ScrapeGamesClient, error) {
+func (c *gameScrapeClient) ScrapeGames(ctx context.Context, in *ScrapeRequest, opts ...grpc.CallOption) (GameScrape_ScrapeGamesClient, error) {
 	stream, err := c.cc.NewStream(ctx, &GameScrape_ServiceDesc.Streams[0], "/proto.GameScrape/ScrapeGames", opts...)
 	if err != nil {
 		return nil, err
@@ -65,7 +65,7 @@ func (x *gameScrapeScrapeGamesClient) Recv() (*GameProperties, error) {
 // All implementations must embed UnimplementedGameScrapeServer
 // for forward compatibility
 type GameScrapeServer interface {
-	ScrapeGames(*Empty, GameScrape_ScrapeGamesServer) error
+	ScrapeGames(*ScrapeRequest, GameScrape_ScrapeGamesServer) error
 	mustEmbedUnimplementedGameScrapeServer()
 }
 
@@ -73,7 +73,7 @@ type GameScrapeServer interface {
 type UnimplementedGameScrapeServer struct {
 }
 
-func (UnimplementedGameScrapeServer) ScrapeGames(*Empty, GameScrape_ScrapeGamesServer) error {
+func (UnimplementedGameScrapeServer) ScrapeGames(*ScrapeRequest, GameScrape_ScrapeGamesServer) error {
 	return status.Errorf(codes.Unimplemented, "method ScrapeGames not implemented")
 }
 func (UnimplementedGameScrapeServer) mustEmbedUnimplementedGameScrapeServer() {}
@@ -90,7 +90,7 @@ func RegisterGameScrapeServer(s grpc.ServiceRegistrar, srv GameScrapeServer) {
 }
 
 func _GameScrape_ScrapeGames_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(Empty)
+	m := new(ScrapeRequest)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
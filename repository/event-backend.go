@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	utilErrs "github.com/br3w0r/gamelist-backend/util/errors"
+	utilLogger "github.com/br3w0r/gamelist-backend/util/logger"
+	"github.com/jackc/pgx/v4"
+)
+
+var eventsLog = utilLogger.For(utilLogger.SubsystemEvents)
+
+const (
+	EVENTS_CHANNEL = "gamelist_events"
+
+	// Payloads of NOTIFY are limited to 8000 bytes by default
+	PG_NOTIFY_MAX_PAYLOAD = 8000
+	PG_LISTEN_RETRY_DELAY = 5 * time.Second
+)
+
+// EventBackend carries events between instances of the server
+type EventBackend interface {
+	Publish(payload []byte) error
+	// Listen passes payloads published by any instance to deliver until ctx is done
+	Listen(ctx context.Context, deliver func(payload []byte)) error
+}
+
+// localEventBackend delivers payloads of a single instance. Payloads published
+// before Listen starts are kept until then.
+type localEventBackend struct {
+	mu      sync.Mutex
+	deliver func(payload []byte)
+	pending [][]byte
+}
+
+func NewLocalEventBackend() EventBackend {
+	return &localEventBackend{}
+}
+
+func (b *localEventBackend) Publish(payload []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.deliver == nil {
+		b.pending = append(b.pending, payload)
+		return nil
+	}
+	b.deliver(payload)
+
+	return nil
+}
+
+func (b *localEventBackend) Listen(ctx context.Context, deliver func(payload []byte)) error {
+	b.mu.Lock()
+	b.deliver = deliver
+	for _, payload := range b.pending {
+		deliver(payload)
+	}
+	b.pending = nil
+	b.mu.Unlock()
+
+	<-ctx.Done()
+
+	b.mu.Lock()
+	b.deliver = nil
+	b.mu.Unlock()
+
+	return nil
+}
+
+// pgEventBackend fans payloads out to all instances through LISTEN/NOTIFY.
+// Payloads published while the listener reconnects are lost.
+type pgEventBackend struct {
+	dsn     string
+	channel string
+
+	mu   sync.Mutex
+	conn *pgx.Conn
+}
+
+func NewPgEventBackend(conf *DBConfig, channel string) EventBackend {
+	if channel == "" {
+		channel = EVENTS_CHANNEL
+	}
+
+	return &pgEventBackend{
+		dsn:     conf.DSN(),
+		channel: channel,
+	}
+}
+
+func (b *pgEventBackend) Publish(payload []byte) error {
+	if len(payload) >= PG_NOTIFY_MAX_PAYLOAD {
+		return utilErrs.Newf(utilErrs.BadInput, nil, "event payload of %d bytes is too large", len(payload))
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ctx := context.Background()
+	if b.conn == nil || b.conn.IsClosed() {
+		conn, err := pgx.Connect(ctx, b.dsn)
+		if err != nil {
+			return utilErrs.New(utilErrs.Internal, err, "failed to connect to publish event")
+		}
+		b.conn = conn
+	}
+
+	if _, err := b.conn.Exec(ctx, "SELECT pg_notify($1, $2)", b.channel, string(payload)); err != nil {
+		b.conn.Close(ctx) //nolint:errcheck
+		b.conn = nil
+		return utilErrs.New(utilErrs.Internal, err, "failed to publish event")
+	}
+
+	return nil
+}
+
+func (b *pgEventBackend) Listen(ctx context.Context, deliver func(payload []byte)) error {
+	for {
+		err := b.listen(ctx, deliver)
+		if ctx.Err() != nil {
+			return nil
+		}
+		eventsLog.Warn("event listener stopped", "error", err, "cause", errors.Unwrap(err), "retry_in", PG_LISTEN_RETRY_DELAY)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(PG_LISTEN_RETRY_DELAY):
+		}
+	}
+}
+
+func (b *pgEventBackend) listen(ctx context.Context, deliver func(payload []byte)) error {
+	conn, err := pgx.Connect(ctx, b.dsn)
+	if err != nil {
+		return utilErrs.New(utilErrs.Internal, err, "failed to connect to listen for events")
+	}
+	defer conn.Close(context.Background()) //nolint:errcheck
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{b.channel}.Sanitize()); err != nil {
+		return utilErrs.New(utilErrs.Internal, err, "failed to listen for events")
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return utilErrs.New(utilErrs.Internal, err, "failed to wait for events")
+		}
+		deliver([]byte(notification.Payload))
+	}
+}
@@ -1,12 +1,15 @@
 package repository
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"time"
 
 	"github.com/br3w0r/gamelist-backend/entity"
 	utilErrs "github.com/br3w0r/gamelist-backend/util/errors"
 	utilLogger "github.com/br3w0r/gamelist-backend/util/logger"
+	"github.com/br3w0r/gamelist-backend/util/metrics"
+	"github.com/uptrace/opentelemetry-go-extra/otelgorm"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -14,39 +17,110 @@ import (
 )
 
 type GamelistRepository interface {
-	SaveGame(game entity.GameProperties) error
-	GetAllGames() ([]entity.GameProperties, error)
-	GetAllGamesTyped(nickname string, last uint64, batchSize int) ([]entity.TypedGameListProperties, error)
-	GetUserGameList(nickname string) ([]entity.TypedGameListProperties, error)
-	SearchGames(name string) ([]entity.GameSearchResult, error)
-	GetGameDetails(nickname string, id uint64) (*entity.GameDetailsResponse, error)
-
-	CreateListType(listType entity.ListType) error
-	GetAllListTypes() ([]entity.ListType, error)
-	ListGame(nickname string, gameId uint64, listType uint64) error
-
-	SaveGenre(genre entity.Genre) error
-	GetAllGenres() ([]entity.Genre, error)
-
-	SavePlatform(platform entity.Platform) error
-	GetAllPlatforms() ([]entity.Platform, error)
-
-	CreateProfile(profile entity.Profile) error
-	SaveProfile(profile entity.Profile) error
-	GetAllProfiles() ([]entity.ProfileInfo, error)
-	GetProfile(login entity.ProfileCreds) (*entity.Profile, error)
-
-	SaveRefreshToken(nickname string, tokenString string) error
-	FindRefreshToken(nickname string, tokenString string) error
-	DeleteRefreshToken(tokenString string) error
-	DeleteAllUserRefreshTokens(nickname string) error
-
-	SaveSocialType(socialType entity.SocialType) error
-	GetAllSocialTypes() ([]entity.SocialType, error)
+	SaveGame(ctx context.Context, game *entity.GameProperties) error
+	UpdateGame(ctx context.Context, game *entity.GameProperties) error
+	DeleteGame(ctx context.Context, id uint64, reassignTo uint64) error
+	RestoreGame(ctx context.Context, id uint64) error
+	GetGame(ctx context.Context, id uint64) (*entity.GameProperties, error)
+	// List queries take items after the cursor. They return one item more than the limit
+	// to tell if there's a next page. Limits below 1 return all items.
+	GetGames(ctx context.Context, cursor entity.Cursor, limit int) ([]entity.GameProperties, error)
+	UpsertGame(ctx context.Context, sourced entity.SourcedGame, priority entity.SourcePriority) (entity.IngestResult, error)
+	GetAllGamesTyped(ctx context.Context, nickname string, name string, cursor entity.Cursor, limit int) ([]entity.TypedGameListProperties, error)
+	// GetUserGameList is sorted by game names
+	GetUserGameList(ctx context.Context, nickname string, cursor entity.Cursor, limit int) ([]entity.TypedGameListProperties, error)
+	// GetGameListsOfProfiles gets the same page of the lists of several profiles at once
+	GetGameListsOfProfiles(ctx context.Context, profileIDs []uint64, cursor entity.Cursor, limit int) (map[uint64][]entity.TypedGameListProperties, error)
+	SearchGames(ctx context.Context, name string) ([]entity.GameSearchResult, error)
+	GetGameTitles(ctx context.Context) ([]entity.GameTitle, error)
+	GetGameDetails(ctx context.Context, nickname string, id uint64) (*entity.GameDetailsResponse, error)
+	// Batch lookups are keyed by the given ids, unknown ids are left out.
+	// GetGamesByIDs follows redirects of merged games.
+	GetGamesByIDs(ctx context.Context, ids []uint64) (map[uint64]entity.GameProperties, error)
+	GetPlatformsOfGames(ctx context.Context, gameIDs []uint64) (map[uint64][]entity.Platform, error)
+	GetGenresOfGames(ctx context.Context, gameIDs []uint64) (map[uint64][]entity.Genre, error)
+	// GetListTypesOfGames gets list types of the games in the user's list
+	GetListTypesOfGames(ctx context.Context, nickname string, gameIDs []uint64) (map[uint64]uint64, error)
+
+	SaveGameDuplicates(ctx context.Context, duplicates []entity.GameDuplicate) error
+	GetGameDuplicates(ctx context.Context, status string, limit int) ([]entity.GameDuplicate, error)
+	DismissGameDuplicate(ctx context.Context, gameID uint64, duplicateID uint64) error
+	MergeGames(ctx context.Context, survivorID uint64, duplicateID uint64) error
+
+	CreateListType(ctx context.Context, listType *entity.ListType) error
+	UpdateListType(ctx context.Context, listType *entity.ListType) error
+	DeleteListType(ctx context.Context, id uint64, reassignTo uint64) error
+	RestoreListType(ctx context.Context, id uint64) error
+	GetAllListTypes(ctx context.Context, cursor entity.Cursor, limit int) ([]entity.ListType, error)
+	GetListTypesByIDs(ctx context.Context, ids []uint64) (map[uint64]entity.ListType, error)
+	// ListGame sets the list type of the game in the user's list, 0 removes the game from it
+	ListGame(ctx context.Context, nickname string, gameId uint64, listType uint64) error
+	ListGames(ctx context.Context, nickname string, entries []entity.GameListRequest) error
+
+	SaveGenre(ctx context.Context, genre *entity.Genre) error
+	UpdateGenre(ctx context.Context, genre *entity.Genre) error
+	DeleteGenre(ctx context.Context, id uint64, reassignTo uint64) error
+	RestoreGenre(ctx context.Context, id uint64) error
+	GetAllGenres(ctx context.Context, cursor entity.Cursor, limit int) ([]entity.Genre, error)
+
+	SavePlatform(ctx context.Context, platform *entity.Platform) error
+	UpdatePlatform(ctx context.Context, platform *entity.Platform) error
+	DeletePlatform(ctx context.Context, id uint64, reassignTo uint64) error
+	RestorePlatform(ctx context.Context, id uint64) error
+	GetAllPlatforms(ctx context.Context, cursor entity.Cursor, limit int) ([]entity.Platform, error)
+
+	CreateProfile(ctx context.Context, profile entity.Profile) error
+	SaveProfile(ctx context.Context, profile entity.Profile) error
+	GetAllProfiles(ctx context.Context, cursor entity.Cursor, limit int) ([]entity.ProfileInfo, error)
+	GetProfile(ctx context.Context, login entity.ProfileCreds) (*entity.Profile, error)
+	GetProfileInfo(ctx context.Context, nickname string) (*entity.ProfileInfo, error)
+	GetSocialsOfProfiles(ctx context.Context, profileIDs []uint64) (map[uint64][]entity.Social, error)
+	// GetAccount gets the profile with its socials and all refresh tokens including revoked ones
+	GetAccount(ctx context.Context, nickname string) (*entity.Profile, error)
+	// RequestProfileDeletion marks the profile for deletion and revokes its refresh tokens.
+	// It returns the time of the first request if the deletion is already requested.
+	RequestProfileDeletion(ctx context.Context, nickname string, requestedAt time.Time) (time.Time, error)
+	CancelProfileDeletion(ctx context.Context, nickname string) error
+	// PurgeProfiles deletes profiles marked for deletion before the time with all their data
+	PurgeProfiles(ctx context.Context, requestedBefore time.Time) (int64, error)
+
+	SaveRefreshToken(ctx context.Context, nickname string, tokenString string) error
+	FindRefreshToken(ctx context.Context, nickname string, tokenString string) error
+	DeleteRefreshToken(ctx context.Context, tokenString string) error
+	DeleteAllUserRefreshTokens(ctx context.Context, nickname string) error
+
+	SaveSocialType(ctx context.Context, socialType *entity.SocialType) error
+	UpdateSocialType(ctx context.Context, socialType *entity.SocialType) error
+	DeleteSocialType(ctx context.Context, id uint64, reassignTo uint64) error
+	RestoreSocialType(ctx context.Context, id uint64) error
+	GetAllSocialTypes(ctx context.Context, cursor entity.Cursor, limit int) ([]entity.SocialType, error)
+
+	CreateScrapeJob(ctx context.Context, job *entity.ScrapeJob) error
+	SaveScrapeJob(ctx context.Context, job entity.ScrapeJob) error
+	GetScrapeJob(ctx context.Context, id uint64) (*entity.ScrapeJob, error)
+	GetScrapeJobs(ctx context.Context, limit int) ([]entity.ScrapeJob, error)
+	// BeatScrapeJob renews the heartbeat of the running job
+	BeatScrapeJob(ctx context.Context, id uint64) error
+	// AbortStaleScrapeJobs marks running jobs which haven't beaten since staleBefore as failed
+	AbortStaleScrapeJobs(ctx context.Context, staleBefore time.Time, reason string) error
+
+	// WithTx runs fn with a repository whose calls share a single transaction. It's
+	// committed if fn returns nil and rolled back otherwise. fn may run again on
+	// serialization failures and deadlocks, so it mustn't have other side effects.
+	WithTx(ctx context.Context, fn func(repo GamelistRepository) error) error
+
+	// Ping checks the connection to the database
+	Ping(ctx context.Context) error
+	// SchemaVersion is the version of the last migration applied by goose
+	SchemaVersion(ctx context.Context) (int64, error)
+	// Close closes the connection pool of the database
+	Close() error
 }
 
 type gameListRepository struct {
 	db *gorm.DB
+	// timeout limits each call of the repository, 0 for no limit
+	timeout time.Duration
 }
 
 type DBConfig struct {
@@ -59,12 +133,10 @@ type DBConfig struct {
 	TimeZone string
 }
 
-var (
-	GAMES_BATCH_SIZE_LIMIT int = 10
-	ErrDbConnection            = "Failed to connect database."
-)
+// GOOSE_VERSION_TABLE has versions of applied migrations
+const GOOSE_VERSION_TABLE = "goose_db_version"
 
-func NewDBDialector(conf *DBConfig) gorm.Dialector {
+func (conf *DBConfig) DSN() string {
 	var sslString string
 	if conf.SSL {
 		sslString = "require"
@@ -72,7 +144,7 @@ func NewDBDialector(conf *DBConfig) gorm.Dialector {
 		sslString = "disable"
 	}
 
-	dsn := fmt.Sprint("host=", conf.Host,
+	return fmt.Sprint("host=", conf.Host,
 		" user=", conf.User,
 		" password=", conf.Password,
 		" dbname=", conf.DBName,
@@ -80,54 +152,74 @@ func NewDBDialector(conf *DBConfig) gorm.Dialector {
 		" sslmode=", sslString,
 		" TimeZone=", conf.TimeZone,
 	)
-
-	return postgres.Open(dsn)
 }
 
-func NewGamelistRepository(dbName string, dialector gorm.Dialector, loggerConf logger.Config) GamelistRepository {
-	var (
-		db  *gorm.DB
-		err error
-	)
-
-	logger := logger.New(
-		log.New(utilLogger.Logger, "", log.LstdFlags),
-		loggerConf,
-	)
+func NewDBDialector(conf *DBConfig) gorm.Dialector {
+	return postgres.Open(conf.DSN())
+}
 
-	db, err = gorm.Open(dialector, &gorm.Config{
-		Logger: logger,
+// NewGamelistRepository opens the pool of the database. Connections are made on the first
+// query, so the server starts while the database is down and isn't ready until it's up.
+// Queries of a call are canceled with its context or after queryTimeout, 0 for no timeout.
+func NewGamelistRepository(dialector gorm.Dialector, loggerConf logger.Config, queryTimeout time.Duration) (GamelistRepository, error) {
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger:               utilLogger.NewGORMLogger(loggerConf),
+		DisableAutomaticPing: true,
 	})
 	if err != nil {
-		panic(ErrDbConnection)
+		return nil, utilErrs.New(utilErrs.Internal, err, "failed to open database")
+	}
+	// Spans are no-ops until a tracer provider is set up. Values of queries may be
+	// personal data, so they're left out, and pool metrics are collected by Instrument.
+	if err := db.Use(otelgorm.NewPlugin(otelgorm.WithoutQueryVariables(), otelgorm.WithoutMetrics())); err != nil {
+		return nil, utilErrs.New(utilErrs.Internal, err, "failed to instrument database")
 	}
 
 	return &gameListRepository{
-		db: db,
+		db:      db,
+		timeout: queryTimeout,
+	}, nil
+}
+
+// Instrument collects metrics of queries and of the connection pool of the repository
+func Instrument(repo GamelistRepository, dbName string) error {
+	r, ok := repo.(*gameListRepository)
+	if !ok {
+		return nil
 	}
+
+	return metrics.InstrumentGORM(r.db, dbName)
 }
 
-func (r *gameListRepository) SaveGame(game entity.GameProperties) error {
-	for i := range game.Platforms {
-		res := r.db.First(&game.Platforms[i], game.Platforms[i])
-		if res.Error != nil {
-			return utilErrs.FromGORM(res,
-				fmt.Sprintf("couldn't find platform with id %d and name %s",
-					game.Platforms[i].ID, game.Platforms[i].Name,
-				))
-		}
+// withContext is the connection for a call of the repository. Its queries are canceled
+// with ctx or after the timeout of the repository, cancel releases the timer.
+func (r *gameListRepository) withContext(ctx context.Context) (db *gorm.DB, cancel context.CancelFunc) {
+	if r.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
 	}
-	for i := range game.Genres {
-		res := r.db.First(&game.Genres[i], game.Genres[i])
-		if res.Error != nil {
-			return utilErrs.FromGORM(res,
-				fmt.Sprintf("couldn't find genre with id %d and name %s",
-					game.Genres[i].ID, game.Genres[i].Name,
-				))
-		}
+
+	return r.db.WithContext(ctx), cancel
+}
+
+// WithTx doesn't limit the transaction as a whole, each call of the repository in it
+// has its own timeout
+func (r *gameListRepository) WithTx(ctx context.Context, fn func(repo GamelistRepository) error) error {
+	return transaction(r.db.WithContext(ctx), func(tx *gorm.DB) error {
+		return fn(&gameListRepository{db: tx, timeout: r.timeout})
+	})
+}
+
+func (r *gameListRepository) SaveGame(ctx context.Context, game *entity.GameProperties) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	if err := findGameCatalog(db, game); err != nil {
+		return err
 	}
 
-	res := r.db.Save(&game)
+	res := db.Save(game)
 	if res.Error != nil {
 		return utilErrs.FromGORM(res, "failed to save game")
 	}
@@ -135,9 +227,88 @@ func (r *gameListRepository) SaveGame(game entity.GameProperties) error {
 	return nil
 }
 
-func (r *gameListRepository) GetAllGames() ([]entity.GameProperties, error) {
+func (r *gameListRepository) UpdateGame(ctx context.Context, game *entity.GameProperties) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	return transaction(db, func(tx *gorm.DB) error {
+		if err := findGameCatalog(tx, game); err != nil {
+			return err
+		}
+
+		res := tx.Model(game).Select("name", "image_url", "year_released").Updates(game)
+		if res.Error != nil || res.RowsAffected == 0 {
+			return utilErrs.FromGORM(res, fmt.Sprint("failed to update game with id: ", game.ID))
+		}
+
+		err := tx.Model(game).Association("Platforms").Replace(game.Platforms)
+		if err != nil {
+			return utilErrs.FromDB(err, "failed to update platforms of game")
+		}
+		err = tx.Model(game).Association("Genres").Replace(game.Genres)
+		if err != nil {
+			return utilErrs.FromDB(err, "failed to update genres of game")
+		}
+
+		return nil
+	})
+}
+
+// DeleteGame soft-deletes the game. If reassignTo is set, the game is merged into it,
+// otherwise the game can't be deleted while users have it in their lists.
+func (r *gameListRepository) DeleteGame(ctx context.Context, id uint64, reassignTo uint64) error {
+	if reassignTo != 0 {
+		return r.MergeGames(ctx, reassignTo, id)
+	}
+
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	return transaction(db, func(tx *gorm.DB) error {
+		if err := checkUnreferenced(tx, gameReferences, id, "game"); err != nil {
+			return err
+		}
+		return deleteByID(tx, &entity.GameProperties{}, id, "game")
+	})
+}
+
+// RestoreGame brings back a deleted game unless it was merged into another one
+func (r *gameListRepository) RestoreGame(ctx context.Context, id uint64) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	var redirect entity.GameRedirect
+	res := db.Limit(1).Find(&redirect, id)
+	if res.Error != nil {
+		return utilErrs.FromGORM(res, "failed to find game redirect")
+	}
+	if redirect.NewID != 0 {
+		return utilErrs.Newf(utilErrs.BadInput, nil, "game with id %d was merged into game with id %d", id, redirect.NewID).
+			WithReason(utilErrs.ReasonGameMerged)
+	}
+
+	return restoreByID(db, &entity.GameProperties{}, id, "game")
+}
+
+func (r *gameListRepository) GetGame(ctx context.Context, id uint64) (*entity.GameProperties, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	var game entity.GameProperties
+	res := db.Preload(clause.Associations).First(&game, id)
+	if res.Error != nil {
+		return nil, utilErrs.FromGORM(res, fmt.Sprint("couldn't find game with id: ", id)).NotFoundAs(utilErrs.ReasonGameNotFound)
+	}
+
+	return &game, nil
+}
+
+func (r *gameListRepository) GetGames(ctx context.Context, cursor entity.Cursor, limit int) ([]entity.GameProperties, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
 	var games []entity.GameProperties
-	res := r.db.Preload(clause.Associations).Find(&games)
+	res := paginate(db.Preload(clause.Associations), "", "id", cursor, limit).Find(&games)
 	if res.Error != nil {
 		return nil, utilErrs.FromGORM(res, "failed to get games")
 	}
@@ -145,22 +316,119 @@ func (r *gameListRepository) GetAllGames() ([]entity.GameProperties, error) {
 	return games, nil
 }
 
-func (r *gameListRepository) GetAllGamesTyped(nickname string, last uint64, batchSize int) ([]entity.TypedGameListProperties, error) {
-	if batchSize > GAMES_BATCH_SIZE_LIMIT {
-		batchSize = GAMES_BATCH_SIZE_LIMIT
+func (r *gameListRepository) UpsertGame(ctx context.Context, sourced entity.SourcedGame, priority entity.SourcePriority) (entity.IngestResult, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	var result entity.IngestResult
+
+	err := transaction(db, func(tx *gorm.DB) error {
+		// A retried transaction starts over from the sourced game
+		game := sourced.Game
+
+		var err error
+		game.Platforms, err = firstOrCreatePlatforms(tx, game.Platforms)
+		if err != nil {
+			return err
+		}
+		game.Genres, err = firstOrCreateGenres(tx, game.Genres)
+		if err != nil {
+			return err
+		}
+
+		var link entity.GameExternalID
+		res := tx.Where(&entity.GameExternalID{Source: sourced.Source, ExternalID: sourced.ExternalID}).Limit(1).Find(&link)
+		if res.Error != nil {
+			return utilErrs.FromGORM(res, "failed to find game's external id")
+		}
+
+		// Games saved before they were keyed by external id are adopted by name. Names of
+		// deleted games may be taken by live ones, which are adopted first.
+		var existing entity.GameProperties
+		if link.GameID != 0 {
+			res = tx.Unscoped().Preload(clause.Associations).Limit(1).Find(&existing, link.GameID)
+		} else {
+			res = tx.Unscoped().Preload(clause.Associations).Where("name = ?", game.Name).
+				Order("deleted_at IS NOT NULL").Order("id desc").Limit(1).Find(&existing)
+		}
+		if res.Error != nil {
+			return utilErrs.FromGORM(res, "failed to find game")
+		}
+
+		// Deleted games aren't brought back by ingestion
+		if existing.DeletedAt.Valid {
+			result = entity.IngestSkipped
+			return nil
+		}
+
+		var owners map[string]string
+		if existing.ID == 0 {
+			res = tx.Create(&game)
+			if res.Error != nil {
+				return utilErrs.FromGORM(res, "failed to create game")
+			}
+			result = entity.IngestInserted
+		} else {
+			game.ID = existing.ID
+			owners, err = gameFieldSources(tx, existing.ID)
+			if err != nil {
+				return err
+			}
+
+			changed, err := mergeGame(tx, &existing, &game, sourced.Source, owners, priority)
+			if err != nil {
+				return err
+			}
+			if changed {
+				result = entity.IngestUpdated
+			} else {
+				result = entity.IngestSkipped
+			}
+		}
+
+		if err := saveGameFieldSources(tx, &game, sourced.Source, owners, priority); err != nil {
+			return err
+		}
+
+		if link.GameID != game.ID {
+			link = entity.GameExternalID{
+				Source:     sourced.Source,
+				ExternalID: sourced.ExternalID,
+				GameID:     game.ID,
+			}
+			res = tx.Save(&link)
+			if res.Error != nil {
+				return utilErrs.FromGORM(res, "failed to save game's external id")
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
 	}
 
-	userId, err := r.findUserIDByNickname(nickname)
+	return result, nil
+}
+
+// GetAllGamesTyped lists games which names start with name, or all of them if it's empty
+func (r *gameListRepository) GetAllGamesTyped(ctx context.Context, nickname string, name string, cursor entity.Cursor, limit int) ([]entity.TypedGameListProperties, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	userId, err := findUserIDByNickname(db, nickname)
 	if err != nil {
 		return nil, err
 	}
 
 	var games []entity.TypedGameListProperties
-	res := r.db.Table("game_properties").
+	query := db.Table("game_properties").
 		Joins("left join profile_game on game_properties.id = profile_game.game_id and profile_game.profile_id = ?", userId).
-		Where("game_properties.id > ?", last).
-		Limit(batchSize).
-		Scan(&games)
+		Where("game_properties.deleted_at IS NULL")
+	if name != "" {
+		query = query.Where("game_properties.name LIKE ?", name+"%")
+	}
+	res := paginate(query, "", "game_properties.id", cursor, limit).Scan(&games)
 
 	if res.Error != nil {
 		return nil, utilErrs.FromGORM(res, "failed to get games")
@@ -169,16 +437,20 @@ func (r *gameListRepository) GetAllGamesTyped(nickname string, last uint64, batc
 	return games, nil
 }
 
-func (r *gameListRepository) GetUserGameList(nickname string) ([]entity.TypedGameListProperties, error) {
+func (r *gameListRepository) GetUserGameList(ctx context.Context, nickname string, cursor entity.Cursor, limit int) ([]entity.TypedGameListProperties, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
 	var games []entity.TypedGameListProperties
-	res := r.db.Table("game_properties").Select(
+	query := db.Table("game_properties").Select(
 		"game_properties.id, game_properties.name, game_properties.image_url, game_properties.year_released, profile_game.list_type_id",
 	).Joins(
 		"join profile_game on game_properties.id = profile_game.game_id and profile_game.list_type_id != 0",
 	).Joins(
 		"join profile on profile_game.profile_id = profile.id and profile.nickname = ?",
 		nickname,
-	).Scan(&games)
+	).Where("game_properties.deleted_at IS NULL")
+	res := paginate(query, "game_properties.name", "game_properties.id", cursor, limit).Scan(&games)
 
 	if res.Error != nil {
 		return nil, utilErrs.FromGORM(res, "failed to get user game list")
@@ -187,11 +459,60 @@ func (r *gameListRepository) GetUserGameList(nickname string) ([]entity.TypedGam
 	return games, nil
 }
 
-func (r *gameListRepository) SearchGames(name string) ([]entity.GameSearchResult, error) {
+func (r *gameListRepository) GetGameListsOfProfiles(ctx context.Context, profileIDs []uint64, cursor entity.Cursor, limit int) (map[uint64][]entity.TypedGameListProperties, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	// Rows are numbered per profile, so a single query pages every list
+	entries := db.Table("game_properties").Select(
+		"profile_game.profile_id, game_properties.id, game_properties.name, game_properties.image_url, game_properties.year_released, profile_game.list_type_id, "+
+			"row_number() over (partition by profile_game.profile_id order by game_properties.name, game_properties.id) as row_num",
+	).Joins(
+		"join profile_game on game_properties.id = profile_game.game_id",
+	).Where("profile_game.profile_id IN ? AND game_properties.deleted_at IS NULL", profileIDs)
+	if cursor != (entity.Cursor{}) {
+		entries = entries.Where("(game_properties.name, game_properties.id) > (?, ?)", cursor.Key, cursor.ID)
+	}
+
+	query := db.Table("(?) as entries", entries)
+	if limit > 0 {
+		query = query.Where("row_num <= ?", limit+1)
+	}
+
+	var rows []struct {
+		ProfileID    uint64
+		ID           uint64
+		Name         string
+		ImageURL     string
+		YearReleased uint16
+		ListTypeID   uint64
+	}
+	res := query.Order("profile_id").Order("row_num").Scan(&rows)
+	if res.Error != nil {
+		return nil, utilErrs.FromGORM(res, "failed to get game lists of profiles")
+	}
+
+	result := make(map[uint64][]entity.TypedGameListProperties)
+	for _, row := range rows {
+		game := entity.TypedGameListProperties{ListTypeID: row.ListTypeID}
+		game.ID = row.ID
+		game.Name = row.Name
+		game.ImageURL = row.ImageURL
+		game.YearReleased = row.YearReleased
+		result[row.ProfileID] = append(result[row.ProfileID], game)
+	}
+
+	return result, nil
+}
+
+func (r *gameListRepository) SearchGames(ctx context.Context, name string) ([]entity.GameSearchResult, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
 	var games []entity.GameSearchResult
 	if len(name) > 1 {
-		res := r.db.Table("game_properties").
-			Where("name LIKE ?", name+"%").
+		res := db.Table("game_properties").
+			Where("name LIKE ? AND deleted_at IS NULL", name+"%").
 			Limit(10).
 			Find(&games)
 
@@ -202,24 +523,48 @@ func (r *gameListRepository) SearchGames(name string) ([]entity.GameSearchResult
 	return games, nil
 }
 
-func (r *gameListRepository) GetGameDetails(nickname string, gameId uint64) (*entity.GameDetailsResponse, error) {
-	userId, err := r.findUserIDByNickname(nickname)
+func (r *gameListRepository) GetGameTitles(ctx context.Context) ([]entity.GameTitle, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	var titles []entity.GameTitle
+	res := db.Table("game_properties").
+		Select("id, name, year_released").
+		Where("deleted_at IS NULL").
+		Scan(&titles)
+	if res.Error != nil {
+		return nil, utilErrs.FromGORM(res, "failed to get game titles")
+	}
+
+	return titles, nil
+}
+
+func (r *gameListRepository) GetGameDetails(ctx context.Context, nickname string, gameId uint64) (*entity.GameDetailsResponse, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	userId, err := findUserIDByNickname(db, nickname)
+	if err != nil {
+		return nil, err
+	}
+
+	gameId, err = resolveGameID(db, gameId)
 	if err != nil {
 		return nil, err
 	}
 
 	var gameDetails entity.GameDetailsResponse
-	res := r.db.Table("game_properties").
+	res := db.Table("game_properties").
 		Joins("left join profile_game on game_properties.id = profile_game.game_id and profile_game.profile_id = ?", userId).
-		Where("game_properties.id = ?", gameId).
+		Where("game_properties.id = ? AND game_properties.deleted_at IS NULL", gameId).
 		Limit(1).
 		Scan(&(gameDetails.Game))
 
 	if res.Error != nil || res.RowsAffected == 0 {
-		return nil, utilErrs.FromGORM(res, "failed to get game")
+		return nil, utilErrs.FromGORM(res, "failed to get game").NotFoundAs(utilErrs.ReasonGameNotFound)
 	}
 
-	res = r.db.Table("platform").Select("platform.name").
+	res = db.Table("platform").Select("platform.name").
 		Joins("inner join game_platforms on game_platforms.game_properties_id = ? and game_platforms.platform_id = platform.id", gameId).
 		Scan(&(gameDetails.Platforms))
 
@@ -227,7 +572,7 @@ func (r *gameListRepository) GetGameDetails(nickname string, gameId uint64) (*en
 		return nil, utilErrs.FromGORM(res, "failed to get game's platforms")
 	}
 
-	res = r.db.Table("genre").Select("genre.name").
+	res = db.Table("genre").Select("genre.name").
 		Joins("inner join game_genres on game_genres.game_properties_id = ? and game_genres.genre_id = genre.id", gameId).
 		Scan(&(gameDetails.Genres))
 
@@ -238,59 +583,366 @@ func (r *gameListRepository) GetGameDetails(nickname string, gameId uint64) (*en
 	return &gameDetails, nil
 }
 
-func (r *gameListRepository) CreateListType(listType entity.ListType) error {
-	res := r.db.Create(&listType)
+func (r *gameListRepository) GetGamesByIDs(ctx context.Context, ids []uint64) (map[uint64]entity.GameProperties, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	var redirects []entity.GameRedirect
+	res := db.Where("old_id IN ?", ids).Find(&redirects)
 	if res.Error != nil {
-		return utilErrs.FromGORM(res, "failed to create list type")
+		return nil, utilErrs.FromGORM(res, "failed to resolve game ids")
 	}
 
-	return nil
+	requested := make(map[uint64][]uint64, len(ids))
+	for _, id := range ids {
+		requested[id] = append(requested[id], id)
+	}
+	for _, redirect := range redirects {
+		delete(requested, redirect.OldID)
+		requested[redirect.NewID] = append(requested[redirect.NewID], redirect.OldID)
+	}
+
+	resolved := make([]uint64, 0, len(requested))
+	for id := range requested {
+		resolved = append(resolved, id)
+	}
+
+	var games []entity.GameProperties
+	res = db.Where("id IN ?", resolved).Find(&games)
+	if res.Error != nil {
+		return nil, utilErrs.FromGORM(res, "failed to get games")
+	}
+
+	result := make(map[uint64]entity.GameProperties, len(ids))
+	for _, game := range games {
+		for _, id := range requested[game.ID] {
+			result[id] = game
+		}
+	}
+
+	return result, nil
 }
 
-func (r *gameListRepository) GetAllListTypes() ([]entity.ListType, error) {
-	var types []entity.ListType
-	res := r.db.Find(&types)
+// gameItem is an item related to a game, e.g. a genre of it
+type gameItem struct {
+	GameID uint64
+	ID     uint64
+	Name   string
+}
+
+func getItemsOfGames(db *gorm.DB, table string, gameIDs []uint64) ([]gameItem, error) {
+	var items []gameItem
+	res := db.Table(table).
+		Select(fmt.Sprintf("game_%[1]ss.game_properties_id AS game_id, %[1]s.id, %[1]s.name", table)).
+		Joins(fmt.Sprintf("inner join game_%[1]ss on game_%[1]ss.%[1]s_id = %[1]s.id", table)).
+		Where(fmt.Sprintf("game_%ss.game_properties_id IN ? AND %s.deleted_at IS NULL", table, table), gameIDs).
+		Order(table + ".name").
+		Scan(&items)
 	if res.Error != nil {
-		return nil, utilErrs.FromGORM(res, "failed to get list types")
+		return nil, utilErrs.FromGORM(res, fmt.Sprintf("failed to get %ss of games", table))
 	}
 
-	return types, nil
+	return items, nil
 }
 
-func (r *gameListRepository) ListGame(nickname string, gameId uint64, listType uint64) error {
-	userId, err := r.findUserIDByNickname(nickname)
+func (r *gameListRepository) GetPlatformsOfGames(ctx context.Context, gameIDs []uint64) (map[uint64][]entity.Platform, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	items, err := getItemsOfGames(db, "platform", gameIDs)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	platforms := make(map[uint64][]entity.Platform)
+	for _, item := range items {
+		platforms[item.GameID] = append(platforms[item.GameID], entity.Platform{ID: item.ID, Name: item.Name})
 	}
 
-	res := r.db.First(&entity.GameProperties{}, gameId)
+	return platforms, nil
+}
+
+func (r *gameListRepository) GetGenresOfGames(ctx context.Context, gameIDs []uint64) (map[uint64][]entity.Genre, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	items, err := getItemsOfGames(db, "genre", gameIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	genres := make(map[uint64][]entity.Genre)
+	for _, item := range items {
+		genres[item.GameID] = append(genres[item.GameID], entity.Genre{ID: item.ID, Name: item.Name})
+	}
+
+	return genres, nil
+}
+
+func (r *gameListRepository) GetListTypesOfGames(ctx context.Context, nickname string, gameIDs []uint64) (map[uint64]uint64, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	userId, err := findUserIDByNickname(db, nickname)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []entity.ProfileGame
+	res := db.Select("game_id", "list_type_id").
+		Where("profile_id = ? AND game_id IN ? AND list_type_id != 0", userId, gameIDs).
+		Find(&entries)
+	if res.Error != nil {
+		return nil, utilErrs.FromGORM(res, "failed to get list types of games")
+	}
+
+	listTypes := make(map[uint64]uint64, len(entries))
+	for _, entry := range entries {
+		listTypes[entry.GameID] = entry.ListTypeID
+	}
+
+	return listTypes, nil
+}
+
+// SaveGameDuplicates adds new pairs and updates scores of known ones keeping their status
+func (r *gameListRepository) SaveGameDuplicates(ctx context.Context, duplicates []entity.GameDuplicate) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	if len(duplicates) == 0 {
+		return nil
+	}
+
+	res := db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "game_id"}, {Name: "duplicate_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"score", "updated_at"}),
+	}).Omit(clause.Associations).Create(&duplicates)
 	if res.Error != nil {
-		return utilErrs.FromGORM(res, fmt.Sprint("couldn't find game with id: ", gameId))
+		return utilErrs.FromGORM(res, "failed to save game duplicates")
 	}
 
-	if listType != 0 {
-		res = r.db.First(&entity.ListType{}, listType)
+	return nil
+}
+
+func (r *gameListRepository) GetGameDuplicates(ctx context.Context, status string, limit int) ([]entity.GameDuplicate, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	var duplicates []entity.GameDuplicate
+	res := db.Preload("Game").Preload("Duplicate").
+		Where("status = ?", status).
+		Order("score desc").
+		Limit(limit).
+		Find(&duplicates)
+	if res.Error != nil {
+		return nil, utilErrs.FromGORM(res, "failed to get game duplicates")
+	}
+
+	return duplicates, nil
+}
+
+func (r *gameListRepository) DismissGameDuplicate(ctx context.Context, gameID uint64, duplicateID uint64) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	if gameID > duplicateID {
+		gameID, duplicateID = duplicateID, gameID
+	}
+
+	res := db.Model(&entity.GameDuplicate{}).
+		Where("game_id = ? and duplicate_id = ?", gameID, duplicateID).
+		Update("status", entity.DuplicateDismissed)
+	if res.Error != nil || res.RowsAffected == 0 {
+		return utilErrs.FromGORM(res, fmt.Sprintf("couldn't find duplicate pair of games %d and %d", gameID, duplicateID))
+	}
+
+	return nil
+}
+
+// MergeGames moves everything of the duplicate game to the survivor and deletes the duplicate.
+// The duplicate's id keeps resolving to the survivor, its name is free to take.
+func (r *gameListRepository) MergeGames(ctx context.Context, survivorID uint64, duplicateID uint64) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	if survivorID == duplicateID {
+		return utilErrs.New(utilErrs.BadInput, nil, "can't merge a game into itself")
+	}
+
+	return transaction(db, func(tx *gorm.DB) error {
+		for _, id := range []uint64{survivorID, duplicateID} {
+			res := tx.First(&entity.GameProperties{}, id)
+			if res.Error != nil {
+				return utilErrs.FromGORM(res, fmt.Sprint("couldn't find game with id: ", id)).NotFoundAs(utilErrs.ReasonGameNotFound)
+			}
+		}
+
+		// Users who listed both games keep the survivor's entry
+		statements := []struct {
+			sql string
+			msg string
+		}{
+			{`delete from profile_game where game_id = @duplicate
+				and profile_id in (select profile_id from profile_game where game_id = @survivor)`,
+				"failed to delete conflicting list entries"},
+			{`update profile_game set game_id = @survivor where game_id = @duplicate`,
+				"failed to move list entries"},
+			{`insert into game_platforms (game_properties_id, platform_id)
+				select @survivor, platform_id from game_platforms where game_properties_id = @duplicate
+				on conflict do nothing`,
+				"failed to merge platforms"},
+			{`delete from game_platforms where game_properties_id = @duplicate`,
+				"failed to delete duplicate's platforms"},
+			{`insert into game_genres (game_properties_id, genre_id)
+				select @survivor, genre_id from game_genres where game_properties_id = @duplicate
+				on conflict do nothing`,
+				"failed to merge genres"},
+			{`delete from game_genres where game_properties_id = @duplicate`,
+				"failed to delete duplicate's genres"},
+			{`update game_external_id set game_id = @survivor where game_id = @duplicate`,
+				"failed to move external ids"},
+			{`delete from game_field_source where game_id = @duplicate`,
+				"failed to delete duplicate's field sources"},
+			{`update game_duplicate set status = 'merged', updated_at = now()
+				where (game_id = @survivor and duplicate_id = @duplicate)
+					or (game_id = @duplicate and duplicate_id = @survivor)`,
+				"failed to mark games as merged"},
+			{`delete from game_duplicate where status != 'merged'
+				and (game_id = @duplicate or duplicate_id = @duplicate)`,
+				"failed to delete duplicate's pairs"},
+			{`update game_redirect set new_id = @survivor where new_id = @duplicate`,
+				"failed to update redirects"},
+			{`insert into game_redirect (old_id, new_id) values (@duplicate, @survivor)`,
+				"failed to save redirect"},
+		}
+
+		args := map[string]interface{}{
+			"survivor":  survivorID,
+			"duplicate": duplicateID,
+		}
+		for _, statement := range statements {
+			res := tx.Exec(statement.sql, args)
+			if res.Error != nil {
+				return utilErrs.FromGORM(res, statement.msg)
+			}
+		}
+
+		res := tx.Delete(&entity.GameProperties{}, duplicateID)
 		if res.Error != nil {
-			return utilErrs.FromGORM(res, fmt.Sprint("couldn't find list type with id: ", listType))
+			return utilErrs.FromGORM(res, "failed to delete duplicate game")
 		}
+
+		return nil
+	})
+}
+
+func (r *gameListRepository) CreateListType(ctx context.Context, listType *entity.ListType) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	res := db.Create(listType)
+	if res.Error != nil {
+		return utilErrs.FromGORM(res, "failed to create list type")
 	}
 
-	listGame := entity.ProfileGame{
-		ProfileID:  userId,
-		GameID:     gameId,
-		ListTypeID: listType,
+	return nil
+}
+
+func (r *gameListRepository) UpdateListType(ctx context.Context, listType *entity.ListType) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	return updateName(db, listType, listType.ID, listType.Name, "list type")
+}
+
+func (r *gameListRepository) DeleteListType(ctx context.Context, id uint64, reassignTo uint64) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	return transaction(db, func(tx *gorm.DB) error {
+		if err := reassignReferences(tx, &entity.ListType{}, listTypeReferences, id, reassignTo, "list type"); err != nil {
+			return err
+		}
+		return deleteByID(tx, &entity.ListType{}, id, "list type")
+	})
+}
+
+func (r *gameListRepository) RestoreListType(ctx context.Context, id uint64) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	return restoreByID(db, &entity.ListType{}, id, "list type")
+}
+
+func (r *gameListRepository) GetAllListTypes(ctx context.Context, cursor entity.Cursor, limit int) ([]entity.ListType, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	var types []entity.ListType
+	res := paginate(db, "", "id", cursor, limit).Find(&types)
+	if res.Error != nil {
+		return nil, utilErrs.FromGORM(res, "failed to get list types")
 	}
 
-	res = r.db.Save(&listGame)
+	return types, nil
+}
+
+func (r *gameListRepository) GetListTypesByIDs(ctx context.Context, ids []uint64) (map[uint64]entity.ListType, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	var types []entity.ListType
+	res := db.Where("id IN ?", ids).Find(&types)
 	if res.Error != nil {
-		return utilErrs.FromGORM(res, "failed to save changes")
+		return nil, utilErrs.FromGORM(res, "failed to get list types")
 	}
 
-	return nil
+	result := make(map[uint64]entity.ListType, len(types))
+	for _, listType := range types {
+		result[listType.ID] = listType
+	}
+
+	return result, nil
 }
 
-func (r *gameListRepository) SaveGenre(genre entity.Genre) error {
-	res := r.db.Save(&genre)
+func (r *gameListRepository) ListGame(ctx context.Context, nickname string, gameId uint64, listType uint64) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	userId, err := findUserIDByNickname(db, nickname)
+	if err != nil {
+		return err
+	}
+
+	return listGame(db, userId, gameId, listType)
+}
+
+// ListGames applies all entries or none of them
+func (r *gameListRepository) ListGames(ctx context.Context, nickname string, entries []entity.GameListRequest) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	userId, err := findUserIDByNickname(db, nickname)
+	if err != nil {
+		return err
+	}
+
+	return transaction(db, func(tx *gorm.DB) error {
+		for _, entry := range entries {
+			if err := listGame(tx, userId, entry.GameId, entry.ListType); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *gameListRepository) SaveGenre(ctx context.Context, genre *entity.Genre) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	res := db.Save(genre)
 	if res.Error != nil {
 		return utilErrs.FromGORM(res, "failed to save genre")
 	}
@@ -298,9 +950,38 @@ func (r *gameListRepository) SaveGenre(genre entity.Genre) error {
 	return nil
 }
 
-func (r *gameListRepository) GetAllGenres() ([]entity.Genre, error) {
+func (r *gameListRepository) UpdateGenre(ctx context.Context, genre *entity.Genre) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	return updateName(db, genre, genre.ID, genre.Name, "genre")
+}
+
+func (r *gameListRepository) DeleteGenre(ctx context.Context, id uint64, reassignTo uint64) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	return transaction(db, func(tx *gorm.DB) error {
+		if err := reassignReferences(tx, &entity.Genre{}, genreReferences, id, reassignTo, "genre"); err != nil {
+			return err
+		}
+		return deleteByID(tx, &entity.Genre{}, id, "genre")
+	})
+}
+
+func (r *gameListRepository) RestoreGenre(ctx context.Context, id uint64) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	return restoreByID(db, &entity.Genre{}, id, "genre")
+}
+
+func (r *gameListRepository) GetAllGenres(ctx context.Context, cursor entity.Cursor, limit int) ([]entity.Genre, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
 	var genres []entity.Genre
-	res := r.db.Find(&genres)
+	res := paginate(db, "", "id", cursor, limit).Find(&genres)
 	if res.Error != nil {
 		return nil, utilErrs.FromGORM(res, "failed to get genres")
 	}
@@ -308,8 +989,11 @@ func (r *gameListRepository) GetAllGenres() ([]entity.Genre, error) {
 	return genres, nil
 }
 
-func (r *gameListRepository) SavePlatform(platform entity.Platform) error {
-	res := r.db.Save(&platform)
+func (r *gameListRepository) SavePlatform(ctx context.Context, platform *entity.Platform) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	res := db.Save(platform)
 	if res.Error != nil {
 		return utilErrs.FromGORM(res, "failed to save platform")
 	}
@@ -317,9 +1001,38 @@ func (r *gameListRepository) SavePlatform(platform entity.Platform) error {
 	return nil
 }
 
-func (r *gameListRepository) GetAllPlatforms() ([]entity.Platform, error) {
+func (r *gameListRepository) UpdatePlatform(ctx context.Context, platform *entity.Platform) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	return updateName(db, platform, platform.ID, platform.Name, "platform")
+}
+
+func (r *gameListRepository) DeletePlatform(ctx context.Context, id uint64, reassignTo uint64) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	return transaction(db, func(tx *gorm.DB) error {
+		if err := reassignReferences(tx, &entity.Platform{}, platformReferences, id, reassignTo, "platform"); err != nil {
+			return err
+		}
+		return deleteByID(tx, &entity.Platform{}, id, "platform")
+	})
+}
+
+func (r *gameListRepository) RestorePlatform(ctx context.Context, id uint64) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	return restoreByID(db, &entity.Platform{}, id, "platform")
+}
+
+func (r *gameListRepository) GetAllPlatforms(ctx context.Context, cursor entity.Cursor, limit int) ([]entity.Platform, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
 	var platforms []entity.Platform
-	res := r.db.Find(&platforms)
+	res := paginate(db, "", "id", cursor, limit).Find(&platforms)
 	if res.Error != nil {
 		return nil, utilErrs.FromGORM(res, "failed to get platforms")
 	}
@@ -327,37 +1040,47 @@ func (r *gameListRepository) GetAllPlatforms() ([]entity.Platform, error) {
 	return platforms, nil
 }
 
-func (r *gameListRepository) CreateProfile(profile entity.Profile) error {
-	if err := CheckSocialTypes(r.db, &profile); err != nil {
+func (r *gameListRepository) CreateProfile(ctx context.Context, profile entity.Profile) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	if err := CheckSocialTypes(db, &profile); err != nil {
 		return err
 	}
 
 	profile.GamesListed = 0
 
-	res := r.db.Create(&profile)
+	res := db.Create(&profile)
 	if res.Error != nil {
-		return utilErrs.FromGORM(res, "failed to create profile")
+		return profileConflict(res, "failed to create profile")
 	}
 
 	return nil
 }
 
-func (r *gameListRepository) SaveProfile(profile entity.Profile) error {
-	if err := CheckSocialTypes(r.db, &profile); err != nil {
+func (r *gameListRepository) SaveProfile(ctx context.Context, profile entity.Profile) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	if err := CheckSocialTypes(db, &profile); err != nil {
 		return err
 	}
 
-	res := r.db.Save(&profile)
+	res := db.Save(&profile)
 	if res.Error != nil {
-		return utilErrs.FromGORM(res, "failed to save profile")
+		return profileConflict(res, "failed to save profile")
 	}
 
 	return nil
 }
 
-func (r *gameListRepository) GetAllProfiles() ([]entity.ProfileInfo, error) {
+func (r *gameListRepository) GetAllProfiles(ctx context.Context, cursor entity.Cursor, limit int) ([]entity.ProfileInfo, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
 	var profiles []entity.ProfileInfo
-	res := r.db.Model(&entity.Profile{}).Find(&profiles)
+	query := db.Model(&entity.Profile{}).Where("deletion_requested_at IS NULL")
+	res := paginate(query, "", "id", cursor, limit).Find(&profiles)
 	if res.Error != nil {
 		return nil, utilErrs.FromGORM(res, "failed to get profiles")
 	}
@@ -365,18 +1088,142 @@ func (r *gameListRepository) GetAllProfiles() ([]entity.ProfileInfo, error) {
 	return profiles, nil
 }
 
-func (r *gameListRepository) GetProfile(login entity.ProfileCreds) (*entity.Profile, error) {
+func (r *gameListRepository) GetProfile(ctx context.Context, login entity.ProfileCreds) (*entity.Profile, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
 	var profile entity.Profile
-	res := r.db.First(&profile, login)
+	res := db.First(&profile, login)
 	if res.Error != nil {
-		return nil, utilErrs.FromGORM(res, "failed to get profile")
+		return nil, utilErrs.FromGORM(res, "failed to get profile").NotFoundAs(utilErrs.ReasonProfileNotFound)
 	}
 
 	return &profile, nil
 }
 
-func (r *gameListRepository) SaveRefreshToken(nickname string, tokenString string) error {
-	userID, err := r.findUserIDByNickname(nickname)
+func (r *gameListRepository) GetProfileInfo(ctx context.Context, nickname string) (*entity.ProfileInfo, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	var profile entity.ProfileInfo
+	res := db.Model(&entity.Profile{}).
+		Where("nickname = ? AND deletion_requested_at IS NULL", nickname).
+		Take(&profile)
+	if res.Error != nil {
+		return nil, utilErrs.FromGORM(res, fmt.Sprintf("failed to get profile \"%s\"", nickname)).NotFoundAs(utilErrs.ReasonProfileNotFound)
+	}
+
+	return &profile, nil
+}
+
+func (r *gameListRepository) GetSocialsOfProfiles(ctx context.Context, profileIDs []uint64) (map[uint64][]entity.Social, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	var socials []entity.Social
+	res := db.Preload("Type").Where("profile_id IN ?", profileIDs).Find(&socials)
+	if res.Error != nil {
+		return nil, utilErrs.FromGORM(res, "failed to get socials of profiles")
+	}
+
+	result := make(map[uint64][]entity.Social)
+	for _, social := range socials {
+		result[social.ProfileID] = append(result[social.ProfileID], social)
+	}
+
+	return result, nil
+}
+
+func (r *gameListRepository) GetAccount(ctx context.Context, nickname string) (*entity.Profile, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	var profile entity.Profile
+	res := db.
+		Preload("Socials.Type", func(db *gorm.DB) *gorm.DB {
+			return db.Unscoped()
+		}).
+		Preload("RefreshTokens", func(db *gorm.DB) *gorm.DB {
+			return db.Unscoped().Order("id")
+		}).
+		Where("nickname = ?", nickname).
+		Take(&profile)
+	if res.Error != nil {
+		return nil, utilErrs.FromGORM(res, fmt.Sprintf("failed to get account of \"%s\"", nickname)).NotFoundAs(utilErrs.ReasonProfileNotFound)
+	}
+
+	return &profile, nil
+}
+
+func (r *gameListRepository) RequestProfileDeletion(ctx context.Context, nickname string, requestedAt time.Time) (time.Time, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	err := transaction(db, func(tx *gorm.DB) error {
+		var profile entity.Profile
+		res := tx.Select("id", "deletion_requested_at").Where("nickname = ?", nickname).Take(&profile)
+		if res.Error != nil {
+			return utilErrs.FromGORM(res, fmt.Sprintf("failed to find user with nickname \"%s\"", nickname)).NotFoundAs(utilErrs.ReasonProfileNotFound)
+		}
+
+		if profile.DeletionRequestedAt != nil {
+			requestedAt = *profile.DeletionRequestedAt
+		} else {
+			res = tx.Model(&profile).Update("deletion_requested_at", requestedAt)
+			if res.Error != nil {
+				return utilErrs.FromGORM(res, "failed to request profile deletion")
+			}
+		}
+
+		res = tx.Where("profile_id = ?", profile.ID).Delete(&entity.RefreshToken{})
+		if res.Error != nil {
+			return utilErrs.FromGORM(res, "failed to delete refresh tokens")
+		}
+
+		return nil
+	})
+
+	return requestedAt, err
+}
+
+func (r *gameListRepository) CancelProfileDeletion(ctx context.Context, nickname string) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	res := db.Model(&entity.Profile{}).
+		Where("nickname = ? AND deletion_requested_at IS NOT NULL", nickname).
+		Update("deletion_requested_at", nil)
+	if res.Error != nil {
+		return utilErrs.FromGORM(res, "failed to cancel profile deletion")
+	}
+	if res.RowsAffected == 0 {
+		return utilErrs.New(utilErrs.NotFound, nil, "deletion of the profile isn't requested").WithReason(utilErrs.ReasonDeletionNotRequested)
+	}
+
+	return nil
+}
+
+// PurgeProfiles relies on the cascade of profile foreign keys to delete
+// socials, listed games and refresh tokens of the profiles
+func (r *gameListRepository) PurgeProfiles(ctx context.Context, requestedBefore time.Time) (int64, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	res := db.Unscoped().
+		Where("deletion_requested_at IS NOT NULL AND deletion_requested_at < ?", requestedBefore).
+		Delete(&entity.Profile{})
+	if res.Error != nil {
+		return 0, utilErrs.FromGORM(res, "failed to purge profiles")
+	}
+
+	return res.RowsAffected, nil
+}
+
+func (r *gameListRepository) SaveRefreshToken(ctx context.Context, nickname string, tokenString string) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	userID, err := findUserIDByNickname(db, nickname)
 	if err != nil {
 		return err
 	}
@@ -386,7 +1233,7 @@ func (r *gameListRepository) SaveRefreshToken(nickname string, tokenString strin
 		Token:     tokenString,
 	}
 
-	res := r.db.Create(&refreshToken)
+	res := db.Create(&refreshToken)
 	if res.Error != nil {
 		return utilErrs.FromGORM(res, "unable to save the refresh token")
 	}
@@ -394,9 +1241,12 @@ func (r *gameListRepository) SaveRefreshToken(nickname string, tokenString strin
 	return nil
 }
 
-func (r *gameListRepository) FindRefreshToken(nickname string, tokenString string) error {
+func (r *gameListRepository) FindRefreshToken(ctx context.Context, nickname string, tokenString string) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
 	var result entity.RefreshToken
-	res := r.db.Table("refresh_token, profile").Select("refresh_token.token").Where(
+	res := db.Table("refresh_token, profile").Select("refresh_token.token").Where(
 		"refresh_token.token = ?", tokenString).Where(
 		"refresh_token.profile_id = profile.id").Where(
 		"profile.nickname = ?", nickname).Where(
@@ -411,22 +1261,30 @@ func (r *gameListRepository) FindRefreshToken(nickname string, tokenString strin
 	return nil
 }
 
-func (r *gameListRepository) DeleteRefreshToken(tokenString string) error {
-	res := r.db.Where("token = ?", tokenString).Delete(&entity.RefreshToken{})
-	if res.Error != nil {
+func (r *gameListRepository) DeleteRefreshToken(ctx context.Context, tokenString string) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	// A token which was deleted meanwhile is not found, so of concurrent refreshes of
+	// a token only the first one is committed
+	res := db.Where("token = ?", tokenString).Delete(&entity.RefreshToken{})
+	if res.Error != nil || res.RowsAffected == 0 {
 		return utilErrs.FromGORM(res, "failed to delete refresh token")
 	}
 
 	return nil
 }
 
-func (r *gameListRepository) DeleteAllUserRefreshTokens(nickname string) error {
-	userID, err := r.findUserIDByNickname(nickname)
+func (r *gameListRepository) DeleteAllUserRefreshTokens(ctx context.Context, nickname string) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	userID, err := findUserIDByNickname(db, nickname)
 	if err != nil {
 		return err
 	}
 
-	res := r.db.Where("profile_id = ?", userID).Delete(&entity.RefreshToken{})
+	res := db.Where("profile_id = ?", userID).Delete(&entity.RefreshToken{})
 	if res.Error != nil {
 		return utilErrs.FromGORM(res, "failed to delete refresh token")
 	}
@@ -434,8 +1292,11 @@ func (r *gameListRepository) DeleteAllUserRefreshTokens(nickname string) error {
 	return nil
 }
 
-func (r *gameListRepository) SaveSocialType(socialType entity.SocialType) error {
-	res := r.db.Save(&socialType)
+func (r *gameListRepository) SaveSocialType(ctx context.Context, socialType *entity.SocialType) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	res := db.Save(socialType)
 	if res.Error != nil {
 		return utilErrs.FromGORM(res, "failed to save social type")
 	}
@@ -443,10 +1304,39 @@ func (r *gameListRepository) SaveSocialType(socialType entity.SocialType) error
 	return nil
 }
 
-func (r *gameListRepository) GetAllSocialTypes() ([]entity.SocialType, error) {
+func (r *gameListRepository) UpdateSocialType(ctx context.Context, socialType *entity.SocialType) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	return updateName(db, socialType, socialType.ID, socialType.Name, "social type")
+}
+
+func (r *gameListRepository) DeleteSocialType(ctx context.Context, id uint64, reassignTo uint64) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	return transaction(db, func(tx *gorm.DB) error {
+		if err := reassignReferences(tx, &entity.SocialType{}, socialTypeReferences, id, reassignTo, "social type"); err != nil {
+			return err
+		}
+		return deleteByID(tx, &entity.SocialType{}, id, "social type")
+	})
+}
+
+func (r *gameListRepository) RestoreSocialType(ctx context.Context, id uint64) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	return restoreByID(db, &entity.SocialType{}, id, "social type")
+}
+
+func (r *gameListRepository) GetAllSocialTypes(ctx context.Context, cursor entity.Cursor, limit int) ([]entity.SocialType, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
 	var socialTypes []entity.SocialType
 
-	res := r.db.Find(&socialTypes)
+	res := paginate(db, "", "id", cursor, limit).Find(&socialTypes)
 	if res.Error != nil {
 		return nil, utilErrs.FromGORM(res, "failed to find social types")
 	}
@@ -454,11 +1344,150 @@ func (r *gameListRepository) GetAllSocialTypes() ([]entity.SocialType, error) {
 	return socialTypes, nil
 }
 
-func (r *gameListRepository) findUserIDByNickname(nickname string) (uint64, error) {
+func (r *gameListRepository) CreateScrapeJob(ctx context.Context, job *entity.ScrapeJob) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	// Only one job may run across all instances
+	res := db.Create(job)
+	if constraint, ok := utilErrs.UniqueViolation(res.Error); ok && constraint == "scrape_job_running_key" {
+		return utilErrs.New(utilErrs.Conflict, res.Error, "another scrape job is already running").
+			WithReason(utilErrs.ReasonScrapeJobRunning)
+	}
+	if res.Error != nil {
+		return utilErrs.FromGORM(res, "failed to create scrape job")
+	}
+
+	return nil
+}
+
+func (r *gameListRepository) SaveScrapeJob(ctx context.Context, job entity.ScrapeJob) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	res := db.Save(&job)
+	if res.Error != nil {
+		return utilErrs.FromGORM(res, "failed to save scrape job")
+	}
+
+	return nil
+}
+
+func (r *gameListRepository) GetScrapeJob(ctx context.Context, id uint64) (*entity.ScrapeJob, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	var job entity.ScrapeJob
+	res := db.First(&job, id)
+	if res.Error != nil {
+		return nil, utilErrs.FromGORM(res, fmt.Sprint("couldn't find scrape job with id: ", id))
+	}
+
+	return &job, nil
+}
+
+func (r *gameListRepository) GetScrapeJobs(ctx context.Context, limit int) ([]entity.ScrapeJob, error) {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	var jobs []entity.ScrapeJob
+	res := db.Order("id desc").Limit(limit).Find(&jobs)
+	if res.Error != nil {
+		return nil, utilErrs.FromGORM(res, "failed to get scrape jobs")
+	}
+
+	return jobs, nil
+}
+
+func (r *gameListRepository) BeatScrapeJob(ctx context.Context, id uint64) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	// Jobs which were aborted meanwhile aren't found
+	res := db.Model(&entity.ScrapeJob{}).
+		Where("id = ? AND status = ?", id, entity.ScrapeJobRunning).
+		Update("heartbeat_at", time.Now())
+	if res.Error != nil || res.RowsAffected == 0 {
+		return utilErrs.FromGORM(res, fmt.Sprint("couldn't find running scrape job with id: ", id))
+	}
+
+	return nil
+}
+
+// AbortStaleScrapeJobs leaves jobs of other instances which still beat running
+func (r *gameListRepository) AbortStaleScrapeJobs(ctx context.Context, staleBefore time.Time, reason string) error {
+	db, cancel := r.withContext(ctx)
+	defer cancel()
+
+	res := db.Model(&entity.ScrapeJob{}).
+		Where("status = ? AND heartbeat_at < ?", entity.ScrapeJobRunning, staleBefore).
+		Updates(map[string]interface{}{
+			"status":      entity.ScrapeJobFailed,
+			"finished_at": time.Now(),
+			"error":       reason,
+		})
+	if res.Error != nil {
+		return utilErrs.FromGORM(res, "failed to abort stale scrape jobs")
+	}
+
+	return nil
+}
+
+func (r *gameListRepository) Ping(ctx context.Context) error {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return utilErrs.New(utilErrs.Internal, err, "failed to get connection pool")
+	}
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return utilErrs.New(utilErrs.Internal, err, "failed to ping database")
+	}
+
+	return nil
+}
+
+func (r *gameListRepository) SchemaVersion(ctx context.Context) (int64, error) {
+	var rows []struct {
+		VersionID int64
+		IsApplied bool
+	}
+	res := r.db.WithContext(ctx).Table(GOOSE_VERSION_TABLE).Select("version_id", "is_applied").Order("id desc").Find(&rows)
+	if res.Error != nil {
+		return 0, utilErrs.FromDB(res.Error, "failed to get schema version")
+	}
+
+	// Goose adds a row for every migration up and down, the latest row of a version tells
+	// if it's applied
+	rolledBack := make(map[int64]bool)
+	for _, row := range rows {
+		if rolledBack[row.VersionID] {
+			continue
+		}
+		if row.IsApplied {
+			return row.VersionID, nil
+		}
+		rolledBack[row.VersionID] = true
+	}
+
+	return 0, nil
+}
+
+func (r *gameListRepository) Close() error {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return utilErrs.New(utilErrs.Internal, err, "failed to get connection pool")
+	}
+	if err := sqlDB.Close(); err != nil {
+		return utilErrs.New(utilErrs.Internal, err, "failed to close database")
+	}
+
+	return nil
+}
+
+func findUserIDByNickname(db *gorm.DB, nickname string) (uint64, error) {
 	var userID uint64
-	res := r.db.Table("profile").Select("id").Take(&userID, map[string]string{"nickname": nickname})
+	res := db.Table("profile").Select("id").Take(&userID, map[string]string{"nickname": nickname})
 	if res.Error != nil {
-		return 0, utilErrs.FromGORM(res, fmt.Sprintf("failed to find user with nickname \"%s\"", nickname))
+		return 0, utilErrs.FromGORM(res, fmt.Sprintf("failed to find user with nickname \"%s\"", nickname)).NotFoundAs(utilErrs.ReasonProfileNotFound)
 	}
 
 	return userID, nil
@@ -2,12 +2,64 @@ package repository
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/br3w0r/gamelist-backend/entity"
 	utilErrs "github.com/br3w0r/gamelist-backend/util/errors"
 	"gorm.io/gorm"
 )
 
+const (
+	// Transactions failed by serialization failures or deadlocks are run again
+	TX_MAX_ATTEMPTS = 3
+	TX_RETRY_DELAY  = 50 * time.Millisecond
+)
+
+// transaction runs fn in a transaction of db and retries it if it may succeed on
+// another attempt. fn must not leave changes outside of the transaction.
+//
+// In a transaction of WithTx, fn runs in a savepoint and isn't retried, since failed
+// serialization aborts the outer transaction, which is retried as a whole.
+func transaction(db *gorm.DB, fn func(tx *gorm.DB) error) error {
+	if inTransaction(db) {
+		err := db.Transaction(fn)
+		if _, ok := err.(*utilErrs.Error); err != nil && !ok {
+			return utilErrs.FromDB(err, "failed to create savepoint")
+		}
+		return err
+	}
+
+	return retryTransaction(func() error {
+		return db.Transaction(fn)
+	})
+}
+
+func inTransaction(db *gorm.DB) bool {
+	committer, ok := db.Statement.ConnPool.(gorm.TxCommitter)
+	return ok && committer != nil
+}
+
+func retryTransaction(run func() error) error {
+	var err error
+	for attempt := 1; attempt <= TX_MAX_ATTEMPTS; attempt++ {
+		err = run()
+		if err == nil || !utilErrs.Retryable(err) {
+			break
+		}
+		if attempt < TX_MAX_ATTEMPTS {
+			time.Sleep(time.Duration(attempt) * TX_RETRY_DELAY)
+		}
+	}
+
+	// Commits fail with errors of the driver
+	if _, ok := err.(*utilErrs.Error); err != nil && !ok {
+		return utilErrs.FromDB(err, "failed to commit transaction")
+	}
+
+	return err
+}
+
 func CheckSocialTypes(db *gorm.DB, profile *entity.Profile) error {
 	for i := range profile.Socials {
 		res := db.First(&entity.SocialType{}, profile.Socials[i].TypeID)
@@ -17,3 +69,412 @@ func CheckSocialTypes(db *gorm.DB, profile *entity.Profile) error {
 	}
 	return nil
 }
+
+// findGameCatalog resolves platforms and genres of the game, which must already exist
+func findGameCatalog(db *gorm.DB, game *entity.GameProperties) error {
+	for i := range game.Platforms {
+		res := db.First(&game.Platforms[i], game.Platforms[i])
+		if res.Error != nil {
+			return utilErrs.FromGORM(res,
+				fmt.Sprintf("couldn't find platform with id %d and name %s",
+					game.Platforms[i].ID, game.Platforms[i].Name,
+				))
+		}
+	}
+	for i := range game.Genres {
+		res := db.First(&game.Genres[i], game.Genres[i])
+		if res.Error != nil {
+			return utilErrs.FromGORM(res,
+				fmt.Sprintf("couldn't find genre with id %d and name %s",
+					game.Genres[i].ID, game.Genres[i].Name,
+				))
+		}
+	}
+
+	return nil
+}
+
+// updateName renames a catalog item such as a genre or a platform
+func updateName(db *gorm.DB, model interface{}, id uint64, name string, what string) error {
+	if name == "" {
+		return utilErrs.Newf(utilErrs.BadInput, nil, "name of %s can't be empty", what)
+	}
+
+	res := db.Model(model).Where("id = ?", id).Update("name", name)
+	if res.Error != nil || res.RowsAffected == 0 {
+		return utilErrs.FromGORM(res, fmt.Sprintf("failed to update %s with id: %d", what, id)).NotFoundAs(utilErrs.ReasonItemNotFound)
+	}
+
+	return nil
+}
+
+// deleteByID soft-deletes a record of the model's table
+func deleteByID(db *gorm.DB, model interface{}, id uint64, what string) error {
+	res := db.Delete(model, id)
+	if res.Error != nil || res.RowsAffected == 0 {
+		return utilErrs.FromGORM(res, fmt.Sprintf("failed to delete %s with id: %d", what, id)).NotFoundAs(utilErrs.ReasonItemNotFound)
+	}
+
+	return nil
+}
+
+// restoreByID brings back a soft-deleted record of the model's table
+func restoreByID(db *gorm.DB, model interface{}, id uint64, what string) error {
+	res := db.Unscoped().Model(model).Where("id = ? AND deleted_at IS NOT NULL", id).Update("deleted_at", nil)
+	if res.Error != nil || res.RowsAffected == 0 {
+		return utilErrs.FromGORM(res, fmt.Sprintf("couldn't find deleted %s with id: %d", what, id)).NotFoundAs(utilErrs.ReasonItemNotFound)
+	}
+
+	return nil
+}
+
+// reference is a column which points to a catalog item. If the column is a part of
+// the primary key together with owner, an owner can only reference the item once.
+type reference struct {
+	table  string
+	column string
+	owner  string
+	what   string
+}
+
+var (
+	gameReferences       = []reference{{"profile_game", "game_id", "profile_id", "list entries"}}
+	listTypeReferences   = []reference{{"profile_game", "list_type_id", "", "list entries"}}
+	genreReferences      = []reference{{"game_genres", "genre_id", "game_properties_id", "games"}}
+	platformReferences   = []reference{{"game_platforms", "platform_id", "game_properties_id", "games"}}
+	socialTypeReferences = []reference{{"social", "type_id", "profile_id", "socials"}}
+)
+
+// checkUnreferenced fails if any of the references point to the item
+func checkUnreferenced(db *gorm.DB, refs []reference, id uint64, what string) error {
+	for _, ref := range refs {
+		var count int64
+		res := db.Table(ref.table).Where(ref.column+" = ?", id).Count(&count)
+		if res.Error != nil {
+			return utilErrs.FromGORM(res, fmt.Sprintf("failed to count %s of %s", ref.what, what))
+		}
+		if count > 0 {
+			return utilErrs.Newf(utilErrs.Conflict, nil,
+				"%s with id %d is used by %d %s: reassign them to another %s", what, id, count, ref.what, what).
+				WithReason(utilErrs.ReasonItemInUse)
+		}
+	}
+
+	return nil
+}
+
+// reassignReferences points the references of the item to the reassignTo one.
+// Without reassignTo the item must be unreferenced. Owners which already reference
+// reassignTo keep their reference to it.
+func reassignReferences(db *gorm.DB, model interface{}, refs []reference, id uint64, reassignTo uint64, what string) error {
+	if reassignTo == 0 {
+		return checkUnreferenced(db, refs, id, what)
+	}
+	if reassignTo == id {
+		return utilErrs.Newf(utilErrs.BadInput, nil, "can't reassign %s to itself", what)
+	}
+
+	res := db.Model(model).Where("id = ?", reassignTo).Take(model)
+	if res.Error != nil {
+		return utilErrs.FromGORM(res, fmt.Sprintf("couldn't find %s with id: %d", what, reassignTo)).NotFoundAs(utilErrs.ReasonItemNotFound)
+	}
+
+	for _, ref := range refs {
+		if ref.owner != "" {
+			res = db.Exec(fmt.Sprintf(`delete from %[1]s where %[2]s = @id
+				and %[3]s in (select %[3]s from %[1]s where %[2]s = @reassign)`, ref.table, ref.column, ref.owner),
+				map[string]interface{}{"id": id, "reassign": reassignTo})
+			if res.Error != nil {
+				return utilErrs.FromGORM(res, fmt.Sprintf("failed to delete conflicting %s of %s", ref.what, what))
+			}
+		}
+
+		res = db.Table(ref.table).Where(ref.column+" = ?", id).Update(ref.column, reassignTo)
+		if res.Error != nil {
+			return utilErrs.FromGORM(res, fmt.Sprintf("failed to reassign %s of %s", ref.what, what))
+		}
+	}
+
+	return nil
+}
+
+// firstOrCreatePlatforms resolves platforms by name, creating the ones that don't exist yet.
+// Deleted platforms are left out.
+func firstOrCreatePlatforms(db *gorm.DB, platforms []entity.Platform) ([]entity.Platform, error) {
+	result := make([]entity.Platform, 0, len(platforms))
+	for _, platform := range platforms {
+		if platform.Name == "" {
+			continue
+		}
+
+		found := entity.Platform{Name: platform.Name}
+		res := db.Unscoped().Where(&found).FirstOrCreate(&found)
+		if res.Error != nil {
+			return nil, utilErrs.FromGORM(res, fmt.Sprintf("failed to find or create platform %s", platform.Name))
+		}
+		if found.DeletedAt.Valid {
+			continue
+		}
+		result = append(result, found)
+	}
+	return result, nil
+}
+
+// firstOrCreateGenres resolves genres by name, creating the ones that don't exist yet.
+// Deleted genres are left out.
+func firstOrCreateGenres(db *gorm.DB, genres []entity.Genre) ([]entity.Genre, error) {
+	result := make([]entity.Genre, 0, len(genres))
+	for _, genre := range genres {
+		if genre.Name == "" {
+			continue
+		}
+
+		found := entity.Genre{Name: genre.Name}
+		res := db.Unscoped().Where(&found).FirstOrCreate(&found)
+		if res.Error != nil {
+			return nil, utilErrs.FromGORM(res, fmt.Sprintf("failed to find or create genre %s", genre.Name))
+		}
+		if found.DeletedAt.Valid {
+			continue
+		}
+		result = append(result, found)
+	}
+	return result, nil
+}
+
+// mergeGame updates fields of the stored game with fields of the incoming one
+// where the incoming source wins by priority. Empty incoming fields are ignored.
+// Platforms and genres of both games must be resolved to their ids.
+func mergeGame(
+	db *gorm.DB,
+	stored *entity.GameProperties,
+	incoming *entity.GameProperties,
+	source string,
+	owners map[string]string,
+	priority entity.SourcePriority,
+) (bool, error) {
+	wins := func(field string) bool {
+		return priority.Wins(field, source, owners[field])
+	}
+
+	updates := map[string]interface{}{}
+	if incoming.Name != "" && incoming.Name != stored.Name && wins(entity.GameFieldName) {
+		updates["name"] = incoming.Name
+	}
+	if incoming.ImageURL != "" && incoming.ImageURL != stored.ImageURL && wins(entity.GameFieldImageURL) {
+		updates["image_url"] = incoming.ImageURL
+	}
+	if incoming.YearReleased != 0 && incoming.YearReleased != stored.YearReleased && wins(entity.GameFieldYearReleased) {
+		updates["year_released"] = incoming.YearReleased
+	}
+
+	changed := false
+	if len(updates) > 0 {
+		res := db.Model(stored).Updates(updates)
+		if res.Error != nil {
+			return false, utilErrs.FromGORM(res, "failed to update game")
+		}
+		changed = true
+	}
+
+	if len(incoming.Platforms) > 0 && !samePlatforms(stored.Platforms, incoming.Platforms) && wins(entity.GameFieldPlatforms) {
+		if err := db.Model(stored).Association("Platforms").Replace(incoming.Platforms); err != nil {
+			return false, utilErrs.FromDB(err, "failed to update game's platforms")
+		}
+		changed = true
+	}
+	if len(incoming.Genres) > 0 && !sameGenres(stored.Genres, incoming.Genres) && wins(entity.GameFieldGenres) {
+		if err := db.Model(stored).Association("Genres").Replace(incoming.Genres); err != nil {
+			return false, utilErrs.FromDB(err, "failed to update game's genres")
+		}
+		changed = true
+	}
+
+	return changed, nil
+}
+
+func gameFieldSources(db *gorm.DB, gameID uint64) (map[string]string, error) {
+	var sources []entity.GameFieldSource
+	res := db.Where("game_id = ?", gameID).Find(&sources)
+	if res.Error != nil {
+		return nil, utilErrs.FromGORM(res, "failed to get game's field sources")
+	}
+
+	owners := make(map[string]string, len(sources))
+	for i := range sources {
+		owners[sources[i].Field] = sources[i].Source
+	}
+
+	return owners, nil
+}
+
+// saveGameFieldSources makes the source an owner of every non-empty field it wins
+func saveGameFieldSources(
+	db *gorm.DB,
+	game *entity.GameProperties,
+	source string,
+	owners map[string]string,
+	priority entity.SourcePriority,
+) error {
+	present := map[string]bool{
+		entity.GameFieldName:         game.Name != "",
+		entity.GameFieldImageURL:     game.ImageURL != "",
+		entity.GameFieldYearReleased: game.YearReleased != 0,
+		entity.GameFieldPlatforms:    len(game.Platforms) > 0,
+		entity.GameFieldGenres:       len(game.Genres) > 0,
+	}
+
+	var sources []entity.GameFieldSource
+	for _, field := range entity.GameFields {
+		if present[field] && owners[field] != source && priority.Wins(field, source, owners[field]) {
+			sources = append(sources, entity.GameFieldSource{
+				GameID: game.ID,
+				Field:  field,
+				Source: source,
+			})
+		}
+	}
+	if len(sources) == 0 {
+		return nil
+	}
+
+	res := db.Save(&sources)
+	if res.Error != nil {
+		return utilErrs.FromGORM(res, "failed to save game's field sources")
+	}
+
+	return nil
+}
+
+func samePlatforms(a []entity.Platform, b []entity.Platform) bool {
+	idsA := make([]uint64, len(a))
+	for i := range a {
+		idsA[i] = a[i].ID
+	}
+	idsB := make([]uint64, len(b))
+	for i := range b {
+		idsB[i] = b[i].ID
+	}
+
+	return sameIDs(idsA, idsB)
+}
+
+func sameGenres(a []entity.Genre, b []entity.Genre) bool {
+	idsA := make([]uint64, len(a))
+	for i := range a {
+		idsA[i] = a[i].ID
+	}
+	idsB := make([]uint64, len(b))
+	for i := range b {
+		idsB[i] = b[i].ID
+	}
+
+	return sameIDs(idsA, idsB)
+}
+
+func sameIDs(a []uint64, b []uint64) bool {
+	set := make(map[uint64]bool, len(a))
+	for _, id := range a {
+		set[id] = true
+	}
+
+	other := make(map[uint64]bool, len(b))
+	for _, id := range b {
+		if !set[id] {
+			return false
+		}
+		other[id] = true
+	}
+
+	return len(set) == len(other)
+}
+
+// listGame adds the game to the user's list or changes its list type. List type 0
+// removes the game from the list.
+func listGame(db *gorm.DB, userId uint64, gameId uint64, listType uint64) error {
+	gameId, err := resolveGameID(db, gameId)
+	if err != nil {
+		return err
+	}
+
+	res := db.First(&entity.GameProperties{}, gameId)
+	if res.Error != nil {
+		return utilErrs.FromGORM(res, fmt.Sprint("couldn't find game with id: ", gameId)).NotFoundAs(utilErrs.ReasonGameNotFound)
+	}
+
+	if listType == 0 {
+		res = db.Where("profile_id = ? AND game_id = ?", userId, gameId).Delete(&entity.ProfileGame{})
+		if res.Error != nil {
+			return utilErrs.FromGORM(res, "failed to remove game from list")
+		}
+		return nil
+	}
+
+	res = db.First(&entity.ListType{}, listType)
+	if res.Error != nil {
+		return utilErrs.FromGORM(res, fmt.Sprint("couldn't find list type with id: ", listType)).NotFoundAs(utilErrs.ReasonListTypeNotFound)
+	}
+
+	listGame := entity.ProfileGame{
+		ProfileID:  userId,
+		GameID:     gameId,
+		ListTypeID: listType,
+	}
+
+	res = db.Save(&listGame)
+	if res.Error != nil {
+		return utilErrs.FromGORM(res, "failed to save changes")
+	}
+
+	return nil
+}
+
+// resolveGameID follows the redirect of a merged game
+func resolveGameID(db *gorm.DB, id uint64) (uint64, error) {
+	var redirect entity.GameRedirect
+	res := db.Limit(1).Find(&redirect, id)
+	if res.Error != nil {
+		return 0, utilErrs.FromGORM(res, "failed to resolve game id")
+	}
+
+	if res.RowsAffected == 0 {
+		return id, nil
+	}
+
+	return redirect.NewID, nil
+}
+
+// paginate orders the query by keyColumn, if it's set, and idColumn and takes
+// limit+1 items after the cursor. Limits below 1 take all items.
+func paginate(db *gorm.DB, keyColumn string, idColumn string, cursor entity.Cursor, limit int) *gorm.DB {
+	if keyColumn == "" {
+		if cursor.ID != 0 {
+			db = db.Where(idColumn+" > ?", cursor.ID)
+		}
+		db = db.Order(idColumn)
+	} else {
+		if cursor != (entity.Cursor{}) {
+			db = db.Where(fmt.Sprintf("(%s, %s) > (?, ?)", keyColumn, idColumn), cursor.Key, cursor.ID)
+		}
+		db = db.Order(keyColumn).Order(idColumn)
+	}
+
+	if limit > 0 {
+		db = db.Limit(limit + 1)
+	}
+
+	return db
+}
+
+// profileConflict tells taken nicknames and emails apart by the violated constraint
+func profileConflict(res *gorm.DB, msg string) error {
+	constraint, _ := utilErrs.UniqueViolation(res.Error)
+	switch {
+	case strings.Contains(constraint, "nickname"):
+		return utilErrs.New(utilErrs.Conflict, res.Error, "nickname is already taken").WithReason(utilErrs.ReasonNicknameTaken)
+	case strings.Contains(constraint, "email"):
+		return utilErrs.New(utilErrs.Conflict, res.Error, "email is already taken").WithReason(utilErrs.ReasonEmailTaken)
+	}
+
+	return utilErrs.FromGORM(res, msg)
+}
@@ -0,0 +1,761 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/br3w0r/gamelist-backend/entity"
+	utilErrs "github.com/br3w0r/gamelist-backend/util/errors"
+	"github.com/br3w0r/gamelist-backend/util/metrics"
+	"github.com/jackc/pgconn"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/smartystreets/goconvey/convey"
+	"github.com/uptrace/opentelemetry-go-extra/otelgorm"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestRepository connects to the database of TEST_DATABASE_DSN with all migrations applied
+func newTestRepository(t *testing.T) *gameListRepository {
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN isn't set")
+	}
+
+	repo, err := NewGamelistRepository(postgres.Open(dsn), logger.Config{
+		LogLevel: logger.Silent,
+	}, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return repo.(*gameListRepository)
+}
+
+// createTestGame saves a game which is deleted with everything referencing it after the test
+func createTestGame(t *testing.T, repo *gameListRepository, name string) *entity.GameProperties {
+	game := &entity.GameProperties{Name: name, ImageURL: "https://example.com/game.png", YearReleased: 2021}
+	if err := repo.SaveGame(context.Background(), game); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { deleteTestGame(repo, game.ID) })
+
+	return game
+}
+
+func deleteTestGame(repo *gameListRepository, id uint64) {
+	repo.db.Exec("delete from game_redirect where old_id = ? or new_id = ?", id, id)
+	repo.db.Exec("delete from game_duplicate where game_id = ? or duplicate_id = ?", id, id)
+	repo.db.Exec("delete from game_external_id where game_id = ?", id)
+	repo.db.Exec("delete from game_field_source where game_id = ?", id)
+	repo.db.Exec("delete from game_platforms where game_properties_id = ?", id)
+	repo.db.Exec("delete from game_genres where game_properties_id = ?", id)
+	repo.db.Exec("delete from profile_game where game_id = ?", id)
+	repo.db.Unscoped().Delete(&entity.GameProperties{}, id)
+}
+
+// createTestProfile creates a profile which is deleted with its data after the test
+func createTestProfile(t *testing.T, repo *gameListRepository, nickname string) uint64 {
+	err := repo.CreateProfile(context.Background(), entity.Profile{
+		ProfileInfo: entity.ProfileInfo{Nickname: nickname},
+		Email:       nickname + "@example.com",
+		Password:    "password",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	userID, err := findUserIDByNickname(repo.db, nickname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Lists, socials and tokens are deleted on cascade
+	t.Cleanup(func() { repo.db.Unscoped().Delete(&entity.Profile{}, userID) })
+
+	return userID
+}
+
+// listTypeOf is the list type of the game in the user's list, 0 if it isn't listed
+func listTypeOf(t *testing.T, repo *gameListRepository, userID uint64, gameID uint64) uint64 {
+	var entries []entity.ProfileGame
+	res := repo.db.Where("profile_id = ? and game_id = ?", userID, gameID).Find(&entries)
+	if res.Error != nil {
+		t.Fatal(res.Error)
+	}
+	if len(entries) == 0 {
+		return 0
+	}
+
+	return entries[0].ListTypeID
+}
+
+func TestPurgeProfiles(t *testing.T) {
+	repo := newTestRepository(t)
+	suffix := time.Now().UnixNano() % 1000000
+
+	socialType := entity.SocialType{Name: fmt.Sprint("social-", suffix)}
+	game := entity.GameProperties{Name: fmt.Sprint("Purge Test ", suffix), YearReleased: 2021}
+	if err := repo.SaveSocialType(context.Background(), &socialType); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.SaveGame(context.Background(), &game); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		repo.db.Unscoped().Delete(&game)
+		repo.db.Unscoped().Delete(&socialType)
+	})
+
+	nickname := fmt.Sprint("purge", suffix)
+	err := repo.CreateProfile(context.Background(), entity.Profile{
+		ProfileInfo: entity.ProfileInfo{
+			Nickname: nickname,
+			Socials:  []entity.Social{{TypeID: socialType.ID, Data: "@purge"}},
+		},
+		Email:    nickname + "@example.com",
+		Password: "password",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	userID, err := findUserIDByNickname(repo.db, nickname)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	convey.Convey("Purged profiles shouldn't leave any data behind", t, func() {
+		convey.So(repo.ListGame(context.Background(), nickname, game.ID, 1), convey.ShouldBeNil)
+		convey.So(repo.SaveRefreshToken(context.Background(), nickname, fmt.Sprint("revoked-", suffix)), convey.ShouldBeNil)
+		convey.So(repo.DeleteRefreshToken(context.Background(), fmt.Sprint("revoked-", suffix)), convey.ShouldBeNil)
+		convey.So(repo.SaveRefreshToken(context.Background(), nickname, fmt.Sprint("active-", suffix)), convey.ShouldBeNil)
+
+		requestedAt := time.Now().Add(-time.Hour)
+		_, err := repo.RequestProfileDeletion(context.Background(), nickname, requestedAt)
+		convey.So(err, convey.ShouldBeNil)
+
+		purged, err := repo.PurgeProfiles(context.Background(), requestedAt.Add(-time.Minute))
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(purged, convey.ShouldEqual, 0)
+
+		purged, err = repo.PurgeProfiles(context.Background(), time.Now())
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(purged, convey.ShouldBeGreaterThanOrEqualTo, 1)
+
+		for _, table := range []string{"social", "profile_game", "refresh_token"} {
+			var count int64
+			res := repo.db.Table(table).Where("profile_id = ?", userID).Count(&count)
+			convey.So(res.Error, convey.ShouldBeNil)
+			convey.So(count, convey.ShouldEqual, 0)
+		}
+
+		var count int64
+		res := repo.db.Table("profile").Where("id = ?", userID).Count(&count)
+		convey.So(res.Error, convey.ShouldBeNil)
+		convey.So(count, convey.ShouldEqual, 0)
+	})
+}
+
+func TestProfileConflicts(t *testing.T) {
+	convey.Convey("Unique violations should tell taken nicknames and emails apart", t, func() {
+		violation := func(constraint string) *gorm.DB {
+			return &gorm.DB{Error: &pgconn.PgError{Code: "23505", ConstraintName: constraint}}
+		}
+
+		err := profileConflict(violation("profile_nickname_key"), "failed to create profile").(*utilErrs.Error)
+		convey.So(err.Code(), convey.ShouldEqual, utilErrs.Conflict)
+		convey.So(err.Reason(), convey.ShouldEqual, utilErrs.ReasonNicknameTaken)
+
+		err = profileConflict(violation("idx_profile_email"), "failed to create profile").(*utilErrs.Error)
+		convey.So(err.Reason(), convey.ShouldEqual, utilErrs.ReasonEmailTaken)
+
+		err = profileConflict(violation("profile_pkey"), "failed to create profile").(*utilErrs.Error)
+		convey.So(err.Code(), convey.ShouldEqual, utilErrs.Conflict)
+		convey.So(err.Reason(), convey.ShouldEqual, utilErrs.ReasonAlreadyExists)
+
+		err = profileConflict(&gorm.DB{Error: errors.New("connection refused")}, "failed to create profile").(*utilErrs.Error)
+		convey.So(err.Code(), convey.ShouldEqual, utilErrs.Internal)
+		convey.So(err.Reason(), convey.ShouldEqual, utilErrs.Reason("INTERNAL"))
+	})
+}
+
+func TestQueryTimeout(t *testing.T) {
+	convey.Convey("Statements should be canceled with their calls or after the timeout", t, func() {
+		db, err := gorm.Open(postgres.Open("host=localhost"), &gorm.Config{
+			DryRun:               true,
+			DisableAutomaticPing: true,
+			Logger:               logger.Discard,
+		})
+		convey.So(err, convey.ShouldBeNil)
+
+		var contexts []context.Context
+		err = db.Callback().Query().Before("gorm:query").Register("test:context", func(tx *gorm.DB) {
+			contexts = append(contexts, tx.Statement.Context)
+		})
+		convey.So(err, convey.ShouldBeNil)
+
+		repo := &gameListRepository{db: db, timeout: time.Minute}
+		ctx, cancel := context.WithCancel(context.Background())
+		_, err = repo.GetAllGenres(ctx, entity.Cursor{}, 10)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(contexts, convey.ShouldHaveLength, 1)
+
+		deadline, ok := contexts[0].Deadline()
+		convey.So(ok, convey.ShouldBeTrue)
+		convey.So(deadline, convey.ShouldHappenWithin, time.Minute, time.Now())
+		// Calls release their contexts when they return
+		convey.So(contexts[0].Err(), convey.ShouldEqual, context.Canceled)
+
+		repo.timeout = 0
+		_, err = repo.GetAllGenres(ctx, entity.Cursor{}, 10)
+		convey.So(err, convey.ShouldBeNil)
+		_, ok = contexts[1].Deadline()
+		convey.So(ok, convey.ShouldBeFalse)
+		cancel()
+	})
+
+	convey.Convey("Queries out of time should be timeouts", t, func() {
+		for _, cause := range []error{
+			fmt.Errorf("timeout: %w", context.DeadlineExceeded),
+			context.Canceled,
+			&pgconn.PgError{Code: "57014", Message: "canceling statement due to statement timeout"},
+		} {
+			err := utilErrs.FromGORM(&gorm.DB{Error: cause}, "failed to get games")
+			convey.So(err.Code(), convey.ShouldEqual, utilErrs.Timeout)
+			convey.So(err.Cause(), convey.ShouldEqual, cause)
+		}
+	})
+}
+
+func TestTakenNickname(t *testing.T) {
+	repo := newTestRepository(t)
+
+	convey.Convey("Taken nicknames should be conflicts in the database", t, func() {
+		nickname := fmt.Sprint("taken", time.Now().UnixNano()%1000000)
+
+		profile := entity.Profile{ProfileInfo: entity.ProfileInfo{Nickname: nickname}, Email: nickname + "@mail.com", Password: "password"}
+		convey.So(repo.CreateProfile(context.Background(), profile), convey.ShouldBeNil)
+		defer repo.db.Unscoped().Where("nickname = ?", nickname).Delete(&entity.Profile{})
+
+		err := repo.CreateProfile(context.Background(), profile)
+		convey.So(err, convey.ShouldNotBeNil)
+		convey.So(err.(*utilErrs.Error).Reason(), convey.ShouldEqual, utilErrs.ReasonNicknameTaken)
+	})
+}
+
+func TestConstraintViolations(t *testing.T) {
+	convey.Convey("Constraint violations should name the violated constraint", t, func() {
+		err := utilErrs.FromGORM(&gorm.DB{Error: &pgconn.PgError{
+			Code: "23505", ConstraintName: "genres_name_key", Detail: "Key (name)=(RPG) already exists.",
+		}}, "failed to create genre")
+		convey.So(err.Code(), convey.ShouldEqual, utilErrs.Conflict)
+		convey.So(err.Reason(), convey.ShouldEqual, utilErrs.ReasonAlreadyExists)
+		convey.So(err.Error(), convey.ShouldEqual, "failed to create genre: (name)=(RPG) already exists")
+
+		err = utilErrs.FromGORM(&gorm.DB{Statement: &gorm.Statement{Table: "genres"}, Error: &pgconn.PgError{
+			Code: "23503", ConstraintName: "fk_game_genres_genre", TableName: "game_genres",
+			Detail: `Key (id)=(3) is still referenced from table "game_genres".`,
+		}}, "failed to delete genre")
+		convey.So(err.Code(), convey.ShouldEqual, utilErrs.Conflict)
+		convey.So(err.Reason(), convey.ShouldEqual, utilErrs.ReasonItemInUse)
+		convey.So(err.Error(), convey.ShouldContainSubstring, "is still referenced by game_genres")
+
+		err = utilErrs.FromGORM(&gorm.DB{Statement: &gorm.Statement{Table: "profile_game"}, Error: &pgconn.PgError{
+			Code: "23503", ConstraintName: "fk_profile_game_list_type", TableName: "profile_game",
+			Detail: `Key (list_type_id)=(42) is not present in table "list_types".`,
+		}}, "failed to list game")
+		convey.So(err.Code(), convey.ShouldEqual, utilErrs.BadInput)
+		convey.So(err.Reason(), convey.ShouldEqual, utilErrs.ReasonReferenceNotFound)
+		convey.So(err.Error(), convey.ShouldContainSubstring, "(list_type_id)=(42)")
+
+		err = utilErrs.FromGORM(&gorm.DB{Error: &pgconn.PgError{Code: "23514", ConstraintName: "chk_games_year"}}, "failed to update game")
+		convey.So(err.Code(), convey.ShouldEqual, utilErrs.BadInput)
+		convey.So(err.Reason(), convey.ShouldEqual, utilErrs.ReasonConstraintViolated)
+		convey.So(err.Error(), convey.ShouldEqual, "failed to update game: violates check chk_games_year")
+
+		for _, code := range []string{"40001", "40P01"} {
+			err = utilErrs.FromGORM(&gorm.DB{Error: &pgconn.PgError{Code: code}}, "failed to merge games")
+			convey.So(err.Code(), convey.ShouldEqual, utilErrs.Conflict)
+			convey.So(err.Reason(), convey.ShouldEqual, utilErrs.ReasonConcurrentChange)
+		}
+	})
+
+	convey.Convey("Foreign key violations shouldn't depend on the language of messages", t, func() {
+		err := utilErrs.FromGORM(&gorm.DB{Statement: &gorm.Statement{Table: "genres"}, Error: &pgconn.PgError{
+			Code: "23503", ConstraintName: "fk_game_genres_genre", TableName: "game_genres",
+			Detail: `Schlüssel (id)=(3) wird noch aus Tabelle »game_genres« verwiesen.`,
+		}}, "failed to delete genre")
+		convey.So(err.Code(), convey.ShouldEqual, utilErrs.Conflict)
+		convey.So(err.Reason(), convey.ShouldEqual, utilErrs.ReasonItemInUse)
+		convey.So(err.Error(), convey.ShouldContainSubstring, "fk_game_genres_genre is still referenced by game_genres")
+
+		err = utilErrs.FromGORM(&gorm.DB{Statement: &gorm.Statement{Table: "profile_game"}, Error: &pgconn.PgError{
+			Code: "23503", ConstraintName: "fk_profile_game_list_type", TableName: "profile_game",
+			Detail: `Schlüssel (list_type_id)=(42) ist nicht in Tabelle »list_types« vorhanden.`,
+		}}, "failed to list game")
+		convey.So(err.Code(), convey.ShouldEqual, utilErrs.BadInput)
+		convey.So(err.Reason(), convey.ShouldEqual, utilErrs.ReasonReferenceNotFound)
+	})
+}
+
+func TestTransactionRetries(t *testing.T) {
+	convey.Convey("Serialization failures should be retried", t, func() {
+		attempts := 0
+		err := retryTransaction(func() error {
+			attempts++
+			if attempts < TX_MAX_ATTEMPTS {
+				return utilErrs.FromDB(&pgconn.PgError{Code: "40001"}, "failed to list game")
+			}
+			return nil
+		})
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(attempts, convey.ShouldEqual, TX_MAX_ATTEMPTS)
+
+		convey.Convey("Up to TX_MAX_ATTEMPTS times", func() {
+			attempts := 0
+			err := retryTransaction(func() error {
+				attempts++
+				return &pgconn.PgError{Code: "40P01"}
+			})
+			convey.So(attempts, convey.ShouldEqual, TX_MAX_ATTEMPTS)
+			convey.So(err.(*utilErrs.Error).Reason(), convey.ShouldEqual, utilErrs.ReasonConcurrentChange)
+		})
+
+		convey.Convey("But other errors shouldn't", func() {
+			attempts := 0
+			err := retryTransaction(func() error {
+				attempts++
+				return utilErrs.New(utilErrs.NotFound, nil, "game not found")
+			})
+			convey.So(attempts, convey.ShouldEqual, 1)
+			convey.So(err.(*utilErrs.Error).Code(), convey.ShouldEqual, utilErrs.NotFound)
+		})
+	})
+}
+
+func TestListGame(t *testing.T) {
+	repo := newTestRepository(t)
+	suffix := time.Now().UnixNano() % 1000000
+	ctx := context.Background()
+
+	game := createTestGame(t, repo, fmt.Sprint("List Test ", suffix))
+	nickname := fmt.Sprint("list", suffix)
+	userID := createTestProfile(t, repo, nickname)
+
+	convey.Convey("Games should be listed and relisted with other list types", t, func() {
+		convey.So(repo.ListGame(ctx, nickname, game.ID, 1), convey.ShouldBeNil)
+		convey.So(listTypeOf(t, repo, userID, game.ID), convey.ShouldEqual, 1)
+
+		convey.So(repo.ListGame(ctx, nickname, game.ID, 2), convey.ShouldBeNil)
+		convey.So(listTypeOf(t, repo, userID, game.ID), convey.ShouldEqual, 2)
+	})
+
+	convey.Convey("List type 0 should remove the game from the list", t, func() {
+		convey.So(repo.ListGame(ctx, nickname, game.ID, 0), convey.ShouldBeNil)
+		convey.So(listTypeOf(t, repo, userID, game.ID), convey.ShouldEqual, 0)
+
+		var count int64
+		repo.db.Table("profile_game").Where("profile_id = ?", userID).Count(&count)
+		convey.So(count, convey.ShouldEqual, 0)
+
+		// Games which aren't listed are removed already
+		convey.So(repo.ListGame(ctx, nickname, game.ID, 0), convey.ShouldBeNil)
+	})
+
+	convey.Convey("Unknown games and list types should be reported", t, func() {
+		err := repo.ListGame(ctx, nickname, game.ID, 1<<31-1).(*utilErrs.Error)
+		convey.So(err.Reason(), convey.ShouldEqual, utilErrs.ReasonListTypeNotFound)
+
+		err = repo.ListGame(ctx, nickname, 1<<31-1, 0).(*utilErrs.Error)
+		convey.So(err.Reason(), convey.ShouldEqual, utilErrs.ReasonGameNotFound)
+	})
+}
+
+func TestUpsertGameByName(t *testing.T) {
+	repo := newTestRepository(t)
+	suffix := time.Now().UnixNano() % 1000000
+
+	name := fmt.Sprint("Upserted ", suffix)
+	deleted := createTestGame(t, repo, name)
+	if err := repo.DeleteGame(context.Background(), deleted.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	live := createTestGame(t, repo, name)
+
+	convey.Convey("Ingested games should update the live game of their name", t, func() {
+		sourced := entity.SourcedGame{
+			Source:     "feed",
+			ExternalID: fmt.Sprint("upserted-", suffix),
+			Game:       entity.GameProperties{Name: name, ImageURL: "https://example.com/new.png", YearReleased: 2021},
+		}
+		result, err := repo.UpsertGame(context.Background(), sourced, entity.SourcePriority{})
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(result, convey.ShouldEqual, entity.IngestUpdated)
+
+		game, err := repo.GetGame(context.Background(), live.ID)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(game.ImageURL, convey.ShouldEqual, "https://example.com/new.png")
+
+		var link entity.GameExternalID
+		convey.So(repo.db.Where("source = ? and external_id = ?", "feed", sourced.ExternalID).First(&link).Error, convey.ShouldBeNil)
+		convey.So(link.GameID, convey.ShouldEqual, live.ID)
+	})
+}
+
+func TestGameListsOfProfiles(t *testing.T) {
+	repo := newTestRepository(t)
+	suffix := time.Now().UnixNano() % 1000000
+	ctx := context.Background()
+
+	games := []*entity.GameProperties{
+		createTestGame(t, repo, fmt.Sprint("Lists Test A ", suffix)),
+		createTestGame(t, repo, fmt.Sprint("Lists Test B ", suffix)),
+		createTestGame(t, repo, fmt.Sprint("Lists Test C ", suffix)),
+	}
+	first := fmt.Sprint("lists", suffix)
+	second := fmt.Sprint("lists2", suffix)
+	firstID := createTestProfile(t, repo, first)
+	secondID := createTestProfile(t, repo, second)
+	emptyID := createTestProfile(t, repo, fmt.Sprint("lists3", suffix))
+	for _, game := range games {
+		if err := repo.ListGame(ctx, first, game.ID, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := repo.ListGame(ctx, second, games[2].ID, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	convey.Convey("Every list should be paged by itself", t, func() {
+		lists, err := repo.GetGameListsOfProfiles(ctx, []uint64{firstID, secondID, emptyID}, entity.Cursor{}, 1)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(lists, convey.ShouldHaveLength, 2)
+
+		// One more entry tells there is a next page
+		convey.So(lists[firstID], convey.ShouldHaveLength, 2)
+		convey.So(lists[firstID][0].ID, convey.ShouldEqual, games[0].ID)
+		convey.So(lists[firstID][1].ID, convey.ShouldEqual, games[1].ID)
+		convey.So(lists[secondID], convey.ShouldHaveLength, 1)
+		convey.So(lists[secondID][0].Name, convey.ShouldEqual, games[2].Name)
+		convey.So(lists[secondID][0].ListTypeID, convey.ShouldEqual, 2)
+	})
+
+	convey.Convey("The cursor should apply to every list", t, func() {
+		lists, err := repo.GetGameListsOfProfiles(ctx, []uint64{firstID, secondID}, entity.Cursor{Key: games[1].Name, ID: games[1].ID}, 0)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(lists[firstID], convey.ShouldHaveLength, 1)
+		convey.So(lists[firstID][0].ID, convey.ShouldEqual, games[2].ID)
+		convey.So(lists[secondID], convey.ShouldHaveLength, 1)
+	})
+}
+
+func TestMergeGames(t *testing.T) {
+	repo := newTestRepository(t)
+	suffix := time.Now().UnixNano() % 1000000
+	ctx := context.Background()
+
+	survivor := createTestGame(t, repo, fmt.Sprint("Merge Survivor ", suffix))
+	duplicate := createTestGame(t, repo, fmt.Sprint("Merge Duplicate ", suffix))
+	nickname := fmt.Sprint("merge", suffix)
+	userID := createTestProfile(t, repo, nickname)
+	other := fmt.Sprint("merge-other", suffix)
+	otherID := createTestProfile(t, repo, other)
+
+	for _, entry := range []struct {
+		nickname string
+		gameID   uint64
+		listType uint64
+	}{
+		{nickname, duplicate.ID, 2},
+		{other, survivor.ID, 1},
+		{other, duplicate.ID, 3},
+	} {
+		if err := repo.ListGame(ctx, entry.nickname, entry.gameID, entry.listType); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := repo.MergeGames(ctx, survivor.ID, duplicate.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	convey.Convey("Merged games should resolve to the survivor", t, func() {
+		id, err := resolveGameID(repo.db, duplicate.ID)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(id, convey.ShouldEqual, survivor.ID)
+
+		id, err = resolveGameID(repo.db, survivor.ID)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(id, convey.ShouldEqual, survivor.ID)
+
+		games, err := repo.GetGamesByIDs(ctx, []uint64{duplicate.ID, survivor.ID})
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(games[duplicate.ID].ID, convey.ShouldEqual, survivor.ID)
+		convey.So(games[survivor.ID].ID, convey.ShouldEqual, survivor.ID)
+	})
+
+	convey.Convey("List entries should move to the survivor unless it's listed already", t, func() {
+		convey.So(listTypeOf(t, repo, userID, survivor.ID), convey.ShouldEqual, 2)
+		convey.So(listTypeOf(t, repo, otherID, survivor.ID), convey.ShouldEqual, 1)
+		convey.So(listTypeOf(t, repo, userID, duplicate.ID), convey.ShouldEqual, 0)
+		convey.So(listTypeOf(t, repo, otherID, duplicate.ID), convey.ShouldEqual, 0)
+
+		// Listing the merged game lists the survivor
+		convey.So(repo.ListGame(ctx, nickname, duplicate.ID, 3), convey.ShouldBeNil)
+		convey.So(listTypeOf(t, repo, userID, survivor.ID), convey.ShouldEqual, 3)
+	})
+
+	convey.Convey("The duplicate's name should be free to take", t, func() {
+		game := &entity.GameProperties{Name: duplicate.Name, ImageURL: duplicate.ImageURL, YearReleased: 2021}
+		convey.So(repo.SaveGame(ctx, game), convey.ShouldBeNil)
+		deleteTestGame(repo, game.ID)
+	})
+
+	convey.Convey("The duplicate shouldn't be merged or restored again", t, func() {
+		err := repo.MergeGames(ctx, survivor.ID, duplicate.ID).(*utilErrs.Error)
+		convey.So(err.Reason(), convey.ShouldEqual, utilErrs.ReasonGameNotFound)
+
+		err = repo.RestoreGame(ctx, duplicate.ID).(*utilErrs.Error)
+		convey.So(err.Reason(), convey.ShouldEqual, utilErrs.ReasonGameMerged)
+	})
+}
+
+func TestCatalogDeletion(t *testing.T) {
+	repo := newTestRepository(t)
+	suffix := time.Now().UnixNano() % 1000000
+	ctx := context.Background()
+
+	var genres [2]entity.Genre
+	for i := range genres {
+		genres[i].Name = fmt.Sprintf("Genre %c %d", 'A'+i, suffix)
+		if err := repo.SaveGenre(ctx, &genres[i]); err != nil {
+			t.Fatal(err)
+		}
+		id := genres[i].ID
+		t.Cleanup(func() {
+			repo.db.Exec("delete from game_genres where genre_id = ?", id)
+			repo.db.Unscoped().Delete(&entity.Genre{}, id)
+		})
+	}
+
+	// Both games have the first genre, only one of them has the second one as well
+	both := createTestGame(t, repo, fmt.Sprint("Two Genres ", suffix))
+	one := createTestGame(t, repo, fmt.Sprint("One Genre ", suffix))
+	for _, row := range [][2]uint64{{both.ID, genres[0].ID}, {both.ID, genres[1].ID}, {one.ID, genres[0].ID}} {
+		if res := repo.db.Exec("insert into game_genres (game_properties_id, genre_id) values (?, ?)", row[0], row[1]); res.Error != nil {
+			t.Fatal(res.Error)
+		}
+	}
+
+	nickname := fmt.Sprint("catalog", suffix)
+	createTestProfile(t, repo, nickname)
+	if err := repo.ListGame(ctx, nickname, one.ID, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	convey.Convey("Items in use shouldn't be deleted without reassigning", t, func() {
+		err := repo.DeleteGenre(ctx, genres[0].ID, 0).(*utilErrs.Error)
+		convey.So(err.Code(), convey.ShouldEqual, utilErrs.Conflict)
+		convey.So(err.Reason(), convey.ShouldEqual, utilErrs.ReasonItemInUse)
+
+		err = repo.DeleteGame(ctx, one.ID, 0).(*utilErrs.Error)
+		convey.So(err.Reason(), convey.ShouldEqual, utilErrs.ReasonItemInUse)
+
+		err = repo.DeleteGenre(ctx, genres[0].ID, genres[0].ID).(*utilErrs.Error)
+		convey.So(err.Code(), convey.ShouldEqual, utilErrs.BadInput)
+	})
+
+	convey.Convey("Reassigning should keep a single reference of owners which have both items", t, func() {
+		convey.So(repo.DeleteGenre(ctx, genres[0].ID, genres[1].ID), convey.ShouldBeNil)
+
+		gameGenres, err := repo.GetGenresOfGames(ctx, []uint64{both.ID, one.ID})
+		convey.So(err, convey.ShouldBeNil)
+		for _, id := range []uint64{both.ID, one.ID} {
+			convey.So(gameGenres[id], convey.ShouldHaveLength, 1)
+			convey.So(gameGenres[id][0].ID, convey.ShouldEqual, genres[1].ID)
+		}
+	})
+
+	convey.Convey("Deleted items should be restored once", t, func() {
+		convey.So(repo.RestoreGenre(ctx, genres[0].ID), convey.ShouldBeNil)
+		err := repo.RestoreGenre(ctx, genres[0].ID).(*utilErrs.Error)
+		convey.So(err.Reason(), convey.ShouldEqual, utilErrs.ReasonItemNotFound)
+
+		// The restored genre isn't used anymore
+		convey.So(repo.DeleteGenre(ctx, genres[0].ID, 0), convey.ShouldBeNil)
+		convey.So(repo.RestoreGenre(ctx, genres[0].ID), convey.ShouldBeNil)
+	})
+
+	convey.Convey("Restoring a game whose name was taken should conflict", t, func() {
+		convey.So(repo.DeleteGame(ctx, both.ID, 0), convey.ShouldBeNil)
+		taken := createTestGame(t, repo, both.Name)
+
+		err := repo.RestoreGame(ctx, both.ID).(*utilErrs.Error)
+		convey.So(err.Reason(), convey.ShouldEqual, utilErrs.ReasonAlreadyExists)
+
+		deleteTestGame(repo, taken.ID)
+		convey.So(repo.RestoreGame(ctx, both.ID), convey.ShouldBeNil)
+	})
+}
+
+func TestWithTx(t *testing.T) {
+	repo := newTestRepository(t)
+	suffix := time.Now().UnixNano() % 1000000
+
+	nickname := fmt.Sprint("tx", suffix)
+	token := fmt.Sprint("tx-token-", suffix)
+	createTestProfile(t, repo, nickname)
+
+	convey.Convey("Calls in a failed transaction should be rolled back", t, func() {
+		failure := errors.New("failed")
+		err := repo.WithTx(context.Background(), func(tx GamelistRepository) error {
+			if err := tx.SaveRefreshToken(context.Background(), nickname, token); err != nil {
+				return err
+			}
+			// Transactions of calls are savepoints in the outer one
+			if err := tx.ListGames(context.Background(), nickname, nil); err != nil {
+				return err
+			}
+			if err := tx.FindRefreshToken(context.Background(), nickname, token); err != nil {
+				return err
+			}
+			return failure
+		})
+		convey.So(err, convey.ShouldEqual, failure)
+		convey.So(repo.FindRefreshToken(context.Background(), nickname, token), convey.ShouldNotBeNil)
+
+		convey.Convey("And committed otherwise", func() {
+			err := repo.WithTx(context.Background(), func(tx GamelistRepository) error {
+				return tx.SaveRefreshToken(context.Background(), nickname, token)
+			})
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(repo.FindRefreshToken(context.Background(), nickname, token), convey.ShouldBeNil)
+		})
+	})
+}
+
+func TestRefreshTokenDeletion(t *testing.T) {
+	repo := newTestRepository(t)
+	suffix := time.Now().UnixNano() % 1000000
+
+	nickname := fmt.Sprint("refresh", suffix)
+	token := fmt.Sprint("refresh-token-", suffix)
+	createTestProfile(t, repo, nickname)
+	if err := repo.SaveRefreshToken(context.Background(), nickname, token); err != nil {
+		t.Fatal(err)
+	}
+
+	convey.Convey("Tokens which are already deleted shouldn't be found", t, func() {
+		convey.So(repo.DeleteRefreshToken(context.Background(), token), convey.ShouldBeNil)
+
+		err := repo.DeleteRefreshToken(context.Background(), token)
+		convey.So(err, convey.ShouldNotBeNil)
+		convey.So(err.(*utilErrs.Error).Code(), convey.ShouldEqual, utilErrs.NotFound)
+
+		convey.Convey("And refreshes which lost the race should be rolled back", func() {
+			newToken := fmt.Sprint("refreshed-token-", suffix)
+			err := repo.WithTx(context.Background(), func(tx GamelistRepository) error {
+				if err := tx.SaveRefreshToken(context.Background(), nickname, newToken); err != nil {
+					return err
+				}
+				return tx.DeleteRefreshToken(context.Background(), token)
+			})
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(repo.FindRefreshToken(context.Background(), nickname, newToken), convey.ShouldNotBeNil)
+		})
+	})
+}
+
+func TestScrapeJobs(t *testing.T) {
+	repo := newTestRepository(t)
+	if err := repo.AbortStaleScrapeJobs(context.Background(), time.Now().Add(time.Hour), "aborted by tests"); err != nil {
+		t.Fatal(err)
+	}
+
+	convey.Convey("Only one scrape job should run across instances", t, func() {
+		now := time.Now()
+		job := entity.ScrapeJob{Trigger: entity.ScrapeTriggerManual, Status: entity.ScrapeJobRunning, StartedAt: now, HeartbeatAt: now}
+		convey.So(repo.CreateScrapeJob(context.Background(), &job), convey.ShouldBeNil)
+
+		other := entity.ScrapeJob{Trigger: entity.ScrapeTriggerManual, Status: entity.ScrapeJobRunning, StartedAt: now, HeartbeatAt: now}
+		err := repo.CreateScrapeJob(context.Background(), &other)
+		convey.So(err, convey.ShouldNotBeNil)
+		convey.So(err.(*utilErrs.Error).Code(), convey.ShouldEqual, utilErrs.Conflict)
+		convey.So(err.(*utilErrs.Error).Reason(), convey.ShouldEqual, utilErrs.ReasonScrapeJobRunning)
+
+		convey.Convey("And jobs which still beat shouldn't be aborted", func() {
+			convey.So(repo.BeatScrapeJob(context.Background(), job.ID), convey.ShouldBeNil)
+			convey.So(repo.AbortStaleScrapeJobs(context.Background(), time.Now().Add(-time.Minute), "stale"), convey.ShouldBeNil)
+
+			running, err := repo.GetScrapeJob(context.Background(), job.ID)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(running.Status, convey.ShouldEqual, entity.ScrapeJobRunning)
+
+			convey.So(repo.AbortStaleScrapeJobs(context.Background(), time.Now().Add(time.Minute), "stale"), convey.ShouldBeNil)
+			aborted, err := repo.GetScrapeJob(context.Background(), job.ID)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(aborted.Status, convey.ShouldEqual, entity.ScrapeJobFailed)
+			convey.So(aborted.Error, convey.ShouldEqual, "stale")
+			convey.So(repo.BeatScrapeJob(context.Background(), job.ID), convey.ShouldNotBeNil)
+		})
+	})
+}
+
+func TestQueryMetrics(t *testing.T) {
+	convey.Convey("Queries should be timed by operation and table", t, func() {
+		// Dry runs build queries without a database
+		db, err := gorm.Open(postgres.Open("host=localhost"), &gorm.Config{
+			DryRun:               true,
+			DisableAutomaticPing: true,
+			Logger:               logger.Discard,
+		})
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(metrics.InstrumentGORM(db, "metrics_test"), convey.ShouldBeNil)
+
+		samples := func() uint64 {
+			metric := &dto.Metric{}
+			metrics.DBQueryDuration.WithLabelValues("query", "genre").(prometheus.Histogram).Write(metric) //nolint:errcheck
+			return metric.GetHistogram().GetSampleCount()
+		}
+
+		before := samples()
+		db.Find(&[]entity.Genre{})
+		convey.So(samples()-before, convey.ShouldEqual, 1)
+	})
+}
+
+func TestStatementSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	convey.Convey("Statements should be children of spans of their contexts", t, func() {
+		db, err := gorm.Open(postgres.Open("host=localhost"), &gorm.Config{
+			DryRun:               true,
+			DisableAutomaticPing: true,
+			Logger:               logger.Discard,
+		})
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(db.Use(otelgorm.NewPlugin(otelgorm.WithoutQueryVariables(), otelgorm.WithoutMetrics())), convey.ShouldBeNil)
+
+		ctx, parent := otel.Tracer("test").Start(context.Background(), "request")
+		db.WithContext(ctx).Where("name = ?", "RPG").Find(&[]entity.Genre{})
+		parent.End()
+
+		spans := recorder.Ended()
+		convey.So(spans, convey.ShouldHaveLength, 2)
+		convey.So(spans[0].Name(), convey.ShouldEqual, "gorm.Query")
+		convey.So(spans[0].Parent().SpanID(), convey.ShouldEqual, parent.SpanContext().SpanID())
+		// Values of queries are masked
+		convey.So(spans[0].Attributes(), convey.ShouldContain, semconv.DBStatementKey.String(`SELECT * FROM "genre" WHERE name = '?' AND "genre"."deleted_at" IS NULL`))
+		convey.So(spans[0].Attributes(), convey.ShouldContain, semconv.DBSQLTableKey.String("genre"))
+	})
+}
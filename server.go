@@ -1,68 +1,91 @@
 package main
 
 import (
-	"log"
-	"strings"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
 
-	"github.com/br3w0r/gamelist-backend/helpers"
-	"github.com/br3w0r/gamelist-backend/repository"
+	"github.com/br3w0r/gamelist-backend/config"
 	"github.com/br3w0r/gamelist-backend/server"
+	"github.com/br3w0r/gamelist-backend/util/logger"
+	"github.com/br3w0r/gamelist-backend/util/tracing"
 )
 
-var (
-	PORT                 string = helpers.GetEnvOrDefault("PORT", "8080")
-	PRODUCTION_MODE      string = helpers.GetEnvOrDefault("PRODUCTION_MODE", "0")
-	SERVE_STATIC         string = helpers.GetEnvOrDefault("SERVE_STATIC", "1")
-	FORCE_SCRAPE         string = helpers.GetEnvOrDefault("FORCE_SCRAPE", "0")
-	STATIC_DIR           string = helpers.GetEnvOrDefault("STATIC_FOLDER", "../gamelist-frontend/gamelist/dist")
-	DATABASE_DIST        string = helpers.GetEnvOrDefault("DATABASE_DIST", "./gamelist.db")
-	SCRAPER_GRPC_ADDRESS string = helpers.GetEnvOrDefault("SCRAPER_GRPC_ADDRESS", "localhost")
-	STRESS_TEST          string = helpers.GetEnvOrDefault("STRESS_TEST", "0")
-	STRESS_TEST_OPTIONS  string = helpers.GetEnvOrDefault("STRESS_TEST_OPTIONS", "user_creation,get_game=75,get_all_games,get_user_games")
-	DB_HOST              string = helpers.GetEnvOrDefault("DB_HOST", "localhost")
-	DB_PORT              string = helpers.GetEnvOrDefault("DB_PORT", "5432")
-	DB_USER              string = helpers.GetEnvOrDefault("DB_USER", "postgres")
-	DB_NAME              string = helpers.GetEnvOrDefault("DB_NAME", "gamelist")
-	DB_PASSWORD          string = helpers.GetEnvOrDefault("DB_PASSWORD", "pgpass")
-	DB_SSL               string = helpers.GetEnvOrDefault("DB_SSL", "0")
-	DB_TIMEZONE          string = helpers.GetEnvOrDefault("DB_TIMEZONE", "UTC")
-)
+// failed logs the error and returns it
+func failed(msg string, err error) error {
+	slog.Error(msg, "error", err)
+	return err
+}
 
 func main() {
-	var scraperAsync bool
-	if STRESS_TEST == "1" {
-		scraperAsync = true
-	} else {
-		scraperAsync = false
+	conf, err := config.Load(os.Args[1:], os.LookupEnv, os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return
 	}
-	options := server.ServerOptions{
-		Production:         PRODUCTION_MODE == "1",
-		ServeStatic:        SERVE_STATIC == "1",
-		ForceScrape:        FORCE_SCRAPE == "1",
-		StaticDir:          STATIC_DIR,
-		DatabaseDist:       DATABASE_DIST,
-		ScraperGRPCAddress: SCRAPER_GRPC_ADDRESS,
-		ScraperAsync:       scraperAsync,
-		StressTest:         STRESS_TEST == "1",
-		StressTestOptions:  strings.Split(STRESS_TEST_OPTIONS, ","),
-		SilentMode:         false,
-		DBConfig: &repository.DBConfig{
-			Host:     DB_HOST,
-			Port:     DB_PORT,
-			User:     DB_USER,
-			DBName:   DB_NAME,
-			Password: DB_PASSWORD,
-			SSL:      DB_SSL == "1",
-			TimeZone: DB_TIMEZONE,
-		},
+	if err == nil {
+		err = conf.Validate()
+	}
+	if err != nil {
+		// Each problem is on its own line
+		fmt.Fprintf(os.Stderr, "wrong config:\n%s\n", err)
+		os.Exit(2)
 	}
 
-	server := server.NewServer(options)
-
-	if !options.StressTest {
-		err := server.Run(":" + PORT)
-		if err != nil {
-			log.Fatalf("failed to start server: %s", err)
+	if conf.PrintConfig {
+		if err := conf.Print(os.Stdout); err != nil {
+			slog.Error("failed to print config", "error", err)
+			os.Exit(1)
 		}
+		return
+	}
+
+	// Errors are logged by run, which returns once spans are flushed
+	if err := run(conf); err != nil {
+		os.Exit(1)
 	}
 }
+
+// run runs the server until it's stopped by a signal. Spans of failed starts are
+// flushed too, since they're the ones worth seeing.
+func run(conf *config.Config) error {
+	loggerConfig, err := conf.LoggerConfig()
+	if err != nil {
+		return failed("wrong log config", err)
+	}
+	logger.Setup(loggerConfig)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), conf.TracingConfig())
+	if err != nil {
+		return failed("failed to set up tracing", err)
+	}
+	defer shutdownTracing(context.Background()) //nolint:errcheck
+
+	options, err := conf.ServerOptions()
+	if err != nil {
+		return failed("wrong server config", err)
+	}
+
+	app, err := server.NewServer(options)
+	if err != nil {
+		return failed("failed to create server", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+	go func() {
+		// A second signal stops the process right away
+		<-ctx.Done()
+		stop()
+	}()
+
+	if err := app.Run(ctx); err != nil {
+		return failed("server stopped with an error", err)
+	}
+
+	return nil
+}
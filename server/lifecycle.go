@@ -0,0 +1,253 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/br3w0r/gamelist-backend/controller"
+	"github.com/br3w0r/gamelist-backend/entity"
+	"github.com/br3w0r/gamelist-backend/repository"
+	"github.com/br3w0r/gamelist-backend/service"
+	test "github.com/br3w0r/gamelist-backend/test/stress"
+	utilLogger "github.com/br3w0r/gamelist-backend/util/logger"
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+)
+
+// Server is the HTTP server of the API with the admin gRPC server, the server of metrics
+// and background jobs. NewServer builds it, Start and Stop run it, Run does both.
+type Server struct {
+	options ServerOptions
+	engine  *gin.Engine
+
+	repo             repository.GamelistRepository
+	scraper          service.ScraperClient
+	eventBus         service.EventBus
+	healthService    service.HealthService
+	scrapeJobService service.ScrapeJobService
+	accountService   service.AccountService
+
+	http     *http.Server
+	listener net.Listener
+	admin    *grpc.Server
+	metrics  *http.Server
+	// errs has errors of servers which stopped on their own
+	errs chan error
+}
+
+// Engine handles requests of the API without listening, e.g. in tests
+func (s *Server) Engine() *gin.Engine {
+	return s.engine
+}
+
+// Addr is the address the HTTP server listens on after Start
+func (s *Server) Addr() net.Addr {
+	if s.listener == nil {
+		return nil
+	}
+
+	return s.listener.Addr()
+}
+
+// Run starts the server and stops it when ctx is done, e.g. on a signal. The server
+// reports that it isn't ready for DrainDelay first, so load balancers stop sending
+// requests to it, and then has ShutdownTimeout to stop.
+func (s *Server) Run(ctx context.Context) error {
+	// First version. Should be remade
+	if s.options.StressTest {
+		s.startJobs()
+		test.RunStress(s.repo, s.options.StressTestOptions)
+		return s.stop()
+	}
+
+	if err := s.Start(); err != nil {
+		return errors.Join(err, s.stop())
+	}
+	serverLog.Info("serving", "address", s.Addr().String())
+
+	var err error
+	select {
+	case <-ctx.Done():
+		serverLog.Info("draining", "delay", s.options.DrainDelay)
+		s.healthService.Drain()
+		time.Sleep(s.options.DrainDelay)
+	case err = <-s.errs:
+	}
+
+	serverLog.Info("stopping", "timeout", s.options.ShutdownTimeout)
+	return errors.Join(err, s.stop())
+}
+
+// Start starts background jobs, listens on addresses of the servers and serves them in
+// background. A scrape at boot finishes before it returns unless it's async.
+func (s *Server) Start() error {
+	s.startJobs()
+
+	listener, err := net.Listen("tcp", s.options.Address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.options.Address, err)
+	}
+
+	var adminListener net.Listener
+	if s.admin != nil {
+		adminListener, err = net.Listen("tcp", s.options.AdminGRPCAddress)
+		if err != nil {
+			listener.Close()
+			return fmt.Errorf("failed to listen for admin gRPC server on %s: %w", s.options.AdminGRPCAddress, err)
+		}
+	}
+
+	var metricsListener net.Listener
+	if s.metrics != nil {
+		metricsListener, err = net.Listen("tcp", s.metrics.Addr)
+		if err != nil {
+			listener.Close()
+			if adminListener != nil {
+				adminListener.Close()
+			}
+			return fmt.Errorf("failed to listen for metrics on %s: %w", s.metrics.Addr, err)
+		}
+	}
+
+	s.listener = listener
+	s.http = &http.Server{
+		Handler:           controller.KeepResponseWriter(s.engine),
+		ReadHeaderTimeout: s.options.ReadTimeout,
+		ReadTimeout:       s.options.ReadTimeout,
+		WriteTimeout:      s.options.WriteTimeout,
+		IdleTimeout:       s.options.IdleTimeout,
+		ErrorLog:          slog.NewLogLogger(utilLogger.For(utilLogger.SubsystemHTTP).Handler(), slog.LevelWarn),
+	}
+	// Streams of events would keep their connections busy until the deadline
+	s.http.RegisterOnShutdown(s.eventBus.Close)
+
+	go s.serve("HTTP", func() error { return s.http.Serve(listener) })
+	if s.admin != nil {
+		go s.serve("admin gRPC", func() error { return s.admin.Serve(adminListener) })
+	}
+	if s.metrics != nil {
+		go s.serve("metrics", func() error { return s.metrics.Serve(metricsListener) })
+	}
+
+	return nil
+}
+
+// Stop makes the server unready, waits for in-flight requests and cancels background
+// jobs until the deadline of ctx. Then it closes the rest of connections and the database.
+func (s *Server) Stop(ctx context.Context) error {
+	s.healthService.Drain()
+
+	var errs []error
+	if s.http != nil {
+		if err := s.http.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to shut down HTTP server: %w", err))
+			s.http.Close()
+		}
+	}
+
+	if s.admin != nil {
+		if err := waitFor(ctx, s.admin.GracefulStop); err != nil {
+			errs = append(errs, fmt.Errorf("failed to shut down admin gRPC server: %w", err))
+			s.admin.Stop()
+		}
+	}
+
+	// Running scrape jobs are cancelled and save their status
+	if err := waitFor(ctx, s.scrapeJobService.Stop, s.accountService.Stop); err != nil {
+		errs = append(errs, fmt.Errorf("failed to stop background jobs: %w", err))
+	}
+
+	if s.metrics != nil {
+		if err := s.metrics.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to shut down metrics server: %w", err))
+			s.metrics.Close()
+		}
+	}
+
+	s.eventBus.Close()
+	if s.scraper != nil {
+		if err := s.scraper.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close scraper client: %w", err))
+		}
+	}
+	if err := s.repo.Close(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// stop stops the server with ShutdownTimeout, zero waits without a deadline
+func (s *Server) stop() error {
+	ctx := context.Background()
+	if s.options.ShutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.options.ShutdownTimeout)
+		defer cancel()
+	}
+
+	return s.Stop(ctx)
+}
+
+func (s *Server) serve(name string, serve func() error) {
+	err := serve()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		s.errs <- fmt.Errorf("%s server stopped: %w", name, err)
+	}
+}
+
+func (s *Server) startJobs() {
+	// Jobs of other instances which are still running are left alone
+	if err := s.scrapeJobService.AbortStale(context.Background()); err != nil {
+		serverLog.Error("failed to abort stale scrape jobs", "error", err)
+	}
+
+	if s.options.ForceScrape {
+		serverLog.Info("force scraping")
+
+		var err error
+		if s.options.ScraperAsync {
+			_, err = s.scrapeJobService.Start(context.Background(), entity.ScrapeTriggerBoot)
+		} else {
+			_, err = s.scrapeJobService.Run(context.Background(), entity.ScrapeTriggerBoot)
+		}
+		if err != nil {
+			serverLog.Error("failed to scrape games", "error", err, "cause", errors.Unwrap(err))
+		}
+	}
+
+	if s.options.ScrapeSchedule != "" {
+		if err := s.scrapeJobService.Schedule(s.options.ScrapeSchedule); err != nil {
+			serverLog.Error("failed to schedule scrape jobs", "error", err, "cause", errors.Unwrap(err))
+		}
+	}
+
+	if s.options.PurgeSchedule != "" {
+		if err := s.accountService.Schedule(s.options.PurgeSchedule); err != nil {
+			serverLog.Error("failed to schedule account purges", "error", err, "cause", errors.Unwrap(err))
+		}
+	}
+}
+
+// waitFor runs the functions one by one and waits for them until ctx is done
+func waitFor(ctx context.Context, fns ...func()) error {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for _, fn := range fns {
+			fn()
+		}
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -1,69 +1,205 @@
 package server
 
 import (
-	"log"
+	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/br3w0r/gamelist-backend/controller"
+	"github.com/br3w0r/gamelist-backend/entity"
+	"github.com/br3w0r/gamelist-backend/graph"
+	"github.com/br3w0r/gamelist-backend/migrations"
+	pb "github.com/br3w0r/gamelist-backend/proto"
 	"github.com/br3w0r/gamelist-backend/repository"
 	"github.com/br3w0r/gamelist-backend/service"
-	test "github.com/br3w0r/gamelist-backend/test/stress"
+	utilLogger "github.com/br3w0r/gamelist-backend/util/logger"
 	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
 	"gorm.io/gorm/logger"
 )
 
+const (
+	API_V0 = "/api/v0"
+	API_V1 = "/api/v1"
+
+	EVENT_BACKEND_LOCAL    = "local"
+	EVENT_BACKEND_POSTGRES = "postgres"
+
+	// GORM_SLOW_THRESHOLD is the duration after which queries are logged as slow
+	GORM_SLOW_THRESHOLD = 200 * time.Millisecond
+)
+
+var (
+	// v0 is deprecated in favor of v1
+	API_V0_DEPRECATED_AT = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+
+	serverLog = utilLogger.For(utilLogger.SubsystemServer)
+)
+
 type ServerOptions struct {
-	Production         bool
-	ServeStatic        bool
-	ForceScrape        bool
-	StaticDir          string
-	DatabaseDist       string
-	ScraperGRPCAddress string
-	ScraperAsync       bool
-	StressTest         bool
-	StressTestOptions  []string
-	SilentMode         bool
-	DBConfig           *repository.DBConfig
+	Production     bool
+	ServeStatic    bool
+	ForceScrape    bool
+	StaticDir      string
+	Scraper        service.ScraperConfig
+	GameSources    []service.GameSourceConfig
+	SourcePriority entity.SourcePriority
+	PageLimits     entity.PageLimits
+	// GraphQLMaxComplexity limits costs of GraphQL queries, see graph.Complexity
+	GraphQLMaxComplexity int
+	// EventBackend fans events out between instances, EVENT_BACKEND_LOCAL or EVENT_BACKEND_POSTGRES
+	EventBackend     string
+	ScraperAsync     bool
+	ScrapeSchedule   string
+	DeletionGrace    time.Duration
+	PurgeSchedule    string
+	APIv0Sunset      time.Time
+	AdminGRPCAddress string
+	AdminTokens      []string
+	// MetricsAddress serves Prometheus metrics on /metrics, empty to disable them
+	MetricsAddress string
+	// MetricsToken is the bearer token of scrapes of metrics, empty to serve them without it
+	MetricsToken string
+	// ReadyCheckScraper makes the server unready while the scraper isn't reachable
+	ReadyCheckScraper bool
+	// DrainDelay is how long the server reports it isn't ready before it stops on SIGTERM
+	DrainDelay time.Duration
+	// Address of the HTTP server, e.g. ":8080"
+	Address      string
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+	// ShutdownTimeout limits how long in-flight requests and jobs are waited for on stop
+	ShutdownTimeout   time.Duration
+	StressTest        bool
+	StressTestOptions []string
+	SilentMode        bool
+	DBConfig          *repository.DBConfig
+	// DBQueryTimeout limits each call of the repository, e.g. the queries of a request
+	// to the database, 0 for no limit
+	DBQueryTimeout time.Duration
 }
 
-func NewServer(options ServerOptions) *gin.Engine {
-	var (
-		// DB dialector init
-		dialector = repository.NewDBDialector(options.DBConfig)
+// newGameSources returns the scraper client too if a source uses it
+func newGameSources(options ServerOptions) ([]service.GameSource, service.ScraperClient) {
+	var scraper service.ScraperClient
+	for _, conf := range options.GameSources {
+		if conf.Kind == service.GameSourceScraper {
+			var err error
+			scraper, err = service.NewScraperClient(options.Scraper)
+			if err != nil {
+				serverLog.Error("failed to create scraper client", "error", err, "cause", errors.Unwrap(err))
+			}
+			break
+		}
+	}
 
-		// Repos
-		gamelistRepository repository.GamelistRepository = repository.NewGamelistRepository(
-			options.DatabaseDist, dialector,
-			logger.Config{
-				Colorful: true,
-				IgnoreRecordNotFoundError: true,
-				LogLevel: logger.Error,
-			},
-		)
+	var sources []service.GameSource
+	for _, conf := range options.GameSources {
+		source, err := service.NewGameSource(conf, scraper)
+		if err != nil {
+			serverLog.Error("failed to create game source", "source", conf.Name, "error", err, "cause", errors.Unwrap(err))
+			continue
+		}
+		sources = append(sources, source)
+	}
 
-		// Services
-		gamelistService service.GameListService = service.NewGameListService(gamelistRepository, options.ScraperGRPCAddress)
-		jwtService      service.JWTService      = service.NewJWTService(gamelistRepository)
+	return sources, scraper
+}
 
-		// Controllers
-		gamelistController controller.GameListController = controller.NewGameListController(gamelistService, jwtService)
+// newAdminServer is the GamelistAdmin gRPC server, nil if it's disabled
+func newAdminServer(options ServerOptions, gamelistService service.GameListService, scrapeJobService service.ScrapeJobService) *grpc.Server {
+	if options.AdminGRPCAddress == "" {
+		return nil
+	}
+	if len(options.AdminTokens) == 0 {
+		serverLog.Warn("no admin tokens are set, admin gRPC server is disabled")
+		return nil
+	}
+
+	unary, stream := controller.AdminAuthInterceptors(options.AdminTokens)
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(unary),
+		grpc.StreamInterceptor(stream),
 	)
+	pb.RegisterGamelistAdminServer(grpcServer, controller.NewGamelistAdminController(gamelistService, scrapeJobService))
 
-	if options.ForceScrape {
-		log.Println("Force scraping.")
+	return grpcServer
+}
+
+// NewServer opens the database and builds the server, see Server.Run to serve it. The
+// database may be down, the server isn't ready until it's up.
+func NewServer(options ServerOptions) (*Server, error) {
+	gamelistRepository, err := repository.NewGamelistRepository(
+		repository.NewDBDialector(options.DBConfig),
+		logger.Config{
+			IgnoreRecordNotFoundError: true,
+			SlowThreshold:             GORM_SLOW_THRESHOLD,
+			// Queries are debug lines, so the level of the gorm subsystem filters them
+			LogLevel: logger.Info,
+		},
+		options.DBQueryTimeout,
+	)
+	if err != nil {
+		return nil, err
+	}
 
-		if options.ScraperAsync {
-			go gamelistService.ScrapeGames()
-		} else {
-			gamelistService.ScrapeGames()
+	if options.MetricsAddress != "" {
+		if err := repository.Instrument(gamelistRepository, options.DBConfig.DBName); err != nil {
+			serverLog.Error("failed to instrument database", "error", err)
 		}
 	}
 
-	// First version. Should be remade
-	if options.StressTest {
-		test.RunStress(gamelistRepository, options.StressTestOptions)
-		return nil
+	var eventBackend repository.EventBackend
+	switch options.EventBackend {
+	case EVENT_BACKEND_POSTGRES:
+		eventBackend = repository.NewPgEventBackend(options.DBConfig, repository.EVENTS_CHANNEL)
+	case EVENT_BACKEND_LOCAL, "":
+		eventBackend = repository.NewLocalEventBackend()
+	default:
+		gamelistRepository.Close() //nolint:errcheck
+		return nil, fmt.Errorf("unknown event backend %q", options.EventBackend)
+	}
+
+	return newServer(options, gamelistRepository, eventBackend)
+}
+
+func newServer(options ServerOptions, gamelistRepository repository.GamelistRepository, eventBackend repository.EventBackend) (*Server, error) {
+	schemaVersion, err := migrations.Latest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	sources, scraper := newGameSources(options)
+	healthConfig := service.HealthConfig{SchemaVersion: schemaVersion}
+	if options.ReadyCheckScraper {
+		healthConfig.Scraper = scraper
+	}
+
+	var (
+		// Services
+		eventBus        service.EventBus        = service.NewEventBus(eventBackend)
+		gamelistService service.GameListService = service.NewGameListService(
+			gamelistRepository, sources, options.SourcePriority, options.PageLimits, eventBus,
+		)
+		healthService    service.HealthService    = service.NewHealthService(gamelistRepository, healthConfig)
+		jwtService       service.JWTService       = service.NewJWTService(gamelistRepository)
+		scrapeJobService service.ScrapeJobService = service.NewScrapeJobService(gamelistRepository, gamelistService)
+		accountService   service.AccountService   = service.NewAccountService(gamelistRepository, gamelistService, options.DeletionGrace)
+
+		// Controllers
+		gamelistController controller.GameListController = controller.NewGameListController(gamelistService, jwtService, accountService)
+		eventsController   controller.EventsController   = controller.NewEventsController(gamelistService, eventBus)
+		healthController   controller.HealthController   = controller.NewHealthController(healthService)
+	)
+
+	graphExecutor, err := graph.NewExecutor(gamelistService, options.PageLimits, options.GraphQLMaxComplexity)
+	if err != nil {
+		eventBus.Close()
+		return nil, fmt.Errorf("failed to create GraphQL executor: %w", err)
 	}
+	graphqlController := controller.NewGraphQLController(graphExecutor)
 
 	if options.Production {
 		gin.SetMode(gin.ReleaseMode)
@@ -71,10 +207,18 @@ func NewServer(options ServerOptions) *gin.Engine {
 
 	server := gin.New()
 
-	server.Use(gin.Recovery())
+	server.Use(controller.RequestID)
+	server.Use(controller.Tracing)
+	if options.MetricsAddress != "" {
+		server.Use(controller.Metrics)
+	}
 	if !options.SilentMode {
-		server.Use(gin.Logger())
+		server.Use(controller.AccessLog)
 	}
+	server.Use(controller.Recovery)
+
+	server.GET(controller.HEALTHZ_PATH, healthController.Healthz)
+	server.GET(controller.READYZ_PATH, healthController.Readyz)
 
 	if options.ServeStatic {
 		server.Static("/css", options.StaticDir+"/css")
@@ -87,7 +231,9 @@ func NewServer(options ServerOptions) *gin.Engine {
 		})
 	}
 
-	apiRoutes := server.Group("/api/v0")
+	// Deprecated: v0 is kept for compatibility until the sunset
+	// v0 predates pages, its lists send the first page alone
+	apiRoutes := server.Group(API_V0, controller.Deprecated(API_V0_DEPRECATED_AT, options.APIv0Sunset, API_V1), controller.BareLists)
 	{
 		apiRoutes.POST("/games/all",
 			gamelistController.Authorized,
@@ -104,6 +250,16 @@ func NewServer(options ServerOptions) *gin.Engine {
 			gamelistController.GetMyGameList,
 		)
 
+		apiRoutes.POST("/my-games/import",
+			gamelistController.Authorized,
+			gamelistController.ImportGameList,
+		)
+
+		apiRoutes.GET("/my-games/export",
+			gamelistController.Authorized,
+			gamelistController.ExportGameList,
+		)
+
 		apiRoutes.POST("/games/search",
 			gamelistController.Authorized,
 			gamelistController.SearchGames,
@@ -124,25 +280,165 @@ func NewServer(options ServerOptions) *gin.Engine {
 			gamelistController.DeleteAllRefreshTokens,
 		)
 
-		// This will be replaced with gRPC admin shell
-		if options.Production {
-			apiRoutes.POST("/games", gamelistController.PostGame)
+		apiRoutes.GET("/account/export",
+			gamelistController.Authorized,
+			gamelistController.ExportAccount,
+		)
+
+		apiRoutes.POST("/account/delete",
+			gamelistController.Authorized,
+			gamelistController.DeleteAccount,
+		)
 
-			apiRoutes.POST("/list-types", gamelistController.PostListType)
-			apiRoutes.GET("/list-types", gamelistController.GetAllListTypes)
+		apiRoutes.POST("/account/restore",
+			gamelistController.Authorized,
+			gamelistController.RestoreAccount,
+		)
+
+		apiRoutes.GET("/list-types",
+			gamelistController.Authorized,
+			gamelistController.GetAllListTypes,
+		)
 
-			apiRoutes.GET("/genres", gamelistController.GetAllGenres)
-			apiRoutes.POST("/genres", gamelistController.PostGenre)
+		apiRoutes.GET("/genres",
+			gamelistController.Authorized,
+			gamelistController.GetAllGenres,
+		)
 
-			apiRoutes.GET("/platforms", gamelistController.GetAllPlatforms)
-			apiRoutes.POST("/platforms", gamelistController.PostPlatform)
+		apiRoutes.GET("/platforms",
+			gamelistController.Authorized,
+			gamelistController.GetAllPlatforms,
+		)
 
-			apiRoutes.GET("/profiles", gamelistController.GetAllProfiles)
+		apiRoutes.GET("/profiles",
+			gamelistController.Authorized,
+			gamelistController.GetAllProfiles,
+		)
 
-			apiRoutes.GET("/social-types", gamelistController.GetAllSocialtypes)
-			apiRoutes.POST("/social-types", gamelistController.PostSocialType)
-		}
+		apiRoutes.GET("/social-types",
+			gamelistController.Authorized,
+			gamelistController.GetAllSocialtypes,
+		)
+	}
+
+	serveOpenAPI(server, apiRoutes, routeDocsV0, true)
+
+	apiV1 := server.Group(API_V1)
+	{
+		apiV1.GET("/games",
+			gamelistController.Authorized,
+			gamelistController.QueryGames,
+		)
+
+		apiV1.GET("/games/:id",
+			gamelistController.Authorized,
+			gamelistController.GetGame,
+		)
+
+		apiV1.GET("/my-games",
+			gamelistController.Authorized,
+			gamelistController.GetMyGameList,
+		)
+
+		apiV1.PUT("/my-games/:id",
+			gamelistController.Authorized,
+			gamelistController.PutListGame,
+		)
+
+		apiV1.DELETE("/my-games/:id",
+			gamelistController.Authorized,
+			gamelistController.UnlistGame,
+		)
+
+		apiV1.POST("/my-games/import",
+			gamelistController.Authorized,
+			gamelistController.ImportGameList,
+		)
+
+		apiV1.GET("/my-games/export",
+			gamelistController.Authorized,
+			gamelistController.ExportGameList,
+		)
+
+		apiV1.POST("/profiles", gamelistController.PostProfile)
+
+		apiV1.GET("/profiles",
+			gamelistController.Authorized,
+			gamelistController.GetAllProfiles,
+		)
+
+		apiV1.POST("/sessions", gamelistController.AcquireJWTPair)
+		apiV1.POST("/sessions/refresh", gamelistController.RefreshJWTPair)
+		apiV1.POST("/sessions/revoke", gamelistController.RevokeRefreshToken)
+		apiV1.DELETE("/sessions",
+			gamelistController.Authorized,
+			gamelistController.DeleteAllRefreshTokens,
+		)
+
+		apiV1.GET("/account/export",
+			gamelistController.Authorized,
+			gamelistController.ExportAccount,
+		)
+
+		apiV1.POST("/account/deletion",
+			gamelistController.Authorized,
+			gamelistController.DeleteAccount,
+		)
+
+		apiV1.DELETE("/account/deletion",
+			gamelistController.Authorized,
+			gamelistController.RestoreAccount,
+		)
+
+		apiV1.GET("/list-types",
+			gamelistController.Authorized,
+			gamelistController.GetAllListTypes,
+		)
+
+		apiV1.GET("/genres",
+			gamelistController.Authorized,
+			gamelistController.GetAllGenres,
+		)
+
+		apiV1.GET("/platforms",
+			gamelistController.Authorized,
+			gamelistController.GetAllPlatforms,
+		)
+
+		apiV1.GET("/social-types",
+			gamelistController.Authorized,
+			gamelistController.GetAllSocialtypes,
+		)
+
+		apiV1.GET("/events",
+			gamelistController.Authorized,
+			eventsController.Stream,
+		)
+
+		apiV1.GET("/profiles/:nickname/events",
+			gamelistController.Authorized,
+			eventsController.Stream,
+		)
+
+		apiV1.POST("/graphql",
+			gamelistController.Authorized,
+			graphqlController.Query,
+		)
 	}
 
-	return server
+	serveOpenAPI(server, apiV1, routeDocsV1, false)
+
+	return &Server{
+		options:          options,
+		engine:           server,
+		repo:             gamelistRepository,
+		scraper:          scraper,
+		eventBus:         eventBus,
+		healthService:    healthService,
+		scrapeJobService: scrapeJobService,
+		accountService:   accountService,
+		admin:            newAdminServer(options, gamelistService, scrapeJobService),
+		metrics:          newMetricsServer(options),
+		errs:             make(chan error, 3),
+	}, nil
 }
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/br3w0r/gamelist-backend/util/metrics"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const METRICS_PATH = "/metrics"
+
+// newMetricsServer is the server of metrics, nil if they're disabled. It listens on its
+// own address, so metrics aren't served on the public one.
+func newMetricsServer(options ServerOptions) *http.Server {
+	if options.MetricsAddress == "" {
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(METRICS_PATH, metricsHandler(options.MetricsToken))
+
+	return &http.Server{
+		Addr:              options.MetricsAddress,
+		Handler:           mux,
+		ReadHeaderTimeout: options.ReadTimeout,
+		WriteTimeout:      options.WriteTimeout,
+	}
+}
+
+// metricsHandler serves metrics of the registry. Requests need the token as a bearer
+// token if it's set.
+func metricsHandler(token string) http.Handler {
+	handler := promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{})
+	if token == "" {
+		return handler
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bearer := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
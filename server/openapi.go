@@ -0,0 +1,287 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/br3w0r/gamelist-backend/entity"
+	"github.com/br3w0r/gamelist-backend/graph"
+	utilErrs "github.com/br3w0r/gamelist-backend/util/errors"
+	"github.com/br3w0r/gamelist-backend/util/openapi"
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+)
+
+const (
+	OPENAPI_PATH = "/openapi.json"
+
+	bearerAuth = "bearerAuth"
+)
+
+// routeDoc describes a route for the OpenAPI document. Schemas are reflected
+// from the zero values of query, body and response.
+type routeDoc struct {
+	summary string
+	tag     string
+	// The route requires an access token
+	auth     bool
+	query    interface{}
+	body     interface{}
+	response interface{}
+	// Content types of the body and the response, JSON if empty
+	bodyTypes     []string
+	responseTypes []string
+}
+
+var (
+	listFileTypes = []string{"application/json", "text/csv", "application/xml"}
+
+	// Route docs are keyed by "<method> <path>" of routes relative to the prefix of
+	// their API version. Every route of the API must be documented here.
+	routeDocsV0 = map[string]routeDoc{
+		"POST /games/all": {
+			summary: "Games after the last one with list types of the user ordered by id", tag: "games", auth: true,
+			body: entity.GameBatchRequest{}, response: []entity.TypedGameListProperties{},
+		},
+		"POST /games/search": {
+			summary: "Search games by name", tag: "games", auth: true,
+			body: entity.SearchRequest{}, response: []entity.GameSearchResult{},
+		},
+		"POST /games/details": {
+			summary: "Game with its platforms and genres", tag: "games", auth: true,
+			body: entity.GameDetailsRequest{}, response: entity.GameDetailsResponse{},
+		},
+		"POST /list-game": {
+			summary: "Add a game to the user's list", tag: "list", auth: true,
+			body: entity.GameListRequest{}, response: entity.MessageResponse{},
+		},
+		"GET /my-games": {
+			summary: "First page of the user's list ordered by name", tag: "list", auth: true,
+			query: entity.PageRequest{}, response: []entity.TypedGameListProperties{},
+		},
+		"POST /my-games/import": {
+			summary: "Import a list file", tag: "list", auth: true,
+			query: entity.ImportRequest{}, body: []entity.ListEntry{}, response: entity.ImportResult{},
+			bodyTypes: listFileTypes,
+		},
+		"GET /my-games/export": {
+			summary: "Export the user's list", tag: "list", auth: true,
+			query: entity.ExportRequest{}, response: []entity.ListEntry{},
+			responseTypes: listFileTypes,
+		},
+		"POST /profiles": {
+			summary: "Sign up", tag: "profiles",
+			body: entity.Profile{}, response: entity.MessageResponse{},
+		},
+		"GET /profiles": {
+			summary: "First page of profiles ordered by id", tag: "profiles", auth: true,
+			query: entity.PageRequest{}, response: []entity.ProfileInfo{},
+		},
+		"POST /aquire-tokens": {
+			summary: "Sign in with a nickname or an email", tag: "auth",
+			body: entity.LoginProfile{}, response: entity.TokenPair{},
+		},
+		"POST /refresh-tokens": {
+			summary: "Exchange a refresh token for a new token pair", tag: "auth",
+			body: entity.RefreshRequest{}, response: entity.TokenPair{},
+		},
+		"POST /revoke-token": {
+			summary: "Revoke a refresh token", tag: "auth",
+			body: entity.RefreshRequest{}, response: entity.MessageResponse{},
+		},
+		"GET /delete-all-refresh-tokens": {
+			summary: "Sign out on all devices", tag: "auth", auth: true,
+			response: entity.MessageResponse{},
+		},
+		"GET /account/export": {
+			summary: "Archive of all data of the user", tag: "account", auth: true,
+			response: entity.AccountArchive{},
+		},
+		"POST /account/delete": {
+			summary: "Request deletion of the user's account", tag: "account", auth: true,
+			body: entity.AccountDeletionRequest{}, response: entity.AccountDeletion{},
+		},
+		"POST /account/restore": {
+			summary: "Cancel deletion of the user's account", tag: "account", auth: true,
+			response: entity.MessageResponse{},
+		},
+		"GET /list-types": {
+			summary: "First page of list types ordered by id", tag: "catalog", auth: true,
+			query: entity.PageRequest{}, response: []entity.ListType{},
+		},
+		"GET /genres": {
+			summary: "First page of genres ordered by id", tag: "catalog", auth: true,
+			query: entity.PageRequest{}, response: []entity.Genre{},
+		},
+		"GET /platforms": {
+			summary: "First page of platforms ordered by id", tag: "catalog", auth: true,
+			query: entity.PageRequest{}, response: []entity.Platform{},
+		},
+		"GET /social-types": {
+			summary: "First page of social types ordered by id", tag: "catalog", auth: true,
+			query: entity.PageRequest{}, response: []entity.SocialType{},
+		},
+		"GET " + OPENAPI_PATH: {
+			summary: "This document", tag: "meta",
+			response: map[string]interface{}{},
+		},
+	}
+
+	routeDocsV1 = map[string]routeDoc{
+		"GET /games": {
+			summary: "Page of games with list types of the user ordered by id", tag: "games", auth: true,
+			query: entity.GamesRequest{}, response: entity.Page[entity.TypedGameListProperties]{},
+		},
+		"GET /games/:id": {
+			summary: "Game with its platforms and genres", tag: "games", auth: true,
+			response: entity.GameDetailsResponse{},
+		},
+		"GET /my-games": {
+			summary: "Page of the user's list ordered by name", tag: "list", auth: true,
+			query: entity.PageRequest{}, response: entity.Page[entity.TypedGameListProperties]{},
+		},
+		"PUT /my-games/:id": {
+			summary: "Set the list type of a game of the user's list", tag: "list", auth: true,
+			body: entity.ListTypeRequest{}, response: entity.MessageResponse{},
+		},
+		"DELETE /my-games/:id": {
+			summary: "Remove a game from the user's list", tag: "list", auth: true,
+			response: entity.MessageResponse{},
+		},
+		"POST /my-games/import": routeDocsV0["POST /my-games/import"],
+		"GET /my-games/export":  routeDocsV0["GET /my-games/export"],
+		"POST /profiles":        routeDocsV0["POST /profiles"],
+		"GET /profiles": {
+			summary: "Page of profiles ordered by id", tag: "profiles", auth: true,
+			query: entity.PageRequest{}, response: entity.Page[entity.ProfileInfo]{},
+		},
+		"POST /sessions":           routeDocsV0["POST /aquire-tokens"],
+		"POST /sessions/refresh":   routeDocsV0["POST /refresh-tokens"],
+		"POST /sessions/revoke":    routeDocsV0["POST /revoke-token"],
+		"DELETE /sessions":         routeDocsV0["GET /delete-all-refresh-tokens"],
+		"GET /account/export":      routeDocsV0["GET /account/export"],
+		"POST /account/deletion":   routeDocsV0["POST /account/delete"],
+		"DELETE /account/deletion": routeDocsV0["POST /account/restore"],
+		"GET /list-types": {
+			summary: "Page of list types ordered by id", tag: "catalog", auth: true,
+			query: entity.PageRequest{}, response: entity.Page[entity.ListType]{},
+		},
+		"GET /genres": {
+			summary: "Page of genres ordered by id", tag: "catalog", auth: true,
+			query: entity.PageRequest{}, response: entity.Page[entity.Genre]{},
+		},
+		"GET /platforms": {
+			summary: "Page of platforms ordered by id", tag: "catalog", auth: true,
+			query: entity.PageRequest{}, response: entity.Page[entity.Platform]{},
+		},
+		"GET /social-types": {
+			summary: "Page of social types ordered by id", tag: "catalog", auth: true,
+			query: entity.PageRequest{}, response: entity.Page[entity.SocialType]{},
+		},
+		"GET /events": {
+			summary: "Server-sent events of changes of the user's list and profile", tag: "events", auth: true,
+			response: entity.Event{}, responseTypes: []string{"text/event-stream"},
+		},
+		"GET /profiles/:nickname/events": {
+			summary: "Server-sent events of changes of the profile's list and profile", tag: "events", auth: true,
+			response: entity.Event{}, responseTypes: []string{"text/event-stream"},
+		},
+		"POST /graphql": {
+			summary: "Execute a GraphQL query", tag: "graphql", auth: true,
+			body: graph.Request{}, response: graphql.Result{},
+		},
+		"GET " + OPENAPI_PATH: routeDocsV0["GET "+OPENAPI_PATH],
+	}
+)
+
+// newOpenAPI documents routes under prefix. Routes missing from docs are left out.
+func newOpenAPI(routes gin.RoutesInfo, prefix string, docs map[string]routeDoc, deprecated bool) *openapi.Document {
+	gen := openapi.NewGenerator(openapi.Info{
+		Title:       "Gamelist API",
+		Description: "Generated from the API routes and entity structs",
+		Version:     strings.TrimPrefix(prefix, "/api/"),
+	})
+	gen.AddSecurityScheme(bearerAuth, openapi.SecurityScheme{
+		Type:         "http",
+		Scheme:       "bearer",
+		BearerFormat: "JWT",
+	})
+
+	errorResponse := openapi.Response{
+		Description: "Error",
+		Content:     jsonContent(gen.Schema(utilErrs.ErrorResponse{}), nil),
+	}
+
+	for _, route := range routes {
+		if !strings.HasPrefix(route.Path, prefix+"/") {
+			continue
+		}
+		path := strings.TrimPrefix(route.Path, prefix)
+		doc, ok := docs[route.Method+" "+path]
+		if !ok {
+			continue
+		}
+
+		op := &openapi.Operation{
+			Summary:    doc.summary,
+			Deprecated: deprecated,
+			Responses: map[string]openapi.Response{
+				"200": {
+					Description: "OK",
+					Content:     jsonContent(gen.Schema(doc.response), doc.responseTypes),
+				},
+				"default": errorResponse,
+			},
+		}
+		if doc.tag != "" {
+			op.Tags = []string{doc.tag}
+		}
+		if doc.auth {
+			op.Security = []map[string][]string{{bearerAuth: {}}}
+		}
+		if doc.query != nil {
+			op.Parameters = gen.QueryParameters(doc.query)
+		}
+		if doc.body != nil {
+			op.RequestBody = &openapi.RequestBody{
+				Required: true,
+				Content:  jsonContent(gen.Schema(doc.body), doc.bodyTypes),
+			}
+		}
+
+		gen.AddOperation(route.Method, path, op)
+	}
+
+	doc := gen.Document()
+	doc.Servers = []openapi.Server{{URL: prefix}}
+
+	return doc
+}
+
+// jsonContent describes JSON bodies with the schema and other content types as plain text
+func jsonContent(schema *openapi.Schema, contentTypes []string) map[string]openapi.MediaType {
+	if len(contentTypes) == 0 {
+		contentTypes = []string{"application/json"}
+	}
+
+	content := map[string]openapi.MediaType{}
+	for _, contentType := range contentTypes {
+		if contentType == "application/json" {
+			content[contentType] = openapi.MediaType{Schema: schema}
+		} else {
+			content[contentType] = openapi.MediaType{Schema: &openapi.Schema{Type: "string"}}
+		}
+	}
+
+	return content
+}
+
+// serveOpenAPI registers the document of all routes of the API version group.
+// It must be called after all of them are registered.
+func serveOpenAPI(server *gin.Engine, group *gin.RouterGroup, docs map[string]routeDoc, deprecated bool) {
+	var doc *openapi.Document
+	group.GET(OPENAPI_PATH, func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, doc)
+	})
+	doc = newOpenAPI(server.Routes(), group.BasePath(), docs, deprecated)
+}
@@ -2,15 +2,24 @@ package server
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/br3w0r/gamelist-backend/entity"
+	"github.com/br3w0r/gamelist-backend/migrations"
+	"github.com/br3w0r/gamelist-backend/repository"
+	"github.com/br3w0r/gamelist-backend/service"
+	"github.com/br3w0r/gamelist-backend/util/openapi"
 	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -48,27 +57,42 @@ func genericRequest(server *gin.Engine, method string, url string, options reque
 }
 
 func TestServer(t *testing.T) {
-	t.Log("TestServer requires a working scrapper instance to run")
+	t.Log("TestServer requires a working scrapper instance and a migrated database to run")
 
 	if os.Getenv("CI") != "" {
 		t.Skip("TestServer doesn't support CI environment")
 	}
 
-	// Initializing server with test db
+	// Initializing server with the database of the development compose file
 	options := ServerOptions{
-		Production:         true,
-		ServeStatic:        false,
-		ForceScrape:        true,
-		ScraperAsync:       false,
-		DatabaseDist:       "./test.db",
-		ScraperGRPCAddress: "localhost",
-		StressTest:         false,
-		SilentMode:         true,
+		Production:   true,
+		ServeStatic:  false,
+		ForceScrape:  true,
+		ScraperAsync: false,
+		Scraper:      service.DefaultScraperConfig,
+		GameSources:  []service.GameSourceConfig{{Name: "scraper", Kind: service.GameSourceScraper}},
+		StressTest:   false,
+		SilentMode:   true,
+		Address:      "127.0.0.1:0",
+		DBConfig: &repository.DBConfig{
+			Host:     "localhost",
+			Port:     "5432",
+			User:     "postgres",
+			DBName:   "gamelist",
+			Password: os.Getenv("DB_PASSWORD"),
+			TimeZone: "UTC",
+		},
 	}
 
-	server := NewServer(options)
-
-	defer os.Remove("./test.db")
+	app, err := NewServer(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := app.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer app.stop() //nolint:errcheck
+	server := app.Engine()
 
 	Convey("Create profile should return ok status", t,
 		genericRequest(server, "POST", "http://localhost/api/v0/profiles",
@@ -147,3 +171,337 @@ func TestServer(t *testing.T) {
 		))
 	})
 }
+
+var ginParam = regexp.MustCompile(`[:*]([^/]+)`)
+
+// undocumentedRoutes lists routes under prefix which have no operation in doc
+func undocumentedRoutes(routes gin.RoutesInfo, prefix string, doc *openapi.Document) []string {
+	var missing []string
+	for _, route := range routes {
+		if !strings.HasPrefix(route.Path, prefix+"/") {
+			continue
+		}
+
+		path := ginParam.ReplaceAllString(strings.TrimPrefix(route.Path, prefix), "{$1}")
+		if _, ok := doc.Paths[path][strings.ToLower(route.Method)]; !ok {
+			missing = append(missing, route.Method+" "+route.Path)
+		}
+	}
+
+	return missing
+}
+
+func newTestServer(t *testing.T) *gin.Engine {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	repo := service.NewMockGamelistRepository(ctrl)
+	repo.EXPECT().AbortStaleScrapeJobs(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+
+	server, err := newServer(ServerOptions{
+		Production:  true,
+		SilentMode:  true,
+		APIv0Sunset: time.Date(2027, time.April, 19, 0, 0, 0, 0, time.UTC),
+	}, repo, repository.NewLocalEventBackend())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return server.Engine()
+}
+
+func TestOpenAPI(t *testing.T) {
+	server := newTestServer(t)
+
+	versions := []struct {
+		prefix     string
+		docs       map[string]routeDoc
+		deprecated bool
+	}{
+		{API_V0, routeDocsV0, true},
+		{API_V1, routeDocsV1, false},
+	}
+
+	for _, version := range versions {
+		version := version
+
+		Convey(fmt.Sprintf("OpenAPI document of %s should describe every route", version.prefix), t, func() {
+			req, err := http.NewRequest("GET", "http://localhost"+version.prefix+OPENAPI_PATH, nil)
+			So(err, ShouldBeNil)
+
+			w := httptest.NewRecorder()
+			server.ServeHTTP(w, req)
+			So(w.Code, ShouldEqual, http.StatusOK)
+
+			var doc openapi.Document
+			So(json.Unmarshal(w.Body.Bytes(), &doc), ShouldBeNil)
+			So(doc.OpenAPI, ShouldEqual, openapi.Version)
+			So(undocumentedRoutes(server.Routes(), version.prefix, &doc), ShouldBeEmpty)
+
+			for _, item := range doc.Paths {
+				for _, op := range item {
+					So(op.Deprecated, ShouldEqual, version.deprecated)
+				}
+			}
+
+			Convey("Every documented route should exist", func() {
+				registered := map[string]bool{}
+				for _, route := range server.Routes() {
+					registered[route.Method+" "+strings.TrimPrefix(route.Path, version.prefix)] = true
+				}
+				for key := range version.docs {
+					So(registered, ShouldContainKey, key)
+				}
+			})
+		})
+	}
+
+	Convey("Schemas should follow json and binding tags", t, func() {
+		doc := newOpenAPI(server.Routes(), API_V1, routeDocsV1, false)
+
+		game := doc.Components.Schemas["TypedGameListProperties"]
+		So(game, ShouldNotBeNil)
+		So(game.Properties, ShouldContainKey, "id")
+		So(game.Properties, ShouldNotContainKey, "Platforms")
+		So(game.Properties["image_url"].Format, ShouldEqual, "uri")
+		So(*game.Properties["year_released"].Minimum, ShouldEqual, 1000)
+		So(game.Required, ShouldResemble, []string{"image_url", "name", "year_released"})
+
+		profile := doc.Components.Schemas["ProfileInfo"]
+		So(profile, ShouldNotBeNil)
+		So(*profile.Properties["nickname"].MinLength, ShouldEqual, 2)
+		So(*profile.Properties["nickname"].MaxLength, ShouldEqual, 20)
+
+		So(doc.Components.Schemas, ShouldContainKey, "PageGenre")
+		So(doc.Paths["/genres"]["get"].Parameters, ShouldHaveLength, 2)
+		So(doc.Paths["/genres"]["get"].Security, ShouldHaveLength, 1)
+		So(doc.Paths["/games"]["get"].Parameters, ShouldHaveLength, 3)
+		So(doc.Paths["/games/{id}"]["get"].Parameters[0].In, ShouldEqual, "path")
+		So(doc.Paths["/profiles"]["post"].Security, ShouldBeEmpty)
+	})
+
+	Convey("Routes without docs should be reported", t, func() {
+		docs := map[string]routeDoc{}
+		for key, doc := range routeDocsV1 {
+			if key != "GET /genres" {
+				docs[key] = doc
+			}
+		}
+
+		doc := newOpenAPI(server.Routes(), API_V1, docs, false)
+		So(undocumentedRoutes(server.Routes(), API_V1, doc), ShouldResemble, []string{"GET " + API_V1 + "/genres"})
+	})
+}
+
+func TestAPIVersions(t *testing.T) {
+	server := newTestServer(t)
+
+	request := func(method string, path string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(method, "http://localhost"+path, nil)
+		So(err, ShouldBeNil)
+
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+		return w
+	}
+
+	Convey("v0 responses should be marked as deprecated", t, func() {
+		w := request("GET", API_V0+OPENAPI_PATH)
+		So(w.Header().Get("Deprecation"), ShouldEqual, fmt.Sprint("@", API_V0_DEPRECATED_AT.Unix()))
+		So(w.Header().Get("Sunset"), ShouldEqual, "Mon, 19 Apr 2027 00:00:00 GMT")
+		So(w.Header().Get("Link"), ShouldEqual, `</api/v1>; rel="successor-version"`)
+
+		w = request("POST", API_V0+"/games/all")
+		So(w.Code, ShouldEqual, http.StatusUnauthorized)
+		So(w.Header().Get("Deprecation"), ShouldNotBeEmpty)
+	})
+
+	Convey("v1 responses shouldn't be marked as deprecated", t, func() {
+		w := request("GET", API_V1+OPENAPI_PATH)
+		So(w.Code, ShouldEqual, http.StatusOK)
+		So(w.Header().Get("Deprecation"), ShouldBeEmpty)
+		So(w.Header().Get("Sunset"), ShouldBeEmpty)
+	})
+
+	Convey("v1 routes should use their methods", t, func() {
+		So(request("GET", API_V1+"/games/1").Code, ShouldEqual, http.StatusUnauthorized)
+		So(request("DELETE", API_V1+"/sessions").Code, ShouldEqual, http.StatusUnauthorized)
+		So(request("GET", API_V1+"/delete-all-refresh-tokens").Code, ShouldEqual, http.StatusNotFound)
+	})
+}
+
+func TestMetricsHandler(t *testing.T) {
+	Convey("Metrics should only be served with the token", t, func() {
+		handler := metricsHandler("metrics-token")
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", METRICS_PATH, nil))
+		So(w.Code, ShouldEqual, http.StatusUnauthorized)
+
+		req := httptest.NewRequest("GET", METRICS_PATH, nil)
+		req.Header.Set("Authorization", "Bearer metrics-token")
+		w = httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		So(w.Code, ShouldEqual, http.StatusOK)
+		So(w.Body.String(), ShouldContainSubstring, "go_goroutines")
+
+		Convey("Or without it if it isn't set", func() {
+			w := httptest.NewRecorder()
+			metricsHandler("").ServeHTTP(w, httptest.NewRequest("GET", METRICS_PATH, nil))
+			So(w.Code, ShouldEqual, http.StatusOK)
+		})
+	})
+}
+
+func TestHealthRoutes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	repo := service.NewMockGamelistRepository(ctrl)
+	repo.EXPECT().AbortStaleScrapeJobs(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	server, err := newServer(ServerOptions{Production: true, SilentMode: true}, repo, repository.NewLocalEventBackend())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request := func(path string) (*httptest.ResponseRecorder, entity.HealthReport) {
+		w := httptest.NewRecorder()
+		server.Engine().ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+
+		var report entity.HealthReport
+		So(json.Unmarshal(w.Body.Bytes(), &report), ShouldBeNil)
+		return w, report
+	}
+
+	Convey("Liveness shouldn't depend on the database", t, func() {
+		w, report := request("/healthz")
+		So(w.Code, ShouldEqual, http.StatusOK)
+		So(report.Status, ShouldEqual, entity.HealthOK)
+		So(report.Checks, ShouldBeEmpty)
+	})
+
+	Convey("Readiness should expect the newest migration", t, func() {
+		latest, err := migrations.Latest()
+		So(err, ShouldBeNil)
+		So(latest, ShouldBeGreaterThanOrEqualTo, 20261019140000)
+
+		repo.EXPECT().Ping(gomock.Any()).Return(nil).AnyTimes()
+		repo.EXPECT().SchemaVersion(gomock.Any()).Return(latest, nil).AnyTimes()
+		w, report := request("/readyz")
+		So(w.Code, ShouldEqual, http.StatusOK)
+		So(report.Checks, ShouldContainKey, service.HEALTH_CHECK_MIGRATIONS)
+
+		Convey("And report unready while draining", func() {
+			server.healthService.Drain()
+			w, report := request("/readyz")
+			So(w.Code, ShouldEqual, http.StatusServiceUnavailable)
+			So(report.Status, ShouldEqual, entity.HealthDraining)
+		})
+	})
+}
+
+func TestUnreachableDatabase(t *testing.T) {
+	// Nothing listens on the port, so connections are refused right away
+	server, err := NewServer(ServerOptions{
+		Production: true,
+		SilentMode: true,
+		DBConfig: &repository.DBConfig{
+			Host:     "127.0.0.1",
+			Port:     "1",
+			User:     "postgres",
+			DBName:   "gamelist",
+			Password: "postgres",
+			TimeZone: "UTC",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { server.repo.Close() }) //nolint:errcheck
+
+	Convey("The server should start without the database and report it in readiness", t, func() {
+		w := httptest.NewRecorder()
+		server.Engine().ServeHTTP(w, httptest.NewRequest("GET", "/readyz", nil))
+		So(w.Code, ShouldEqual, http.StatusServiceUnavailable)
+
+		var report entity.HealthReport
+		So(json.Unmarshal(w.Body.Bytes(), &report), ShouldBeNil)
+		So(report.Status, ShouldEqual, entity.HealthFailed)
+		So(report.Checks[service.HEALTH_CHECK_DATABASE].Status, ShouldEqual, entity.HealthFailed)
+		So(w.Body.String(), ShouldNotContainSubstring, "127.0.0.1")
+	})
+}
+
+func TestLifecycle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	repo := service.NewMockGamelistRepository(ctrl)
+	repo.EXPECT().AbortStaleScrapeJobs(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+	repo.EXPECT().Close().Return(nil)
+
+	server, err := newServer(ServerOptions{
+		Production:   true,
+		SilentMode:   true,
+		Address:      "127.0.0.1:0",
+		ReadTimeout:  time.Second,
+		WriteTimeout: time.Second,
+	}, repo, repository.NewLocalEventBackend())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	started := make(chan struct{}, 2)
+	server.Engine().GET("/slow", func(ctx *gin.Context) {
+		started <- struct{}{}
+		time.Sleep(200 * time.Millisecond)
+		ctx.String(http.StatusOK, "done")
+	})
+	server.Engine().GET("/stream", func(ctx *gin.Context) {
+		events, unsubscribe := server.eventBus.Subscribe("test")
+		defer unsubscribe()
+		started <- struct{}{}
+		for range events {
+		}
+	})
+
+	Convey("Stop should wait for in-flight requests and end streams", t, func() {
+		So(server.Start(), ShouldBeNil)
+		url := "http://" + server.Addr().String()
+
+		type result struct {
+			status int
+			err    error
+		}
+		get := func(path string) chan result {
+			results := make(chan result, 1)
+			go func() {
+				res, err := http.Get(url + path)
+				if err != nil {
+					results <- result{err: err}
+					return
+				}
+				res.Body.Close()
+				results <- result{status: res.StatusCode}
+			}()
+			return results
+		}
+		slow, stream := get("/slow"), get("/stream")
+		<-started
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		start := time.Now()
+		So(server.Stop(ctx), ShouldBeNil)
+		So(time.Since(start), ShouldBeLessThan, 2*time.Second)
+
+		res := <-slow
+		So(res.err, ShouldBeNil)
+		So(res.status, ShouldEqual, http.StatusOK)
+		So((<-stream).err, ShouldBeNil)
+
+		_, err := http.Get(url + "/healthz")
+		So(err, ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/br3w0r/gamelist-backend/entity"
+	"github.com/br3w0r/gamelist-backend/repository"
+	utilErrs "github.com/br3w0r/gamelist-backend/util/errors"
+	utilLogger "github.com/br3w0r/gamelist-backend/util/logger"
+	"github.com/robfig/cron/v3"
+)
+
+var accountsLog = utilLogger.For(utilLogger.SubsystemAccounts)
+
+type AccountService interface {
+	// ExportAccount builds an archive of all the user's data
+	ExportAccount(ctx context.Context, nickname string) (*entity.AccountArchive, error)
+	// RequestDeletion revokes all refresh tokens of the user, issued access tokens
+	// stay valid until they expire. The account is purged after the grace period
+	// unless the deletion is cancelled.
+	RequestDeletion(ctx context.Context, nickname string) (*entity.AccountDeletion, error)
+	CancelDeletion(ctx context.Context, nickname string) error
+	// Purge deletes accounts whose grace period has passed and returns their number
+	Purge(ctx context.Context) (int64, error)
+
+	// Schedule purges accounts on every tick of the cron spec, e.g. "@hourly"
+	Schedule(spec string) error
+	Stop()
+}
+
+type accountService struct {
+	repo            repository.GamelistRepository
+	gamelistService GameListService
+	gracePeriod     time.Duration
+
+	mu   sync.Mutex
+	cron *cron.Cron
+}
+
+const (
+	ACCOUNT_DELETION_GRACE_PERIOD = 30 * 24 * time.Hour
+)
+
+func NewAccountService(repo repository.GamelistRepository, gamelistService GameListService, gracePeriod time.Duration) AccountService {
+	if gracePeriod <= 0 {
+		gracePeriod = ACCOUNT_DELETION_GRACE_PERIOD
+	}
+
+	return &tracedAccountService{&accountService{
+		repo:            repo,
+		gamelistService: gamelistService,
+		gracePeriod:     gracePeriod,
+	}}
+}
+
+func (s *accountService) ExportAccount(ctx context.Context, nickname string) (*entity.AccountArchive, error) {
+	profile, err := s.repo.GetAccount(ctx, nickname)
+	if err != nil {
+		return nil, err
+	}
+
+	games, err := s.gamelistService.ExportGameList(ctx, nickname)
+	if err != nil {
+		return nil, err
+	}
+
+	archive := &entity.AccountArchive{
+		ExportedAt: time.Now(),
+		Profile: entity.AccountProfile{
+			ID:                  profile.ID,
+			Nickname:            profile.Nickname,
+			Email:               profile.Email,
+			Description:         profile.Description,
+			GamesListed:         profile.GamesListed,
+			CreatedAt:           profile.CreatedAt,
+			UpdatedAt:           profile.UpdatedAt,
+			DeletionRequestedAt: profile.DeletionRequestedAt,
+		},
+		Socials:  make([]entity.AccountSocial, len(profile.Socials)),
+		Games:    games,
+		Sessions: make([]entity.AccountSession, len(profile.RefreshTokens)),
+	}
+	if archive.Games == nil {
+		archive.Games = []entity.ListEntry{}
+	}
+
+	for i, social := range profile.Socials {
+		archive.Socials[i] = entity.AccountSocial{
+			Type: social.Type.Name,
+			Data: social.Data,
+		}
+	}
+	for i, token := range profile.RefreshTokens {
+		archive.Sessions[i] = entity.AccountSession{
+			ID:        token.ID,
+			CreatedAt: token.CreatedAt,
+		}
+		if token.DeletedAt.Valid {
+			revokedAt := token.DeletedAt.Time
+			archive.Sessions[i].RevokedAt = &revokedAt
+		}
+	}
+
+	return archive, nil
+}
+
+func (s *accountService) RequestDeletion(ctx context.Context, nickname string) (*entity.AccountDeletion, error) {
+	requestedAt, err := s.repo.RequestProfileDeletion(ctx, nickname, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.AccountDeletion{
+		RequestedAt: requestedAt,
+		PurgeAt:     requestedAt.Add(s.gracePeriod),
+	}, nil
+}
+
+func (s *accountService) CancelDeletion(ctx context.Context, nickname string) error {
+	return s.repo.CancelProfileDeletion(ctx, nickname)
+}
+
+func (s *accountService) Purge(ctx context.Context) (int64, error) {
+	return s.repo.PurgeProfiles(ctx, time.Now().Add(-s.gracePeriod))
+}
+
+func (s *accountService) Schedule(spec string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cron != nil {
+		return utilErrs.New(utilErrs.BadInput, nil, "account purges are already scheduled")
+	}
+
+	c := cron.New()
+	_, err := c.AddFunc(spec, func() {
+		purged, err := s.Purge(context.Background())
+		if err != nil {
+			accountsLog.Error("failed to purge deleted accounts", "error", err)
+		} else if purged > 0 {
+			accountsLog.Info("deleted accounts purged", "purged", purged)
+		}
+	})
+	if err != nil {
+		return utilErrs.Newf(utilErrs.BadInput, err, "wrong account purge schedule \"%s\"", spec)
+	}
+
+	c.Start()
+	s.cron = c
+
+	return nil
+}
+
+func (s *accountService) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cron != nil {
+		<-s.cron.Stop().Done()
+		s.cron = nil
+	}
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"sort"
+
+	"github.com/br3w0r/gamelist-backend/entity"
+	"github.com/br3w0r/gamelist-backend/helpers"
+)
+
+const (
+	// Pairs scored lower aren't considered duplicates
+	DUPLICATE_SCORE_THRESHOLD = 0.7
+	// Bigger groups of games with the same base name are most likely a series, not duplicates
+	DUPLICATE_GROUP_LIMIT = 20
+	DUPLICATES_LIMIT      = 100
+
+	duplicateNameWeight     = 0.6
+	duplicateYearWeight     = 0.2
+	duplicatePlatformWeight = 0.2
+)
+
+// FindDuplicates finds pairs of likely the same games by their normalized names,
+// release years and platforms. Platforms of the games must be loaded.
+func FindDuplicates(games []entity.GameProperties) []entity.GameDuplicate {
+	groups := map[string][]*entity.GameProperties{}
+	for i := range games {
+		base := helpers.BaseGameName(games[i].Name)
+		if base != "" {
+			groups[base] = append(groups[base], &games[i])
+		}
+	}
+
+	var duplicates []entity.GameDuplicate
+	for _, group := range groups {
+		if len(group) < 2 || len(group) > DUPLICATE_GROUP_LIMIT {
+			continue
+		}
+
+		for i := range group {
+			for j := i + 1; j < len(group); j++ {
+				a, b := group[i], group[j]
+				if a.ID > b.ID {
+					a, b = b, a
+				}
+
+				score := duplicateScore(a, b)
+				if score >= DUPLICATE_SCORE_THRESHOLD {
+					duplicates = append(duplicates, entity.GameDuplicate{
+						GameID:      a.ID,
+						DuplicateID: b.ID,
+						Score:       score,
+						Status:      entity.DuplicatePending,
+					})
+				}
+			}
+		}
+	}
+
+	sort.Slice(duplicates, func(i, j int) bool {
+		return duplicates[i].Score > duplicates[j].Score
+	})
+
+	return duplicates
+}
+
+func duplicateScore(a *entity.GameProperties, b *entity.GameProperties) float64 {
+	nameA, nameB := helpers.NormalizeGameName(a.Name), helpers.NormalizeGameName(b.Name)
+
+	// Games in the same group share the base name, so one name only adds a subtitle
+	nameScore := helpers.NameSimilarity(nameA, nameB)
+	if nameScore < 0.8 {
+		nameScore = 0.8
+	}
+
+	var yearScore float64
+	switch {
+	case a.YearReleased == 0 || b.YearReleased == 0:
+		yearScore = 0.5
+	case a.YearReleased == b.YearReleased:
+		yearScore = 1
+	case a.YearReleased+1 == b.YearReleased || b.YearReleased+1 == a.YearReleased:
+		yearScore = 0.5
+	}
+
+	platformScore := 0.5
+	if len(a.Platforms) > 0 && len(b.Platforms) > 0 {
+		platforms := map[string]bool{}
+		for i := range a.Platforms {
+			platforms[a.Platforms[i].Name] = true
+		}
+
+		common := 0
+		for i := range b.Platforms {
+			if platforms[b.Platforms[i].Name] {
+				common++
+			} else {
+				platforms[b.Platforms[i].Name] = true
+			}
+		}
+		platformScore = float64(common) / float64(len(platforms))
+	}
+
+	return duplicateNameWeight*nameScore + duplicateYearWeight*yearScore + duplicatePlatformWeight*platformScore
+}
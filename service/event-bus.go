@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/br3w0r/gamelist-backend/entity"
+	"github.com/br3w0r/gamelist-backend/repository"
+	utilLogger "github.com/br3w0r/gamelist-backend/util/logger"
+)
+
+var eventsLog = utilLogger.For(utilLogger.SubsystemEvents)
+
+const (
+	// EVENT_BUFFER is the number of events a subscriber may lag behind before its events are dropped
+	EVENT_BUFFER = 16
+)
+
+type EventBus interface {
+	// Publish sends the event to subscribers of its user on all instances.
+	// Failures are logged since events are a best effort.
+	Publish(event entity.Event)
+	// Subscribe streams events of the user until unsubscribe is called
+	Subscribe(nickname string) (events <-chan entity.Event, unsubscribe func())
+	// Close stops listening to the backend and closes channels of subscribers, so their
+	// streams end
+	Close()
+}
+
+type eventBus struct {
+	backend repository.EventBackend
+	cancel  context.CancelFunc
+	done    chan struct{}
+
+	mu          sync.RWMutex
+	subscribers map[string]map[chan entity.Event]struct{}
+	closed      bool
+}
+
+// NewEventBus starts listening to the backend right away
+func NewEventBus(backend repository.EventBackend) EventBus {
+	ctx, cancel := context.WithCancel(context.Background())
+	bus := &eventBus{
+		backend:     backend,
+		cancel:      cancel,
+		done:        make(chan struct{}),
+		subscribers: map[string]map[chan entity.Event]struct{}{},
+	}
+
+	go func() {
+		defer close(bus.done)
+		if err := backend.Listen(ctx, bus.deliver); err != nil {
+			eventsLog.Error("failed to listen for events", "error", err, "cause", errors.Unwrap(err))
+		}
+	}()
+
+	return bus
+}
+
+func (b *eventBus) Publish(event entity.Event) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		eventsLog.Error("failed to encode event", "type", event.Type, "error", err)
+		return
+	}
+
+	if err := b.backend.Publish(payload); err != nil {
+		eventsLog.Error("failed to publish event", "type", event.Type, "error", err, "cause", errors.Unwrap(err))
+	}
+}
+
+func (b *eventBus) Subscribe(nickname string) (<-chan entity.Event, func()) {
+	events := make(chan entity.Event, EVENT_BUFFER)
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		close(events)
+		return events, func() {}
+	}
+	if b.subscribers[nickname] == nil {
+		b.subscribers[nickname] = map[chan entity.Event]struct{}{}
+	}
+	b.subscribers[nickname][events] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+
+			// Close has closed it already
+			if _, ok := b.subscribers[nickname][events]; !ok {
+				return
+			}
+			delete(b.subscribers[nickname], events)
+			if len(b.subscribers[nickname]) == 0 {
+				delete(b.subscribers, nickname)
+			}
+			close(events)
+		})
+	}
+
+	return events, unsubscribe
+}
+
+func (b *eventBus) Close() {
+	b.cancel()
+	<-b.done
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, subscribers := range b.subscribers {
+		for events := range subscribers {
+			close(events)
+		}
+	}
+	b.subscribers = map[string]map[chan entity.Event]struct{}{}
+	b.closed = true
+}
+
+// deliver passes a payload of the backend to local subscribers of its user
+func (b *eventBus) deliver(payload []byte) {
+	var event entity.Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		eventsLog.Error("failed to decode event", "error", err)
+		return
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for events := range b.subscribers[event.Nickname] {
+		select {
+		case events <- event:
+		default:
+			// The subscriber doesn't keep up, it will catch up on the next full fetch
+		}
+	}
+}
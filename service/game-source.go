@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/br3w0r/gamelist-backend/entity"
+	pb "github.com/br3w0r/gamelist-backend/proto"
+	utilErrs "github.com/br3w0r/gamelist-backend/util/errors"
+)
+
+// GameSource is anything the game catalog can be filled from
+type GameSource interface {
+	Name() string
+	// Fetch streams all games of the source to emit
+	Fetch(ctx context.Context, emit func(game entity.SourcedGame) error) error
+}
+
+const (
+	GameSourceScraper = "scraper"
+	GameSourceFile    = "file"
+	GameSourceHTTP    = "http"
+
+	// Values of list fields in CSV files are separated by it, e.g. "PC;PS4"
+	csvListSeparator = ";"
+
+	// DefaultHTTPSourceTimeout limits fetches of HTTP sources without a timeout
+	DefaultHTTPSourceTimeout = 30 * time.Minute
+)
+
+type GameSourceConfig struct {
+	Name     string
+	Kind     string
+	Location string
+	// Timeout limits a fetch of an HTTP source including reading of its feed,
+	// DefaultHTTPSourceTimeout if it isn't set
+	Timeout time.Duration
+}
+
+// ParseGameSources parses comma separated "<name>=<kind>:<location>" specs.
+// The gRPC scraper needs no location, so it's just "scraper".
+// e.g. "scraper,catalog=file:/data/games.csv,feed=http:https://example.com/games.json"
+func ParseGameSources(spec string) ([]GameSourceConfig, error) {
+	var sources []GameSourceConfig
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if entry == GameSourceScraper {
+			sources = append(sources, GameSourceConfig{Name: GameSourceScraper, Kind: GameSourceScraper})
+			continue
+		}
+
+		nameKind := strings.SplitN(entry, "=", 2)
+		if len(nameKind) != 2 {
+			return nil, utilErrs.Newf(utilErrs.BadInput, nil, "wrong game source \"%s\": expected <name>=<kind>:<location>", entry)
+		}
+		kindLocation := strings.SplitN(nameKind[1], ":", 2)
+
+		conf := GameSourceConfig{Name: nameKind[0], Kind: kindLocation[0]}
+		if len(kindLocation) == 2 {
+			conf.Location = kindLocation[1]
+		}
+		if conf.Kind != GameSourceScraper && conf.Location == "" {
+			return nil, utilErrs.Newf(utilErrs.BadInput, nil, "wrong game source \"%s\": no location", entry)
+		}
+
+		sources = append(sources, conf)
+	}
+
+	return sources, nil
+}
+
+// NewGameSource builds a source by its config. Scraper sources share the given client.
+func NewGameSource(conf GameSourceConfig, scraper ScraperClient) (GameSource, error) {
+	switch conf.Kind {
+	case GameSourceScraper:
+		if scraper == nil {
+			return nil, utilErrs.New(utilErrs.BadInput, nil, "scraper isn't configured")
+		}
+		return NewScraperSource(conf.Name, scraper), nil
+	case GameSourceFile:
+		return NewFileSource(conf.Name, conf.Location), nil
+	case GameSourceHTTP:
+		timeout := conf.Timeout
+		if timeout <= 0 {
+			timeout = DefaultHTTPSourceTimeout
+		}
+		return NewHTTPSource(conf.Name, conf.Location, &http.Client{Timeout: timeout}), nil
+	}
+
+	return nil, utilErrs.Newf(utilErrs.BadInput, nil, "unknown kind of game source %s: %s", conf.Name, conf.Kind)
+}
+
+type scraperSource struct {
+	name    string
+	scraper ScraperClient
+}
+
+func NewScraperSource(name string, scraper ScraperClient) GameSource {
+	return &scraperSource{name, scraper}
+}
+
+func (s *scraperSource) Name() string {
+	return s.name
+}
+
+func (s *scraperSource) Fetch(ctx context.Context, emit func(game entity.SourcedGame) error) error {
+	return s.scraper.Scrape(ctx, func(game *pb.GameProperties) error {
+		return emit(entity.SourcedGame{
+			Source:     s.name,
+			ExternalID: game.SourceID(),
+			Game:       game.ConvertToEntity(),
+		})
+	})
+}
+
+// fileSource reads a JSON array of entity.GameFeedItem or a CSV file with
+// a header of entity.GameFeedItem's json field names, chosen by file extension
+type fileSource struct {
+	name string
+	path string
+}
+
+func NewFileSource(name string, path string) GameSource {
+	return &fileSource{name, path}
+}
+
+func (s *fileSource) Name() string {
+	return s.name
+}
+
+func (s *fileSource) Fetch(ctx context.Context, emit func(game entity.SourcedGame) error) error {
+	file, err := os.Open(s.path)
+	if err != nil {
+		return utilErrs.Newf(utilErrs.Internal, err, "failed to open game source file %s", s.path)
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(s.path)) {
+	case ".json":
+		return decodeJSONFeed(ctx, s.name, file, emit)
+	case ".csv":
+		return decodeCSVFeed(ctx, s.name, file, emit)
+	}
+
+	return utilErrs.Newf(utilErrs.BadInput, nil, "unsupported game source file %s: expected .json or .csv", s.path)
+}
+
+// httpSource reads a JSON array of entity.GameFeedItem from the URL
+type httpSource struct {
+	name   string
+	url    string
+	client *http.Client
+}
+
+func NewHTTPSource(name string, url string, client *http.Client) GameSource {
+	return &httpSource{name, url, client}
+}
+
+func (s *httpSource) Name() string {
+	return s.name
+}
+
+func (s *httpSource) Fetch(ctx context.Context, emit func(game entity.SourcedGame) error) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return utilErrs.Newf(utilErrs.BadInput, err, "wrong game feed url %s", s.url)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return utilErrs.Newf(utilErrs.Internal, err, "failed to get game feed %s", s.url)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return utilErrs.Newf(utilErrs.Internal, nil, "failed to get game feed %s: %s", s.url, resp.Status)
+	}
+
+	return decodeJSONFeed(ctx, s.name, resp.Body, emit)
+}
+
+// decodeJSONFeed decodes the array item by item, so big feeds aren't loaded at once
+func decodeJSONFeed(ctx context.Context, source string, r io.Reader, emit func(game entity.SourcedGame) error) error {
+	dec := json.NewDecoder(r)
+	if _, err := dec.Token(); err != nil {
+		return utilErrs.New(utilErrs.BadInput, err, "failed to parse game feed: expected an array")
+	}
+
+	for dec.More() {
+		if err := ctx.Err(); err != nil {
+			return utilErrs.New(utilErrs.Timeout, err, "game feed reading was interrupted")
+		}
+
+		var item entity.GameFeedItem
+		if err := dec.Decode(&item); err != nil {
+			return utilErrs.New(utilErrs.BadInput, err, "failed to parse game feed item")
+		}
+
+		if err := emit(item.Sourced(source)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func decodeCSVFeed(ctx context.Context, source string, r io.Reader, emit func(game entity.SourcedGame) error) error {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return utilErrs.New(utilErrs.BadInput, err, "failed to read game feed header")
+	}
+	columns := make(map[string]int, len(header))
+	for i := range header {
+		columns[strings.TrimSpace(header[i])] = i
+	}
+	if _, ok := columns["name"]; !ok {
+		return utilErrs.New(utilErrs.BadInput, nil, "game feed header has no name column")
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return utilErrs.New(utilErrs.Timeout, err, "game feed reading was interrupted")
+		}
+
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return utilErrs.New(utilErrs.BadInput, err, "failed to read game feed record")
+		}
+
+		column := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		item := entity.GameFeedItem{
+			ExternalID: column("external_id"),
+			Name:       column("name"),
+			ImageURL:   column("image_url"),
+			Platforms:  splitCSVList(column("platforms")),
+			Genres:     splitCSVList(column("genres")),
+		}
+		if year := column("year_released"); year != "" {
+			parsed, err := strconv.ParseUint(year, 10, 16)
+			if err != nil {
+				return utilErrs.Newf(utilErrs.BadInput, err, "wrong year_released of game \"%s\"", item.Name)
+			}
+			item.YearReleased = uint16(parsed)
+		}
+
+		if err := emit(item.Sourced(source)); err != nil {
+			return err
+		}
+	}
+}
+
+func splitCSVList(value string) []string {
+	var list []string
+	for _, v := range strings.Split(value, csvListSeparator) {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+
+	return list
+}
@@ -2,110 +2,408 @@ package service
 
 import (
 	"context"
-	"io"
-	"log"
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/br3w0r/gamelist-backend/entity"
-	pb "github.com/br3w0r/gamelist-backend/proto"
 	"github.com/br3w0r/gamelist-backend/repository"
 	utilErrs "github.com/br3w0r/gamelist-backend/util/errors"
+	utilLogger "github.com/br3w0r/gamelist-backend/util/logger"
+	"github.com/br3w0r/gamelist-backend/util/metrics"
+	"github.com/br3w0r/gamelist-backend/util/tracing"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/crypto/bcrypt"
-	"google.golang.org/grpc"
+)
+
+var (
+	ingestLog     = utilLogger.For(utilLogger.SubsystemIngest)
+	serviceTracer = tracing.Tracer("service")
 )
 
 type GameListService interface {
-	SaveGame(game entity.GameProperties) error
-	GetAllGames() ([]entity.GameProperties, error)
-	GetAllGamesTyped(nickname string, last uint64, batchSize int) ([]entity.TypedGameListProperties, error)
-	GetUserGameList(nickname string) ([]entity.TypedGameListProperties, error)
-	SearchGames(name string) ([]entity.GameSearchResult, error)
-	GetGameDetails(nickname string, gameId uint64) (*entity.GameDetailsResponse, error)
+	SaveGame(ctx context.Context, game *entity.GameProperties) error
+	UpdateGame(ctx context.Context, game *entity.GameProperties) error
+	// Delete* soft-delete catalog items. Items in use are only deleted with a reassignTo item.
+	DeleteGame(ctx context.Context, id uint64, reassignTo uint64) error
+	RestoreGame(ctx context.Context, id uint64) error
+	GetGame(ctx context.Context, id uint64) (*entity.GameProperties, error)
+	// Lists are paginated with limits of their collections
+	GetGames(ctx context.Context, page entity.PageRequest) (*entity.Page[entity.GameProperties], error)
+	GetAllGamesTyped(ctx context.Context, nickname string, request entity.GamesRequest) (*entity.Page[entity.TypedGameListProperties], error)
+	GetUserGameList(ctx context.Context, nickname string, page entity.PageRequest) (*entity.Page[entity.TypedGameListProperties], error)
+	// GetGameListsOfProfiles gets the same page of several lists, profiles with empty pages are left out
+	GetGameListsOfProfiles(ctx context.Context, profileIDs []uint64, page entity.PageRequest) (map[uint64]*entity.Page[entity.TypedGameListProperties], error)
+	SearchGames(ctx context.Context, name string) ([]entity.GameSearchResult, error)
+	GetGameDetails(ctx context.Context, nickname string, gameId uint64) (*entity.GameDetailsResponse, error)
+	// Batch lookups are keyed by the given ids, unknown ids are left out
+	GetGamesByIDs(ctx context.Context, ids []uint64) (map[uint64]entity.GameProperties, error)
+	GetPlatformsOfGames(ctx context.Context, gameIDs []uint64) (map[uint64][]entity.Platform, error)
+	GetGenresOfGames(ctx context.Context, gameIDs []uint64) (map[uint64][]entity.Genre, error)
+	GetListTypesOfGames(ctx context.Context, nickname string, gameIDs []uint64) (map[uint64]uint64, error)
+
+	// ScanDuplicates finds likely duplicate games and returns the number of found pairs
+	ScanDuplicates(ctx context.Context) (int, error)
+	GetGameDuplicates(ctx context.Context, status string, limit int) ([]entity.GameDuplicate, error)
+	DismissGameDuplicate(ctx context.Context, gameID uint64, duplicateID uint64) error
+	MergeGames(ctx context.Context, survivorID uint64, duplicateID uint64) error
+
+	CreateListType(ctx context.Context, listType *entity.ListType) error
+	UpdateListType(ctx context.Context, listType *entity.ListType) error
+	DeleteListType(ctx context.Context, id uint64, reassignTo uint64) error
+	RestoreListType(ctx context.Context, id uint64) error
+	GetAllListTypes(ctx context.Context, page entity.PageRequest) (*entity.Page[entity.ListType], error)
+	GetListTypesByIDs(ctx context.Context, ids []uint64) (map[uint64]entity.ListType, error)
+	// ListGame sets the list type of the game in the user's list, 0 removes the game from it
+	ListGame(ctx context.Context, nickname string, gameId uint64, listType uint64) error
+	// ImportGameList lists the entries which match a single game. Other entries are
+	// returned for review. Nothing is listed on dry run or if any listing fails.
+	ImportGameList(ctx context.Context, nickname string, entries []entity.ListEntry, dryRun bool) (*entity.ImportResult, error)
+	ExportGameList(ctx context.Context, nickname string) ([]entity.ListEntry, error)
+
+	SaveGenre(ctx context.Context, genre *entity.Genre) error
+	UpdateGenre(ctx context.Context, genre *entity.Genre) error
+	DeleteGenre(ctx context.Context, id uint64, reassignTo uint64) error
+	RestoreGenre(ctx context.Context, id uint64) error
+	GetAllGenres(ctx context.Context, page entity.PageRequest) (*entity.Page[entity.Genre], error)
+
+	SavePlatform(ctx context.Context, platform *entity.Platform) error
+	UpdatePlatform(ctx context.Context, platform *entity.Platform) error
+	DeletePlatform(ctx context.Context, id uint64, reassignTo uint64) error
+	RestorePlatform(ctx context.Context, id uint64) error
+	GetAllPlatforms(ctx context.Context, page entity.PageRequest) (*entity.Page[entity.Platform], error)
+
+	CreateProfile(ctx context.Context, profile entity.Profile) error
+	SaveProfile(ctx context.Context, profile entity.Profile) error
+	GetAllProfiles(ctx context.Context, page entity.PageRequest) (*entity.Page[entity.ProfileInfo], error)
+	GetProfileInfo(ctx context.Context, nickname string) (*entity.ProfileInfo, error)
+	GetSocialsOfProfiles(ctx context.Context, profileIDs []uint64) (map[uint64][]entity.Social, error)
+	CheckLogin(ctx context.Context, login entity.LoginProfile) (*entity.Profile, error)
+
+	SaveSocialType(ctx context.Context, socialType *entity.SocialType) error
+	UpdateSocialType(ctx context.Context, socialType *entity.SocialType) error
+	DeleteSocialType(ctx context.Context, id uint64, reassignTo uint64) error
+	RestoreSocialType(ctx context.Context, id uint64) error
+	GetAllSocialTypes(ctx context.Context, page entity.PageRequest) (*entity.Page[entity.SocialType], error)
+
+	// ScrapeGames ingests games from all configured sources
+	ScrapeGames(ctx context.Context) (*entity.IngestStats, error)
+	IngestGames(ctx context.Context, source GameSource) (*entity.IngestStats, error)
+}
+
+type gameListService struct {
+	repo     repository.GamelistRepository
+	sources  []GameSource
+	priority entity.SourcePriority
+	limits   entity.PageLimits
+	// Changes of lists and profiles are published to events if it's set
+	events EventBus
+}
+
+func NewGameListService(repo repository.GamelistRepository, sources []GameSource, priority entity.SourcePriority, limits entity.PageLimits, events EventBus) GameListService {
+	return &tracedGameListService{&gameListService{repo, sources, priority, limits, events}}
+}
+
+func (s *gameListService) publish(event entity.Event) {
+	if s.events == nil {
+		return
+	}
 
-	CreateListType(listType entity.ListType) error
-	GetAllListTypes() ([]entity.ListType, error)
-	ListGame(nickname string, gameId uint64, listType uint64) error
+	event.Time = time.Now()
+	s.events.Publish(event)
+}
 
-	SaveGenre(genre entity.Genre) error
-	GetAllGenres() ([]entity.Genre, error)
+func (s *gameListService) SaveGame(ctx context.Context, game *entity.GameProperties) error {
+	// Platforms and genres are looked up before the game is saved
+	return s.repo.WithTx(ctx, func(repo repository.GamelistRepository) error {
+		return repo.SaveGame(ctx, game)
+	})
+}
 
-	SavePlatform(platform entity.Platform) error
-	GetAllPlatforms() ([]entity.Platform, error)
+func (s *gameListService) UpdateGame(ctx context.Context, game *entity.GameProperties) error {
+	return s.repo.UpdateGame(ctx, game)
+}
 
-	CreateProfile(profile entity.Profile) error
-	SaveProfile(profile entity.Profile) error
-	GetAllProfiles() ([]entity.ProfileInfo, error)
-	CheckLogin(login entity.LoginProfile) (*entity.Profile, error)
+func (s *gameListService) DeleteGame(ctx context.Context, id uint64, reassignTo uint64) error {
+	return s.repo.DeleteGame(ctx, id, reassignTo)
+}
 
-	SaveSocialType(socialType entity.SocialType) error
-	GetAllSocialTypes() ([]entity.SocialType, error)
+func (s *gameListService) RestoreGame(ctx context.Context, id uint64) error {
+	return s.repo.RestoreGame(ctx, id)
+}
 
-	// gRPC
-	ScrapeGames()
+func (s *gameListService) GetGame(ctx context.Context, id uint64) (*entity.GameProperties, error) {
+	return s.repo.GetGame(ctx, id)
 }
 
-type gameListService struct {
-	repo               repository.GamelistRepository
-	scraperGRPCAddress string
+func (s *gameListService) GetGames(ctx context.Context, page entity.PageRequest) (*entity.Page[entity.GameProperties], error) {
+	return getPage(ctx, s.limits, entity.PageGames, page, s.repo.GetGames, func(game *entity.GameProperties) entity.Cursor {
+		return entity.Cursor{ID: game.ID}
+	})
+}
+
+func (s *gameListService) GetAllGamesTyped(ctx context.Context, nickname string, request entity.GamesRequest) (*entity.Page[entity.TypedGameListProperties], error) {
+	query := func(ctx context.Context, cursor entity.Cursor, limit int) ([]entity.TypedGameListProperties, error) {
+		return s.repo.GetAllGamesTyped(ctx, nickname, request.Query, cursor, limit)
+	}
+
+	return getPage(ctx, s.limits, entity.PageGames, request.PageRequest, query, func(game *entity.TypedGameListProperties) entity.Cursor {
+		return entity.Cursor{ID: game.ID}
+	})
+}
+
+func (s *gameListService) GetUserGameList(ctx context.Context, nickname string, page entity.PageRequest) (*entity.Page[entity.TypedGameListProperties], error) {
+	query := func(ctx context.Context, cursor entity.Cursor, limit int) ([]entity.TypedGameListProperties, error) {
+		return s.repo.GetUserGameList(ctx, nickname, cursor, limit)
+	}
+
+	return getPage(ctx, s.limits, entity.PageUserGames, page, query, func(game *entity.TypedGameListProperties) entity.Cursor {
+		return entity.Cursor{Key: game.Name, ID: game.ID}
+	})
+}
+
+func (s *gameListService) GetGameListsOfProfiles(ctx context.Context, profileIDs []uint64, page entity.PageRequest) (map[uint64]*entity.Page[entity.TypedGameListProperties], error) {
+	after, err := entity.DecodeCursor(page.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := s.limits.Clamp(entity.PageUserGames, page.Limit)
+	lists, err := s.repo.GetGameListsOfProfiles(ctx, profileIDs, after, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := make(map[uint64]*entity.Page[entity.TypedGameListProperties], len(lists))
+	for id, games := range lists {
+		pages[id] = entity.NewPage(games, limit, func(game *entity.TypedGameListProperties) entity.Cursor {
+			return entity.Cursor{Key: game.Name, ID: game.ID}
+		})
+	}
+
+	return pages, nil
+}
+
+func (s *gameListService) SearchGames(ctx context.Context, name string) ([]entity.GameSearchResult, error) {
+	return s.repo.SearchGames(ctx, name)
+}
+
+func (s *gameListService) GetGameDetails(ctx context.Context, nickname string, gameId uint64) (*entity.GameDetailsResponse, error) {
+	return s.repo.GetGameDetails(ctx, nickname, gameId)
 }
 
-func NewGameListService(repo repository.GamelistRepository, scraperGRPCAddress string) GameListService {
-	return &gameListService{repo, scraperGRPCAddress}
+func (s *gameListService) GetGamesByIDs(ctx context.Context, ids []uint64) (map[uint64]entity.GameProperties, error) {
+	return s.repo.GetGamesByIDs(ctx, ids)
 }
 
-func (s *gameListService) SaveGame(game entity.GameProperties) error {
-	return s.repo.SaveGame(game)
+func (s *gameListService) GetPlatformsOfGames(ctx context.Context, gameIDs []uint64) (map[uint64][]entity.Platform, error) {
+	return s.repo.GetPlatformsOfGames(ctx, gameIDs)
 }
 
-func (s *gameListService) GetAllGames() ([]entity.GameProperties, error) {
-	return s.repo.GetAllGames()
+func (s *gameListService) GetGenresOfGames(ctx context.Context, gameIDs []uint64) (map[uint64][]entity.Genre, error) {
+	return s.repo.GetGenresOfGames(ctx, gameIDs)
 }
 
-func (s *gameListService) GetAllGamesTyped(nickname string, last uint64, batchSize int) ([]entity.TypedGameListProperties, error) {
-	return s.repo.GetAllGamesTyped(nickname, last, batchSize)
+func (s *gameListService) GetListTypesOfGames(ctx context.Context, nickname string, gameIDs []uint64) (map[uint64]uint64, error) {
+	return s.repo.GetListTypesOfGames(ctx, nickname, gameIDs)
+}
+
+func (s *gameListService) ScanDuplicates(ctx context.Context) (int, error) {
+	games, err := s.repo.GetGames(ctx, entity.Cursor{}, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	duplicates := FindDuplicates(games)
+	if err := s.repo.SaveGameDuplicates(ctx, duplicates); err != nil {
+		return 0, err
+	}
+
+	return len(duplicates), nil
 }
 
-func (s *gameListService) GetUserGameList(nickname string) ([]entity.TypedGameListProperties, error) {
-	return s.repo.GetUserGameList(nickname)
+func (s *gameListService) GetGameDuplicates(ctx context.Context, status string, limit int) ([]entity.GameDuplicate, error) {
+	if status == "" {
+		status = entity.DuplicatePending
+	}
+	if limit <= 0 || limit > DUPLICATES_LIMIT {
+		limit = DUPLICATES_LIMIT
+	}
+
+	return s.repo.GetGameDuplicates(ctx, status, limit)
 }
 
-func (s *gameListService) SearchGames(name string) ([]entity.GameSearchResult, error) {
-	return s.repo.SearchGames(name)
+func (s *gameListService) DismissGameDuplicate(ctx context.Context, gameID uint64, duplicateID uint64) error {
+	return s.repo.DismissGameDuplicate(ctx, gameID, duplicateID)
 }
 
-func (s *gameListService) GetGameDetails(nickname string, gameId uint64) (*entity.GameDetailsResponse, error) {
-	return s.repo.GetGameDetails(nickname, gameId)
+func (s *gameListService) MergeGames(ctx context.Context, survivorID uint64, duplicateID uint64) error {
+	return s.repo.MergeGames(ctx, survivorID, duplicateID)
 }
 
-func (s *gameListService) CreateListType(listType entity.ListType) error {
-	return s.repo.CreateListType(listType)
+func (s *gameListService) CreateListType(ctx context.Context, listType *entity.ListType) error {
+	return s.repo.CreateListType(ctx, listType)
 }
 
-func (s *gameListService) GetAllListTypes() ([]entity.ListType, error) {
-	return s.repo.GetAllListTypes()
+func (s *gameListService) UpdateListType(ctx context.Context, listType *entity.ListType) error {
+	return s.repo.UpdateListType(ctx, listType)
 }
 
-func (s *gameListService) ListGame(nickname string, gameId uint64, listType uint64) error {
-	return s.repo.ListGame(nickname, gameId, listType)
+func (s *gameListService) DeleteListType(ctx context.Context, id uint64, reassignTo uint64) error {
+	return s.repo.DeleteListType(ctx, id, reassignTo)
 }
 
-func (s *gameListService) SaveGenre(genre entity.Genre) error {
-	return s.repo.SaveGenre(genre)
+func (s *gameListService) RestoreListType(ctx context.Context, id uint64) error {
+	return s.repo.RestoreListType(ctx, id)
 }
 
-func (s *gameListService) GetAllGenres() ([]entity.Genre, error) {
-	return s.repo.GetAllGenres()
+func (s *gameListService) GetAllListTypes(ctx context.Context, page entity.PageRequest) (*entity.Page[entity.ListType], error) {
+	return getPage(ctx, s.limits, entity.PageCatalog, page, s.repo.GetAllListTypes, func(listType *entity.ListType) entity.Cursor {
+		return entity.Cursor{ID: listType.ID}
+	})
 }
 
-func (s *gameListService) SavePlatform(platform entity.Platform) error {
-	return s.repo.SavePlatform(platform)
+func (s *gameListService) GetListTypesByIDs(ctx context.Context, ids []uint64) (map[uint64]entity.ListType, error) {
+	return s.repo.GetListTypesByIDs(ctx, ids)
 }
 
-func (s *gameListService) GetAllPlatforms() ([]entity.Platform, error) {
-	return s.repo.GetAllPlatforms()
+func (s *gameListService) ListGame(ctx context.Context, nickname string, gameId uint64, listType uint64) error {
+	// The game and the list type are checked before the entry is saved. The event is
+	// published once the entry is committed.
+	err := s.repo.WithTx(ctx, func(repo repository.GamelistRepository) error {
+		return repo.ListGame(ctx, nickname, gameId, listType)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.publish(entity.Event{Type: entity.EventListUpdated, Nickname: nickname, GameID: gameId, ListType: &listType})
+	return nil
 }
 
-func (s *gameListService) CreateProfile(profile entity.Profile) error {
+func (s *gameListService) ImportGameList(ctx context.Context, nickname string, entries []entity.ListEntry, dryRun bool) (*entity.ImportResult, error) {
+	// Games and list types are matched and listed in one transaction, so items deleted
+	// meanwhile fail the import rather than leave it partial. The event is published
+	// once the list is committed.
+	var result *entity.ImportResult
+	err := s.repo.WithTx(ctx, func(repo repository.GamelistRepository) error {
+		games, err := repo.GetGameTitles(ctx)
+		if err != nil {
+			return err
+		}
+		listTypes, err := repo.GetAllListTypes(ctx, entity.Cursor{}, 0)
+		if err != nil {
+			return err
+		}
+
+		typeIDs := make(map[string]uint64, len(listTypes))
+		for i := range listTypes {
+			typeIDs[strings.ToLower(listTypes[i].Name)] = listTypes[i].ID
+		}
+
+		// A retried transaction starts over
+		result = &entity.ImportResult{Review: []entity.ImportEntry{}}
+		var requests []entity.GameListRequest
+		for _, entry := range MatchListEntries(entries, games, typeIDs) {
+			if entry.Status != entity.ImportMatched {
+				result.Review = append(result.Review, entry)
+				continue
+			}
+			requests = append(requests, entity.GameListRequest{GameId: entry.GameID, ListType: entry.ListTypeID})
+		}
+		result.Imported = len(requests)
+
+		if dryRun || len(requests) == 0 {
+			return nil
+		}
+		return repo.ListGames(ctx, nickname, requests)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !dryRun && result.Imported > 0 {
+		s.publish(entity.Event{Type: entity.EventListImported, Nickname: nickname, Imported: result.Imported})
+	}
+
+	return result, nil
+}
+
+func (s *gameListService) ExportGameList(ctx context.Context, nickname string) ([]entity.ListEntry, error) {
+	games, err := s.repo.GetUserGameList(ctx, nickname, entity.Cursor{}, 0)
+	if err != nil {
+		return nil, err
+	}
+	listTypes, err := s.repo.GetAllListTypes(ctx, entity.Cursor{}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	typeNames := make(map[uint64]string, len(listTypes))
+	for i := range listTypes {
+		typeNames[listTypes[i].ID] = listTypes[i].Name
+	}
+
+	entries := make([]entity.ListEntry, len(games))
+	for i := range games {
+		entries[i] = entity.ListEntry{
+			GameID:       games[i].ID,
+			Title:        games[i].Name,
+			YearReleased: games[i].YearReleased,
+			ListType:     typeNames[games[i].ListTypeID],
+		}
+	}
+
+	return entries, nil
+}
+
+func (s *gameListService) SaveGenre(ctx context.Context, genre *entity.Genre) error {
+	return s.repo.SaveGenre(ctx, genre)
+}
+
+func (s *gameListService) UpdateGenre(ctx context.Context, genre *entity.Genre) error {
+	return s.repo.UpdateGenre(ctx, genre)
+}
+
+func (s *gameListService) DeleteGenre(ctx context.Context, id uint64, reassignTo uint64) error {
+	return s.repo.DeleteGenre(ctx, id, reassignTo)
+}
+
+func (s *gameListService) RestoreGenre(ctx context.Context, id uint64) error {
+	return s.repo.RestoreGenre(ctx, id)
+}
+
+func (s *gameListService) GetAllGenres(ctx context.Context, page entity.PageRequest) (*entity.Page[entity.Genre], error) {
+	return getPage(ctx, s.limits, entity.PageCatalog, page, s.repo.GetAllGenres, func(genre *entity.Genre) entity.Cursor {
+		return entity.Cursor{ID: genre.ID}
+	})
+}
+
+func (s *gameListService) SavePlatform(ctx context.Context, platform *entity.Platform) error {
+	return s.repo.SavePlatform(ctx, platform)
+}
+
+func (s *gameListService) UpdatePlatform(ctx context.Context, platform *entity.Platform) error {
+	return s.repo.UpdatePlatform(ctx, platform)
+}
+
+func (s *gameListService) DeletePlatform(ctx context.Context, id uint64, reassignTo uint64) error {
+	return s.repo.DeletePlatform(ctx, id, reassignTo)
+}
+
+func (s *gameListService) RestorePlatform(ctx context.Context, id uint64) error {
+	return s.repo.RestorePlatform(ctx, id)
+}
+
+func (s *gameListService) GetAllPlatforms(ctx context.Context, page entity.PageRequest) (*entity.Page[entity.Platform], error) {
+	return getPage(ctx, s.limits, entity.PageCatalog, page, s.repo.GetAllPlatforms, func(platform *entity.Platform) entity.Cursor {
+		return entity.Cursor{ID: platform.ID}
+	})
+}
+
+func (s *gameListService) CreateProfile(ctx context.Context, profile entity.Profile) error {
 	// Encrypting password
 	hash, err := bcrypt.GenerateFromPassword([]byte(profile.Password), 10)
 	if err != nil {
@@ -114,10 +412,10 @@ func (s *gameListService) CreateProfile(profile entity.Profile) error {
 
 	profile.Password = string(hash)
 
-	return s.repo.CreateProfile(profile)
+	return s.repo.CreateProfile(ctx, profile)
 }
 
-func (s *gameListService) SaveProfile(profile entity.Profile) error {
+func (s *gameListService) SaveProfile(ctx context.Context, profile entity.Profile) error {
 	// Generate hash for new password if it was changed
 	if len(profile.Password) > 0 {
 		hash, err := bcrypt.GenerateFromPassword([]byte(profile.Password), 10)
@@ -128,15 +426,30 @@ func (s *gameListService) SaveProfile(profile entity.Profile) error {
 		profile.Password = string(hash)
 	}
 
-	return s.repo.SaveProfile(profile)
+	if err := s.repo.SaveProfile(ctx, profile); err != nil {
+		return err
+	}
+
+	s.publish(entity.Event{Type: entity.EventProfileUpdated, Nickname: profile.Nickname})
+	return nil
+}
+
+func (s *gameListService) GetAllProfiles(ctx context.Context, page entity.PageRequest) (*entity.Page[entity.ProfileInfo], error) {
+	return getPage(ctx, s.limits, entity.PageProfiles, page, s.repo.GetAllProfiles, func(profile *entity.ProfileInfo) entity.Cursor {
+		return entity.Cursor{ID: profile.ID}
+	})
+}
+
+func (s *gameListService) GetProfileInfo(ctx context.Context, nickname string) (*entity.ProfileInfo, error) {
+	return s.repo.GetProfileInfo(ctx, nickname)
 }
 
-func (s *gameListService) GetAllProfiles() ([]entity.ProfileInfo, error) {
-	return s.repo.GetAllProfiles()
+func (s *gameListService) GetSocialsOfProfiles(ctx context.Context, profileIDs []uint64) (map[uint64][]entity.Social, error) {
+	return s.repo.GetSocialsOfProfiles(ctx, profileIDs)
 }
 
-func (s *gameListService) CheckLogin(login entity.LoginProfile) (*entity.Profile, error) {
-	profile, err := s.repo.GetProfile(entity.ProfileCreds{
+func (s *gameListService) CheckLogin(ctx context.Context, login entity.LoginProfile) (*entity.Profile, error) {
+	profile, err := s.repo.GetProfile(ctx, entity.ProfileCreds{
 		Nickname: login.Nickname,
 		Email:    login.Email,
 	})
@@ -146,56 +459,122 @@ func (s *gameListService) CheckLogin(login entity.LoginProfile) (*entity.Profile
 
 	err = bcrypt.CompareHashAndPassword([]byte(profile.Password), []byte(login.Password))
 	if err != nil {
-		return nil, utilErrs.New(utilErrs.Unauthorized, err, "incorrect password")
+		return nil, utilErrs.New(utilErrs.Unauthorized, err, "incorrect password").WithReason(utilErrs.ReasonInvalidCredentials)
 	}
 
 	return profile, nil
 }
 
-func (s *gameListService) SaveSocialType(socialType entity.SocialType) error {
-	return s.repo.SaveSocialType(socialType)
+func (s *gameListService) SaveSocialType(ctx context.Context, socialType *entity.SocialType) error {
+	return s.repo.SaveSocialType(ctx, socialType)
 }
 
-func (s *gameListService) GetAllSocialTypes() ([]entity.SocialType, error) {
-	return s.repo.GetAllSocialTypes()
+func (s *gameListService) UpdateSocialType(ctx context.Context, socialType *entity.SocialType) error {
+	return s.repo.UpdateSocialType(ctx, socialType)
 }
 
-// gRPC
-//nolint Will be remade
-func (s *gameListService) ScrapeGames() {
-	// Connection
-	opts := []grpc.DialOption{grpc.WithInsecure(), grpc.WithBlock()}
+func (s *gameListService) DeleteSocialType(ctx context.Context, id uint64, reassignTo uint64) error {
+	return s.repo.DeleteSocialType(ctx, id, reassignTo)
+}
 
-	conn, err := grpc.Dial(s.scraperGRPCAddress+":8888", opts...)
-	if err != nil {
-		log.Printf("failed to dial: %v", err)
-		return
+func (s *gameListService) RestoreSocialType(ctx context.Context, id uint64) error {
+	return s.repo.RestoreSocialType(ctx, id)
+}
+
+func (s *gameListService) GetAllSocialTypes(ctx context.Context, page entity.PageRequest) (*entity.Page[entity.SocialType], error) {
+	return getPage(ctx, s.limits, entity.PageCatalog, page, s.repo.GetAllSocialTypes, func(socialType *entity.SocialType) entity.Cursor {
+		return entity.Cursor{ID: socialType.ID}
+	})
+}
+
+func (s *gameListService) ScrapeGames(ctx context.Context) (stats *entity.IngestStats, err error) {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.ScrapeGames")
+	defer func() { tracing.End(span, err) }()
+
+	if len(s.sources) == 0 {
+		return nil, utilErrs.New(utilErrs.Internal, nil, "no game sources are configured")
 	}
-	defer conn.Close()
-	client := pb.NewGameScrapeClient(conn)
 
-	// Getting games from scraper
-	stream, err := client.ScrapeGames(context.Background(), &pb.Empty{})
+	var (
+		total  entity.IngestStats
+		failed []string
+		cause  error
+	)
+	for _, source := range s.sources {
+		sourceStats, err := s.IngestGames(ctx, source)
+		if sourceStats != nil {
+			total.Merge(*sourceStats)
+		}
+		// Sources may stop without an error when they're canceled
+		if ctx.Err() != nil {
+			return &total, ctx.Err()
+		}
+		if err != nil {
+			failed = append(failed, source.Name())
+			cause = err
+		}
+	}
+
+	if len(failed) > 0 {
+		return &total, utilErrs.Newf(utilErrs.Internal, cause, "failed to ingest games from %s", strings.Join(failed, ", "))
+	}
+
+	return &total, nil
+}
+
+func (s *gameListService) IngestGames(ctx context.Context, source GameSource) (*entity.IngestStats, error) {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.IngestGames", trace.WithAttributes(attribute.String("source", source.Name())))
+	stats := &entity.IngestStats{}
+	t := time.Now()
+	err := source.Fetch(ctx, func(game entity.SourcedGame) error {
+		s.ingestGame(ctx, stats, game)
+		return nil
+	})
+	span.SetAttributes(
+		attribute.Int64("inserted", int64(stats.Inserted)),
+		attribute.Int64("updated", int64(stats.Updated)),
+		attribute.Int64("skipped", int64(stats.Skipped)),
+		attribute.Int64("failed", int64(stats.Failed)),
+	)
+	tracing.End(span, err)
+
+	ingested := metrics.IngestedGames.MustCurryWith(prometheus.Labels{"source": source.Name()})
+	ingested.WithLabelValues("inserted").Add(float64(stats.Inserted))
+	ingested.WithLabelValues("updated").Add(float64(stats.Updated))
+	ingested.WithLabelValues("skipped").Add(float64(stats.Skipped))
+	ingested.WithLabelValues("failed").Add(float64(stats.Failed))
+	ingestLog.InfoContext(ctx, "ingestion finished", "source", source.Name(), "elapsed", time.Since(t),
+		"inserted", stats.Inserted, "updated", stats.Updated, "skipped", stats.Skipped, "failed", stats.Failed)
+
+	return stats, err
+}
+
+// ingestGame upserts the game by its source id and counts the result in stats.
+// A failed game doesn't stop the ingestion, so it's only logged.
+func (s *gameListService) ingestGame(ctx context.Context, stats *entity.IngestStats, game entity.SourcedGame) {
+	result, err := s.repo.UpsertGame(ctx, game, s.priority)
 	if err != nil {
-		log.Printf("<ScrapeGames>: failed to set up stream: %v", err)
+		stats.Failed++
+		ingestLog.WarnContext(ctx, "failed to ingest game", "game", game.Game.Name, "source", game.Source, "error", err, "cause", errors.Unwrap(err))
 		return
 	}
 
-	counter := 1
-	t := time.Now()
-	for {
-		log.Printf("Adding game: %d", counter)
-		game, err := stream.Recv()
-
-		if err == io.EOF {
-			break
-		} else if err != nil {
-			log.Printf("rpc error: %v", err)
-			return
-		}
+	stats.Add(result)
+}
 
-		s.repo.SaveGame(game.ConvertToEntity())
-		counter++
+// getPage takes a page of the query with the limit of the collection
+func getPage[T any](ctx context.Context, limits entity.PageLimits, collection string, page entity.PageRequest,
+	query func(ctx context.Context, cursor entity.Cursor, limit int) ([]T, error), cursor func(*T) entity.Cursor) (*entity.Page[T], error) {
+	after, err := entity.DecodeCursor(page.Cursor)
+	if err != nil {
+		return nil, err
 	}
-	log.Printf("Time elapsed: %v", time.Since(t))
+
+	limit := limits.Clamp(collection, page.Limit)
+	items, err := query(ctx, after, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return entity.NewPage(items, limit, cursor), nil
 }
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/br3w0r/gamelist-backend/entity"
+	"github.com/br3w0r/gamelist-backend/repository"
+	utilLogger "github.com/br3w0r/gamelist-backend/util/logger"
+)
+
+var healthLog = utilLogger.For(utilLogger.SubsystemHealth)
+
+const (
+	HEALTH_CHECK_DATABASE   = "database"
+	HEALTH_CHECK_MIGRATIONS = "migrations"
+	HEALTH_CHECK_SCRAPER    = "scraper"
+
+	DEFAULT_HEALTH_CHECK_TIMEOUT = 2 * time.Second
+)
+
+type HealthConfig struct {
+	// SchemaVersion is the version of the newest migration the server needs
+	SchemaVersion int64
+	// CheckTimeout limits each check of readiness
+	CheckTimeout time.Duration
+	// Scraper is checked for readiness if it's set
+	Scraper ScraperClient
+}
+
+type HealthService interface {
+	// Liveness tells that the process is up, it doesn't check dependencies
+	Liveness() entity.HealthReport
+	// Readiness checks the database, its migrations and the scraper if it's configured.
+	// The server isn't ready while it's draining.
+	Readiness(ctx context.Context) entity.HealthReport
+	// Drain marks the server as not ready, so no new traffic is sent to it before it stops
+	Drain()
+}
+
+type healthService struct {
+	repo      repository.GamelistRepository
+	conf      HealthConfig
+	startedAt time.Time
+	draining  atomic.Bool
+}
+
+func NewHealthService(repo repository.GamelistRepository, conf HealthConfig) HealthService {
+	if conf.CheckTimeout <= 0 {
+		conf.CheckTimeout = DEFAULT_HEALTH_CHECK_TIMEOUT
+	}
+
+	return &healthService{
+		repo:      repo,
+		conf:      conf,
+		startedAt: time.Now(),
+	}
+}
+
+func (s *healthService) Liveness() entity.HealthReport {
+	return entity.HealthReport{Status: entity.HealthOK, StartedAt: s.startedAt}
+}
+
+func (s *healthService) Readiness(ctx context.Context) entity.HealthReport {
+	checks := map[string]func(context.Context) (map[string]interface{}, error){
+		HEALTH_CHECK_DATABASE:   s.checkDatabase,
+		HEALTH_CHECK_MIGRATIONS: s.checkMigrations,
+	}
+	if s.conf.Scraper != nil {
+		checks[HEALTH_CHECK_SCRAPER] = s.checkScraper
+	}
+
+	report := entity.HealthReport{
+		Status:    entity.HealthOK,
+		StartedAt: s.startedAt,
+		Checks:    make(map[string]entity.HealthCheck, len(checks)),
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check func(context.Context) (map[string]interface{}, error)) {
+			defer wg.Done()
+			result, err := runHealthCheck(ctx, s.conf.CheckTimeout, check)
+			if err != nil {
+				healthLog.WarnContext(ctx, "health check failed", "check", name, "error", err, "cause", errors.Unwrap(err))
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = result
+			if result.Status != entity.HealthOK {
+				report.Status = entity.HealthFailed
+			}
+		}(name, check)
+	}
+	wg.Wait()
+
+	// Checks are still reported to tell why a draining server would be unready anyway
+	if s.draining.Load() {
+		report.Status = entity.HealthDraining
+	}
+
+	return report
+}
+
+func (s *healthService) Drain() {
+	s.draining.Store(true)
+}
+
+// runHealthCheck doesn't report causes of errors, which may have addresses of dependencies,
+// they're only logged
+func runHealthCheck(ctx context.Context, timeout time.Duration, check func(context.Context) (map[string]interface{}, error)) (entity.HealthCheck, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	details, err := check(ctx)
+	result := entity.HealthCheck{
+		Status:     entity.HealthOK,
+		DurationMs: float64(time.Since(start).Microseconds()) / 1000,
+		Details:    details,
+	}
+	if err != nil {
+		result.Status = entity.HealthFailed
+		result.Error = err.Error()
+	}
+
+	return result, err
+}
+
+func (s *healthService) checkDatabase(ctx context.Context) (map[string]interface{}, error) {
+	return nil, s.repo.Ping(ctx)
+}
+
+// checkMigrations fails if migrations the server needs aren't applied. Newer ones are fine,
+// so the previous version keeps serving while a new one is rolled out.
+func (s *healthService) checkMigrations(ctx context.Context) (map[string]interface{}, error) {
+	applied, err := s.repo.SchemaVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	details := map[string]interface{}{
+		"applied":  applied,
+		"expected": s.conf.SchemaVersion,
+	}
+	if applied < s.conf.SchemaVersion {
+		return details, fmt.Errorf("migrations up to %d aren't applied", s.conf.SchemaVersion)
+	}
+
+	return details, nil
+}
+
+func (s *healthService) checkScraper(ctx context.Context) (map[string]interface{}, error) {
+	return nil, s.conf.Scraper.Ping(ctx)
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"math/rand"
 	"strconv"
 	"time"
@@ -12,11 +13,11 @@ import (
 )
 
 type JWTService interface {
-	GenerateTokens(user string) (*entity.TokenPair, error)
+	GenerateTokens(ctx context.Context, user string) (*entity.TokenPair, error)
 	Authenticate(tokenString string) (string, error)
-	RefreshTokens(refreshToken string) (*entity.TokenPair, error)
-	RevokeRefreshToken(refreshToken string) error
-	DeleteAllUserRefreshTokens(nickname string) error
+	RefreshTokens(ctx context.Context, refreshToken string) (*entity.TokenPair, error)
+	RevokeRefreshToken(ctx context.Context, refreshToken string) error
+	DeleteAllUserRefreshTokens(ctx context.Context, nickname string) error
 }
 
 type jwtService struct {
@@ -55,7 +56,12 @@ func NewJWTService(repo repository.GamelistRepository) JWTService {
 	}
 }
 
-func (s *jwtService) GenerateTokens(user string) (*entity.TokenPair, error) {
+func (s *jwtService) GenerateTokens(ctx context.Context, user string) (*entity.TokenPair, error) {
+	return s.generateTokens(ctx, s.repo, user)
+}
+
+// generateTokens saves the refresh token of the pair with repo
+func (s *jwtService) generateTokens(ctx context.Context, repo repository.GamelistRepository, user string) (*entity.TokenPair, error) {
 	iat := time.Now().Unix()
 	exp := iat + 3900
 
@@ -90,7 +96,7 @@ func (s *jwtService) GenerateTokens(user string) (*entity.TokenPair, error) {
 		return nil, utilErrs.New(utilErrs.Internal, err, "failed to generate refresh token string")
 	}
 
-	err = s.repo.SaveRefreshToken(user, refreshTokenString)
+	err = repo.SaveRefreshToken(ctx, user, refreshTokenString)
 	if err != nil {
 		return nil, err
 	}
@@ -105,23 +111,28 @@ func (s *jwtService) Authenticate(tokenString string) (string, error) {
 	return s.validateToken(tokenString, false)
 }
 
-func (s *jwtService) RefreshTokens(refreshToken string) (*entity.TokenPair, error) {
+func (s *jwtService) RefreshTokens(ctx context.Context, refreshToken string) (*entity.TokenPair, error) {
 	user, err := s.validateToken(refreshToken, true)
 	if err != nil {
 		return nil, err
 	}
 
-	err = s.repo.FindRefreshToken(user, refreshToken)
-	if err != nil {
-		return nil, err
-	}
+	// The old token is deleted with saving of the new pair, so a failure in between
+	// doesn't leave both of them valid
+	var tokens *entity.TokenPair
+	err = s.repo.WithTx(ctx, func(repo repository.GamelistRepository) error {
+		if err := repo.FindRefreshToken(ctx, user, refreshToken); err != nil {
+			return err
+		}
 
-	tokens, err := s.GenerateTokens(user)
-	if err != nil {
-		return nil, err
-	}
+		pair, err := s.generateTokens(ctx, repo, user)
+		if err != nil {
+			return err
+		}
+		tokens = pair
 
-	err = s.repo.DeleteRefreshToken(refreshToken)
+		return repo.DeleteRefreshToken(ctx, refreshToken)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -129,21 +140,27 @@ func (s *jwtService) RefreshTokens(refreshToken string) (*entity.TokenPair, erro
 	return tokens, nil
 }
 
-func (s *jwtService) RevokeRefreshToken(refreshToken string) error {
-	return s.repo.DeleteRefreshToken(refreshToken)
+func (s *jwtService) RevokeRefreshToken(ctx context.Context, refreshToken string) error {
+	err := s.repo.DeleteRefreshToken(ctx, refreshToken)
+	// Revoking is idempotent, tokens which are already revoked stay so
+	if utilErr, ok := err.(*utilErrs.Error); ok && utilErr.Code() == utilErrs.NotFound {
+		return nil
+	}
+
+	return err
 }
 
-func (s *jwtService) DeleteAllUserRefreshTokens(nickname string) error {
-	return s.repo.DeleteAllUserRefreshTokens(nickname)
+func (s *jwtService) DeleteAllUserRefreshTokens(ctx context.Context, nickname string) error {
+	return s.repo.DeleteAllUserRefreshTokens(ctx, nickname)
 }
 
 func (s *jwtService) validateToken(tokenString string, isRefresh bool) (string, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, utilErrs.Newf(utilErrs.BadInput, nil, "invalid signing method: %v", token.Header["alg"])
+			return nil, utilErrs.Newf(utilErrs.BadInput, nil, "invalid signing method: %v", token.Header["alg"]).WithReason(utilErrs.ReasonTokenInvalid)
 		}
 		if err := token.Claims.Valid(); err != nil {
-			return nil, utilErrs.New(utilErrs.BadInput, err, "failed to validate claims")
+			return nil, utilErrs.New(utilErrs.BadInput, err, "failed to validate claims").WithReason(utilErrs.ReasonTokenInvalid)
 		}
 
 		claims := token.Claims.(jwt.MapClaims)
@@ -168,7 +185,7 @@ func (s *jwtService) validateToken(tokenString string, isRefresh bool) (string,
 	}
 
 	if !token.Valid {
-		return "", utilErrs.New(utilErrs.Unauthorized, nil, "token validation failed")
+		return "", utilErrs.New(utilErrs.Unauthorized, nil, "token validation failed").WithReason(utilErrs.ReasonTokenInvalid)
 	}
 
 	return "", err
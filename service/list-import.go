@@ -0,0 +1,325 @@
+package service
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/br3w0r/gamelist-backend/entity"
+	"github.com/br3w0r/gamelist-backend/helpers"
+	utilErrs "github.com/br3w0r/gamelist-backend/util/errors"
+)
+
+const (
+	// Fuzzy matches scored lower aren't offered as candidates
+	IMPORT_MATCH_THRESHOLD  = 0.75
+	IMPORT_CANDIDATES_LIMIT = 5
+	IMPORT_ENTRIES_LIMIT    = 5000
+)
+
+var (
+	// Statuses of other trackers mapped to names of list types
+	listTypeAliases = map[string]string{
+		"completed":     "played",
+		"dropped":       "played",
+		"watching":      "playing",
+		"reading":       "playing",
+		"on-hold":       "playing",
+		"plan to watch": "want to play",
+		"plan to read":  "want to play",
+		"plan to play":  "want to play",
+	}
+
+	listCSVHeader = []string{"game_id", "title", "year_released", "list_type"}
+)
+
+// MatchListEntries matches titles of the entries against the games. An entry is matched
+// by its game id or by a single game with the same normalized name and release year.
+// Other entries get up to IMPORT_CANDIDATES_LIMIT similar games to choose from.
+// listTypes are ids of list types by their lowercase names.
+func MatchListEntries(entries []entity.ListEntry, games []entity.GameTitle, listTypes map[string]uint64) []entity.ImportEntry {
+	byID := make(map[uint64]*entity.GameTitle, len(games))
+	byName := make(map[string][]*entity.GameTitle, len(games))
+	normalized := make([]string, len(games))
+	for i := range games {
+		normalized[i] = helpers.NormalizeGameName(games[i].Name)
+		byID[games[i].ID] = &games[i]
+		byName[normalized[i]] = append(byName[normalized[i]], &games[i])
+	}
+
+	result := make([]entity.ImportEntry, len(entries))
+	for i, entry := range entries {
+		result[i] = entity.ImportEntry{ListEntry: entry}
+		match := &result[i]
+
+		listType, ok := resolveListType(entry.ListType, listTypes)
+		if !ok {
+			match.Status = entity.ImportInvalid
+			match.Error = "unknown list type \"" + entry.ListType + "\""
+			continue
+		}
+		match.ListTypeID = listType
+
+		if entry.GameID != 0 {
+			if game, ok := byID[entry.GameID]; ok {
+				match.Status = entity.ImportMatched
+				match.Title = game.Name
+			} else {
+				match.Status = entity.ImportUnmatched
+				match.Error = "no game with id " + strconv.FormatUint(entry.GameID, 10)
+			}
+			continue
+		}
+
+		name := helpers.NormalizeGameName(entry.Title)
+		var exact []entity.GameTitle
+		for _, game := range byName[name] {
+			if sameRelease(entry.YearReleased, game.YearReleased) {
+				exact = append(exact, *game)
+			}
+		}
+		if len(exact) == 1 {
+			match.Status = entity.ImportMatched
+			match.GameID = exact[0].ID
+			continue
+		}
+		if len(exact) > 1 {
+			match.Status = entity.ImportAmbiguous
+			match.Candidates = exact
+			continue
+		}
+
+		match.Candidates = similarGames(name, entry.YearReleased, games, normalized)
+		if len(match.Candidates) > 0 {
+			match.Status = entity.ImportAmbiguous
+		} else {
+			match.Status = entity.ImportUnmatched
+		}
+	}
+
+	return result
+}
+
+func resolveListType(name string, listTypes map[string]uint64) (uint64, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	id, ok := listTypes[name]
+	if !ok {
+		id, ok = listTypes[listTypeAliases[name]]
+	}
+
+	return id, ok
+}
+
+// sameRelease is true if any of the years is unknown or they differ by a year at most
+func sameRelease(a uint16, b uint16) bool {
+	return a == 0 || b == 0 || a == b || a+1 == b || b+1 == a
+}
+
+func similarGames(name string, year uint16, games []entity.GameTitle, normalized []string) []entity.GameTitle {
+	type scored struct {
+		game  entity.GameTitle
+		score float64
+	}
+
+	var similar []scored
+	for i := range games {
+		if !sameRelease(year, games[i].YearReleased) {
+			continue
+		}
+		if score := helpers.NameSimilarity(name, normalized[i]); score >= IMPORT_MATCH_THRESHOLD {
+			similar = append(similar, scored{games[i], score})
+		}
+	}
+
+	sort.SliceStable(similar, func(i, j int) bool {
+		return similar[i].score > similar[j].score
+	})
+	if len(similar) > IMPORT_CANDIDATES_LIMIT {
+		similar = similar[:IMPORT_CANDIDATES_LIMIT]
+	}
+
+	candidates := make([]entity.GameTitle, len(similar))
+	for i := range similar {
+		candidates[i] = similar[i].game
+	}
+
+	return candidates
+}
+
+// DecodeListEntries reads a list file of the format: a JSON array, a CSV file with
+// a header or a MyAnimeList-like XML export
+func DecodeListEntries(format string, r io.Reader) ([]entity.ListEntry, error) {
+	var (
+		entries []entity.ListEntry
+		err     error
+	)
+
+	switch format {
+	case entity.ListFormatJSON:
+		err = json.NewDecoder(r).Decode(&entries)
+		if err != nil {
+			return nil, utilErrs.JSONParseErr(err)
+		}
+	case entity.ListFormatCSV:
+		entries, err = decodeListCSV(r)
+	case entity.ListFormatXML:
+		entries, err = decodeListXML(r)
+	default:
+		return nil, utilErrs.Newf(utilErrs.BadInput, nil, "unsupported list format \"%s\": expected csv, json or xml", format).WithReason(utilErrs.ReasonInvalidListFile)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) > IMPORT_ENTRIES_LIMIT {
+		return nil, utilErrs.Newf(utilErrs.BadInput, nil, "too many entries: %d is the limit", IMPORT_ENTRIES_LIMIT).WithReason(utilErrs.ReasonInvalidListFile)
+	}
+	for i := range entries {
+		if entries[i].GameID == 0 && strings.TrimSpace(entries[i].Title) == "" {
+			return nil, utilErrs.Newf(utilErrs.BadInput, nil, "entry %d has neither game id nor title", i+1).WithReason(utilErrs.ReasonInvalidListFile)
+		}
+	}
+
+	return entries, nil
+}
+
+func decodeListCSV(r io.Reader) ([]entity.ListEntry, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, utilErrs.New(utilErrs.BadInput, err, "failed to read list csv").WithReason(utilErrs.ReasonInvalidListFile)
+	}
+	if len(records) == 0 {
+		return nil, utilErrs.New(utilErrs.BadInput, nil, "list csv has no header").WithReason(utilErrs.ReasonInvalidListFile)
+	}
+
+	columns := make(map[string]int, len(records[0]))
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	// Spreadsheets often call it a name
+	if _, ok := columns["title"]; !ok {
+		if i, ok := columns["name"]; ok {
+			columns["title"] = i
+		}
+	}
+
+	entries := make([]entity.ListEntry, 0, len(records)-1)
+	for line, record := range records[1:] {
+		column := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		entry := entity.ListEntry{
+			Title:    column("title"),
+			ListType: column("list_type"),
+		}
+		if id := column("game_id"); id != "" {
+			entry.GameID, err = strconv.ParseUint(id, 10, 64)
+			if err != nil {
+				return nil, utilErrs.Newf(utilErrs.BadInput, err, "wrong game_id on line %d", line+2).WithReason(utilErrs.ReasonInvalidListFile)
+			}
+		}
+		if year := column("year_released"); year != "" {
+			parsed, err := strconv.ParseUint(year, 10, 16)
+			if err != nil {
+				return nil, utilErrs.Newf(utilErrs.BadInput, err, "wrong year_released on line %d", line+2).WithReason(utilErrs.ReasonInvalidListFile)
+			}
+			entry.YearReleased = uint16(parsed)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// decodeListXML takes entries from <game>, <anime> and <manga> elements at any depth
+func decodeListXML(r io.Reader) ([]entity.ListEntry, error) {
+	dec := xml.NewDecoder(r)
+
+	var entries []entity.ListEntry
+	for {
+		token, err := dec.Token()
+		if err == io.EOF {
+			return entries, nil
+		} else if err != nil {
+			return nil, utilErrs.New(utilErrs.BadInput, err, "failed to parse list xml").WithReason(utilErrs.ReasonInvalidListFile)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch start.Name.Local {
+		case "game", "anime", "manga":
+			var entry entity.ListEntry
+			if err := dec.DecodeElement(&entry, &start); err != nil {
+				return nil, utilErrs.New(utilErrs.BadInput, err, "failed to parse list xml entry").WithReason(utilErrs.ReasonInvalidListFile)
+			}
+			entry.Title = strings.TrimSpace(entry.Title)
+			entries = append(entries, entry)
+		}
+	}
+}
+
+type listXML struct {
+	XMLName xml.Name `xml:"mygamelist"`
+	Info    struct {
+		UserName   string `xml:"user_name"`
+		TotalGames int    `xml:"user_total_games"`
+	} `xml:"myinfo"`
+	Games []entity.ListEntry `xml:"game"`
+}
+
+// EncodeListEntries writes the user's list in the format DecodeListEntries reads
+func EncodeListEntries(format string, w io.Writer, nickname string, entries []entity.ListEntry) error {
+	switch format {
+	case entity.ListFormatJSON:
+		if entries == nil {
+			entries = []entity.ListEntry{}
+		}
+		return json.NewEncoder(w).Encode(entries)
+	case entity.ListFormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(listCSVHeader); err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			var year string
+			if entry.YearReleased != 0 {
+				year = strconv.FormatUint(uint64(entry.YearReleased), 10)
+			}
+			record := []string{strconv.FormatUint(entry.GameID, 10), entry.Title, year, entry.ListType}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	case entity.ListFormatXML:
+		list := listXML{Games: entries}
+		list.Info.UserName = nickname
+		list.Info.TotalGames = len(entries)
+
+		if _, err := io.WriteString(w, xml.Header); err != nil {
+			return err
+		}
+		enc := xml.NewEncoder(w)
+		enc.Indent("", "  ")
+		return enc.Encode(&list)
+	}
+
+	return utilErrs.Newf(utilErrs.BadInput, nil, "unsupported list format \"%s\": expected csv, json or xml", format).WithReason(utilErrs.ReasonInvalidListFile)
+}
@@ -5,9 +5,12 @@
 package service
 
 import (
+	context "context"
 	reflect "reflect"
+	time "time"
 
 	entity "github.com/br3w0r/gamelist-backend/entity"
+	repository "github.com/br3w0r/gamelist-backend/repository"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -34,335 +37,983 @@ func (m *MockGamelistRepository) EXPECT() *MockGamelistRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AbortStaleScrapeJobs mocks base method.
+func (m *MockGamelistRepository) AbortStaleScrapeJobs(arg0 context.Context, arg1 time.Time, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AbortStaleScrapeJobs", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AbortStaleScrapeJobs indicates an expected call of AbortStaleScrapeJobs.
+func (mr *MockGamelistRepositoryMockRecorder) AbortStaleScrapeJobs(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AbortStaleScrapeJobs", reflect.TypeOf((*MockGamelistRepository)(nil).AbortStaleScrapeJobs), arg0, arg1, arg2)
+}
+
+// BeatScrapeJob mocks base method.
+func (m *MockGamelistRepository) BeatScrapeJob(arg0 context.Context, arg1 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeatScrapeJob", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BeatScrapeJob indicates an expected call of BeatScrapeJob.
+func (mr *MockGamelistRepositoryMockRecorder) BeatScrapeJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeatScrapeJob", reflect.TypeOf((*MockGamelistRepository)(nil).BeatScrapeJob), arg0, arg1)
+}
+
+// CancelProfileDeletion mocks base method.
+func (m *MockGamelistRepository) CancelProfileDeletion(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelProfileDeletion", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelProfileDeletion indicates an expected call of CancelProfileDeletion.
+func (mr *MockGamelistRepositoryMockRecorder) CancelProfileDeletion(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelProfileDeletion", reflect.TypeOf((*MockGamelistRepository)(nil).CancelProfileDeletion), arg0, arg1)
+}
+
+// Close mocks base method.
+func (m *MockGamelistRepository) Close() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockGamelistRepositoryMockRecorder) Close() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockGamelistRepository)(nil).Close))
+}
+
 // CreateListType mocks base method.
-func (m *MockGamelistRepository) CreateListType(arg0 entity.ListType) error {
+func (m *MockGamelistRepository) CreateListType(arg0 context.Context, arg1 *entity.ListType) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateListType", arg0)
+	ret := m.ctrl.Call(m, "CreateListType", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CreateListType indicates an expected call of CreateListType.
-func (mr *MockGamelistRepositoryMockRecorder) CreateListType(arg0 interface{}) *gomock.Call {
+func (mr *MockGamelistRepositoryMockRecorder) CreateListType(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateListType", reflect.TypeOf((*MockGamelistRepository)(nil).CreateListType), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateListType", reflect.TypeOf((*MockGamelistRepository)(nil).CreateListType), arg0, arg1)
 }
 
 // CreateProfile mocks base method.
-func (m *MockGamelistRepository) CreateProfile(arg0 entity.Profile) error {
+func (m *MockGamelistRepository) CreateProfile(arg0 context.Context, arg1 entity.Profile) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateProfile", arg0)
+	ret := m.ctrl.Call(m, "CreateProfile", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CreateProfile indicates an expected call of CreateProfile.
-func (mr *MockGamelistRepositoryMockRecorder) CreateProfile(arg0 interface{}) *gomock.Call {
+func (mr *MockGamelistRepositoryMockRecorder) CreateProfile(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProfile", reflect.TypeOf((*MockGamelistRepository)(nil).CreateProfile), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProfile", reflect.TypeOf((*MockGamelistRepository)(nil).CreateProfile), arg0, arg1)
+}
+
+// CreateScrapeJob mocks base method.
+func (m *MockGamelistRepository) CreateScrapeJob(arg0 context.Context, arg1 *entity.ScrapeJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateScrapeJob", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateScrapeJob indicates an expected call of CreateScrapeJob.
+func (mr *MockGamelistRepositoryMockRecorder) CreateScrapeJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScrapeJob", reflect.TypeOf((*MockGamelistRepository)(nil).CreateScrapeJob), arg0, arg1)
 }
 
 // DeleteAllUserRefreshTokens mocks base method.
-func (m *MockGamelistRepository) DeleteAllUserRefreshTokens(arg0 string) error {
+func (m *MockGamelistRepository) DeleteAllUserRefreshTokens(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteAllUserRefreshTokens", arg0)
+	ret := m.ctrl.Call(m, "DeleteAllUserRefreshTokens", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteAllUserRefreshTokens indicates an expected call of DeleteAllUserRefreshTokens.
-func (mr *MockGamelistRepositoryMockRecorder) DeleteAllUserRefreshTokens(arg0 interface{}) *gomock.Call {
+func (mr *MockGamelistRepositoryMockRecorder) DeleteAllUserRefreshTokens(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAllUserRefreshTokens", reflect.TypeOf((*MockGamelistRepository)(nil).DeleteAllUserRefreshTokens), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAllUserRefreshTokens", reflect.TypeOf((*MockGamelistRepository)(nil).DeleteAllUserRefreshTokens), arg0, arg1)
+}
+
+// DeleteGame mocks base method.
+func (m *MockGamelistRepository) DeleteGame(arg0 context.Context, arg1, arg2 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGame", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteGame indicates an expected call of DeleteGame.
+func (mr *MockGamelistRepositoryMockRecorder) DeleteGame(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGame", reflect.TypeOf((*MockGamelistRepository)(nil).DeleteGame), arg0, arg1, arg2)
+}
+
+// DeleteGenre mocks base method.
+func (m *MockGamelistRepository) DeleteGenre(arg0 context.Context, arg1, arg2 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteGenre", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteGenre indicates an expected call of DeleteGenre.
+func (mr *MockGamelistRepositoryMockRecorder) DeleteGenre(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteGenre", reflect.TypeOf((*MockGamelistRepository)(nil).DeleteGenre), arg0, arg1, arg2)
+}
+
+// DeleteListType mocks base method.
+func (m *MockGamelistRepository) DeleteListType(arg0 context.Context, arg1, arg2 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteListType", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteListType indicates an expected call of DeleteListType.
+func (mr *MockGamelistRepositoryMockRecorder) DeleteListType(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteListType", reflect.TypeOf((*MockGamelistRepository)(nil).DeleteListType), arg0, arg1, arg2)
+}
+
+// DeletePlatform mocks base method.
+func (m *MockGamelistRepository) DeletePlatform(arg0 context.Context, arg1, arg2 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePlatform", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePlatform indicates an expected call of DeletePlatform.
+func (mr *MockGamelistRepositoryMockRecorder) DeletePlatform(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePlatform", reflect.TypeOf((*MockGamelistRepository)(nil).DeletePlatform), arg0, arg1, arg2)
 }
 
 // DeleteRefreshToken mocks base method.
-func (m *MockGamelistRepository) DeleteRefreshToken(arg0 string) error {
+func (m *MockGamelistRepository) DeleteRefreshToken(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteRefreshToken", arg0)
+	ret := m.ctrl.Call(m, "DeleteRefreshToken", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteRefreshToken indicates an expected call of DeleteRefreshToken.
-func (mr *MockGamelistRepositoryMockRecorder) DeleteRefreshToken(arg0 interface{}) *gomock.Call {
+func (mr *MockGamelistRepositoryMockRecorder) DeleteRefreshToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRefreshToken", reflect.TypeOf((*MockGamelistRepository)(nil).DeleteRefreshToken), arg0, arg1)
+}
+
+// DeleteSocialType mocks base method.
+func (m *MockGamelistRepository) DeleteSocialType(arg0 context.Context, arg1, arg2 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSocialType", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSocialType indicates an expected call of DeleteSocialType.
+func (mr *MockGamelistRepositoryMockRecorder) DeleteSocialType(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRefreshToken", reflect.TypeOf((*MockGamelistRepository)(nil).DeleteRefreshToken), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSocialType", reflect.TypeOf((*MockGamelistRepository)(nil).DeleteSocialType), arg0, arg1, arg2)
+}
+
+// DismissGameDuplicate mocks base method.
+func (m *MockGamelistRepository) DismissGameDuplicate(arg0 context.Context, arg1, arg2 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DismissGameDuplicate", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DismissGameDuplicate indicates an expected call of DismissGameDuplicate.
+func (mr *MockGamelistRepositoryMockRecorder) DismissGameDuplicate(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DismissGameDuplicate", reflect.TypeOf((*MockGamelistRepository)(nil).DismissGameDuplicate), arg0, arg1, arg2)
 }
 
 // FindRefreshToken mocks base method.
-func (m *MockGamelistRepository) FindRefreshToken(arg0, arg1 string) error {
+func (m *MockGamelistRepository) FindRefreshToken(arg0 context.Context, arg1, arg2 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindRefreshToken", arg0, arg1)
+	ret := m.ctrl.Call(m, "FindRefreshToken", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // FindRefreshToken indicates an expected call of FindRefreshToken.
-func (mr *MockGamelistRepositoryMockRecorder) FindRefreshToken(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockGamelistRepositoryMockRecorder) FindRefreshToken(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRefreshToken", reflect.TypeOf((*MockGamelistRepository)(nil).FindRefreshToken), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRefreshToken", reflect.TypeOf((*MockGamelistRepository)(nil).FindRefreshToken), arg0, arg1, arg2)
 }
 
-// GetAllGames mocks base method.
-func (m *MockGamelistRepository) GetAllGames() ([]entity.GameProperties, error) {
+// GetAccount mocks base method.
+func (m *MockGamelistRepository) GetAccount(arg0 context.Context, arg1 string) (*entity.Profile, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllGames")
-	ret0, _ := ret[0].([]entity.GameProperties)
+	ret := m.ctrl.Call(m, "GetAccount", arg0, arg1)
+	ret0, _ := ret[0].(*entity.Profile)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAllGames indicates an expected call of GetAllGames.
-func (mr *MockGamelistRepositoryMockRecorder) GetAllGames() *gomock.Call {
+// GetAccount indicates an expected call of GetAccount.
+func (mr *MockGamelistRepositoryMockRecorder) GetAccount(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllGames", reflect.TypeOf((*MockGamelistRepository)(nil).GetAllGames))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockGamelistRepository)(nil).GetAccount), arg0, arg1)
 }
 
 // GetAllGamesTyped mocks base method.
-func (m *MockGamelistRepository) GetAllGamesTyped(arg0 string, arg1 uint64, arg2 int) ([]entity.TypedGameListProperties, error) {
+func (m *MockGamelistRepository) GetAllGamesTyped(arg0 context.Context, arg1, arg2 string, arg3 entity.Cursor, arg4 int) ([]entity.TypedGameListProperties, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllGamesTyped", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "GetAllGamesTyped", arg0, arg1, arg2, arg3, arg4)
 	ret0, _ := ret[0].([]entity.TypedGameListProperties)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllGamesTyped indicates an expected call of GetAllGamesTyped.
-func (mr *MockGamelistRepositoryMockRecorder) GetAllGamesTyped(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockGamelistRepositoryMockRecorder) GetAllGamesTyped(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllGamesTyped", reflect.TypeOf((*MockGamelistRepository)(nil).GetAllGamesTyped), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllGamesTyped", reflect.TypeOf((*MockGamelistRepository)(nil).GetAllGamesTyped), arg0, arg1, arg2, arg3, arg4)
 }
 
 // GetAllGenres mocks base method.
-func (m *MockGamelistRepository) GetAllGenres() ([]entity.Genre, error) {
+func (m *MockGamelistRepository) GetAllGenres(arg0 context.Context, arg1 entity.Cursor, arg2 int) ([]entity.Genre, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllGenres")
+	ret := m.ctrl.Call(m, "GetAllGenres", arg0, arg1, arg2)
 	ret0, _ := ret[0].([]entity.Genre)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllGenres indicates an expected call of GetAllGenres.
-func (mr *MockGamelistRepositoryMockRecorder) GetAllGenres() *gomock.Call {
+func (mr *MockGamelistRepositoryMockRecorder) GetAllGenres(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllGenres", reflect.TypeOf((*MockGamelistRepository)(nil).GetAllGenres))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllGenres", reflect.TypeOf((*MockGamelistRepository)(nil).GetAllGenres), arg0, arg1, arg2)
 }
 
 // GetAllListTypes mocks base method.
-func (m *MockGamelistRepository) GetAllListTypes() ([]entity.ListType, error) {
+func (m *MockGamelistRepository) GetAllListTypes(arg0 context.Context, arg1 entity.Cursor, arg2 int) ([]entity.ListType, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllListTypes")
+	ret := m.ctrl.Call(m, "GetAllListTypes", arg0, arg1, arg2)
 	ret0, _ := ret[0].([]entity.ListType)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllListTypes indicates an expected call of GetAllListTypes.
-func (mr *MockGamelistRepositoryMockRecorder) GetAllListTypes() *gomock.Call {
+func (mr *MockGamelistRepositoryMockRecorder) GetAllListTypes(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllListTypes", reflect.TypeOf((*MockGamelistRepository)(nil).GetAllListTypes))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllListTypes", reflect.TypeOf((*MockGamelistRepository)(nil).GetAllListTypes), arg0, arg1, arg2)
 }
 
 // GetAllPlatforms mocks base method.
-func (m *MockGamelistRepository) GetAllPlatforms() ([]entity.Platform, error) {
+func (m *MockGamelistRepository) GetAllPlatforms(arg0 context.Context, arg1 entity.Cursor, arg2 int) ([]entity.Platform, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllPlatforms")
+	ret := m.ctrl.Call(m, "GetAllPlatforms", arg0, arg1, arg2)
 	ret0, _ := ret[0].([]entity.Platform)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllPlatforms indicates an expected call of GetAllPlatforms.
-func (mr *MockGamelistRepositoryMockRecorder) GetAllPlatforms() *gomock.Call {
+func (mr *MockGamelistRepositoryMockRecorder) GetAllPlatforms(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllPlatforms", reflect.TypeOf((*MockGamelistRepository)(nil).GetAllPlatforms))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllPlatforms", reflect.TypeOf((*MockGamelistRepository)(nil).GetAllPlatforms), arg0, arg1, arg2)
 }
 
 // GetAllProfiles mocks base method.
-func (m *MockGamelistRepository) GetAllProfiles() ([]entity.ProfileInfo, error) {
+func (m *MockGamelistRepository) GetAllProfiles(arg0 context.Context, arg1 entity.Cursor, arg2 int) ([]entity.ProfileInfo, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllProfiles")
+	ret := m.ctrl.Call(m, "GetAllProfiles", arg0, arg1, arg2)
 	ret0, _ := ret[0].([]entity.ProfileInfo)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllProfiles indicates an expected call of GetAllProfiles.
-func (mr *MockGamelistRepositoryMockRecorder) GetAllProfiles() *gomock.Call {
+func (mr *MockGamelistRepositoryMockRecorder) GetAllProfiles(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllProfiles", reflect.TypeOf((*MockGamelistRepository)(nil).GetAllProfiles))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllProfiles", reflect.TypeOf((*MockGamelistRepository)(nil).GetAllProfiles), arg0, arg1, arg2)
 }
 
 // GetAllSocialTypes mocks base method.
-func (m *MockGamelistRepository) GetAllSocialTypes() ([]entity.SocialType, error) {
+func (m *MockGamelistRepository) GetAllSocialTypes(arg0 context.Context, arg1 entity.Cursor, arg2 int) ([]entity.SocialType, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllSocialTypes")
+	ret := m.ctrl.Call(m, "GetAllSocialTypes", arg0, arg1, arg2)
 	ret0, _ := ret[0].([]entity.SocialType)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllSocialTypes indicates an expected call of GetAllSocialTypes.
-func (mr *MockGamelistRepositoryMockRecorder) GetAllSocialTypes() *gomock.Call {
+func (mr *MockGamelistRepositoryMockRecorder) GetAllSocialTypes(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllSocialTypes", reflect.TypeOf((*MockGamelistRepository)(nil).GetAllSocialTypes), arg0, arg1, arg2)
+}
+
+// GetGame mocks base method.
+func (m *MockGamelistRepository) GetGame(arg0 context.Context, arg1 uint64) (*entity.GameProperties, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGame", arg0, arg1)
+	ret0, _ := ret[0].(*entity.GameProperties)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGame indicates an expected call of GetGame.
+func (mr *MockGamelistRepositoryMockRecorder) GetGame(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllSocialTypes", reflect.TypeOf((*MockGamelistRepository)(nil).GetAllSocialTypes))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGame", reflect.TypeOf((*MockGamelistRepository)(nil).GetGame), arg0, arg1)
 }
 
 // GetGameDetails mocks base method.
-func (m *MockGamelistRepository) GetGameDetails(arg0 string, arg1 uint64) (*entity.GameDetailsResponse, error) {
+func (m *MockGamelistRepository) GetGameDetails(arg0 context.Context, arg1 string, arg2 uint64) (*entity.GameDetailsResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetGameDetails", arg0, arg1)
+	ret := m.ctrl.Call(m, "GetGameDetails", arg0, arg1, arg2)
 	ret0, _ := ret[0].(*entity.GameDetailsResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetGameDetails indicates an expected call of GetGameDetails.
-func (mr *MockGamelistRepositoryMockRecorder) GetGameDetails(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockGamelistRepositoryMockRecorder) GetGameDetails(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGameDetails", reflect.TypeOf((*MockGamelistRepository)(nil).GetGameDetails), arg0, arg1, arg2)
+}
+
+// GetGameDuplicates mocks base method.
+func (m *MockGamelistRepository) GetGameDuplicates(arg0 context.Context, arg1 string, arg2 int) ([]entity.GameDuplicate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGameDuplicates", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]entity.GameDuplicate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGameDuplicates indicates an expected call of GetGameDuplicates.
+func (mr *MockGamelistRepositoryMockRecorder) GetGameDuplicates(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGameDuplicates", reflect.TypeOf((*MockGamelistRepository)(nil).GetGameDuplicates), arg0, arg1, arg2)
+}
+
+// GetGameListsOfProfiles mocks base method.
+func (m *MockGamelistRepository) GetGameListsOfProfiles(arg0 context.Context, arg1 []uint64, arg2 entity.Cursor, arg3 int) (map[uint64][]entity.TypedGameListProperties, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGameListsOfProfiles", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(map[uint64][]entity.TypedGameListProperties)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGameListsOfProfiles indicates an expected call of GetGameListsOfProfiles.
+func (mr *MockGamelistRepositoryMockRecorder) GetGameListsOfProfiles(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGameDetails", reflect.TypeOf((*MockGamelistRepository)(nil).GetGameDetails), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGameListsOfProfiles", reflect.TypeOf((*MockGamelistRepository)(nil).GetGameListsOfProfiles), arg0, arg1, arg2, arg3)
+}
+
+// GetGameTitles mocks base method.
+func (m *MockGamelistRepository) GetGameTitles(arg0 context.Context) ([]entity.GameTitle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGameTitles", arg0)
+	ret0, _ := ret[0].([]entity.GameTitle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGameTitles indicates an expected call of GetGameTitles.
+func (mr *MockGamelistRepositoryMockRecorder) GetGameTitles(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGameTitles", reflect.TypeOf((*MockGamelistRepository)(nil).GetGameTitles), arg0)
+}
+
+// GetGames mocks base method.
+func (m *MockGamelistRepository) GetGames(arg0 context.Context, arg1 entity.Cursor, arg2 int) ([]entity.GameProperties, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGames", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]entity.GameProperties)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGames indicates an expected call of GetGames.
+func (mr *MockGamelistRepositoryMockRecorder) GetGames(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGames", reflect.TypeOf((*MockGamelistRepository)(nil).GetGames), arg0, arg1, arg2)
+}
+
+// GetGamesByIDs mocks base method.
+func (m *MockGamelistRepository) GetGamesByIDs(arg0 context.Context, arg1 []uint64) (map[uint64]entity.GameProperties, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGamesByIDs", arg0, arg1)
+	ret0, _ := ret[0].(map[uint64]entity.GameProperties)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGamesByIDs indicates an expected call of GetGamesByIDs.
+func (mr *MockGamelistRepositoryMockRecorder) GetGamesByIDs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGamesByIDs", reflect.TypeOf((*MockGamelistRepository)(nil).GetGamesByIDs), arg0, arg1)
+}
+
+// GetGenresOfGames mocks base method.
+func (m *MockGamelistRepository) GetGenresOfGames(arg0 context.Context, arg1 []uint64) (map[uint64][]entity.Genre, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGenresOfGames", arg0, arg1)
+	ret0, _ := ret[0].(map[uint64][]entity.Genre)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGenresOfGames indicates an expected call of GetGenresOfGames.
+func (mr *MockGamelistRepositoryMockRecorder) GetGenresOfGames(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGenresOfGames", reflect.TypeOf((*MockGamelistRepository)(nil).GetGenresOfGames), arg0, arg1)
+}
+
+// GetListTypesByIDs mocks base method.
+func (m *MockGamelistRepository) GetListTypesByIDs(arg0 context.Context, arg1 []uint64) (map[uint64]entity.ListType, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetListTypesByIDs", arg0, arg1)
+	ret0, _ := ret[0].(map[uint64]entity.ListType)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetListTypesByIDs indicates an expected call of GetListTypesByIDs.
+func (mr *MockGamelistRepositoryMockRecorder) GetListTypesByIDs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListTypesByIDs", reflect.TypeOf((*MockGamelistRepository)(nil).GetListTypesByIDs), arg0, arg1)
+}
+
+// GetListTypesOfGames mocks base method.
+func (m *MockGamelistRepository) GetListTypesOfGames(arg0 context.Context, arg1 string, arg2 []uint64) (map[uint64]uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetListTypesOfGames", arg0, arg1, arg2)
+	ret0, _ := ret[0].(map[uint64]uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetListTypesOfGames indicates an expected call of GetListTypesOfGames.
+func (mr *MockGamelistRepositoryMockRecorder) GetListTypesOfGames(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListTypesOfGames", reflect.TypeOf((*MockGamelistRepository)(nil).GetListTypesOfGames), arg0, arg1, arg2)
+}
+
+// GetPlatformsOfGames mocks base method.
+func (m *MockGamelistRepository) GetPlatformsOfGames(arg0 context.Context, arg1 []uint64) (map[uint64][]entity.Platform, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPlatformsOfGames", arg0, arg1)
+	ret0, _ := ret[0].(map[uint64][]entity.Platform)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPlatformsOfGames indicates an expected call of GetPlatformsOfGames.
+func (mr *MockGamelistRepositoryMockRecorder) GetPlatformsOfGames(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPlatformsOfGames", reflect.TypeOf((*MockGamelistRepository)(nil).GetPlatformsOfGames), arg0, arg1)
 }
 
 // GetProfile mocks base method.
-func (m *MockGamelistRepository) GetProfile(arg0 entity.ProfileCreds) (*entity.Profile, error) {
+func (m *MockGamelistRepository) GetProfile(arg0 context.Context, arg1 entity.ProfileCreds) (*entity.Profile, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetProfile", arg0)
+	ret := m.ctrl.Call(m, "GetProfile", arg0, arg1)
 	ret0, _ := ret[0].(*entity.Profile)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetProfile indicates an expected call of GetProfile.
-func (mr *MockGamelistRepositoryMockRecorder) GetProfile(arg0 interface{}) *gomock.Call {
+func (mr *MockGamelistRepositoryMockRecorder) GetProfile(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProfile", reflect.TypeOf((*MockGamelistRepository)(nil).GetProfile), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProfile", reflect.TypeOf((*MockGamelistRepository)(nil).GetProfile), arg0, arg1)
+}
+
+// GetProfileInfo mocks base method.
+func (m *MockGamelistRepository) GetProfileInfo(arg0 context.Context, arg1 string) (*entity.ProfileInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProfileInfo", arg0, arg1)
+	ret0, _ := ret[0].(*entity.ProfileInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProfileInfo indicates an expected call of GetProfileInfo.
+func (mr *MockGamelistRepositoryMockRecorder) GetProfileInfo(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProfileInfo", reflect.TypeOf((*MockGamelistRepository)(nil).GetProfileInfo), arg0, arg1)
+}
+
+// GetScrapeJob mocks base method.
+func (m *MockGamelistRepository) GetScrapeJob(arg0 context.Context, arg1 uint64) (*entity.ScrapeJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScrapeJob", arg0, arg1)
+	ret0, _ := ret[0].(*entity.ScrapeJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScrapeJob indicates an expected call of GetScrapeJob.
+func (mr *MockGamelistRepositoryMockRecorder) GetScrapeJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScrapeJob", reflect.TypeOf((*MockGamelistRepository)(nil).GetScrapeJob), arg0, arg1)
+}
+
+// GetScrapeJobs mocks base method.
+func (m *MockGamelistRepository) GetScrapeJobs(arg0 context.Context, arg1 int) ([]entity.ScrapeJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScrapeJobs", arg0, arg1)
+	ret0, _ := ret[0].([]entity.ScrapeJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScrapeJobs indicates an expected call of GetScrapeJobs.
+func (mr *MockGamelistRepositoryMockRecorder) GetScrapeJobs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScrapeJobs", reflect.TypeOf((*MockGamelistRepository)(nil).GetScrapeJobs), arg0, arg1)
+}
+
+// GetSocialsOfProfiles mocks base method.
+func (m *MockGamelistRepository) GetSocialsOfProfiles(arg0 context.Context, arg1 []uint64) (map[uint64][]entity.Social, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSocialsOfProfiles", arg0, arg1)
+	ret0, _ := ret[0].(map[uint64][]entity.Social)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSocialsOfProfiles indicates an expected call of GetSocialsOfProfiles.
+func (mr *MockGamelistRepositoryMockRecorder) GetSocialsOfProfiles(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSocialsOfProfiles", reflect.TypeOf((*MockGamelistRepository)(nil).GetSocialsOfProfiles), arg0, arg1)
 }
 
 // GetUserGameList mocks base method.
-func (m *MockGamelistRepository) GetUserGameList(arg0 string) ([]entity.TypedGameListProperties, error) {
+func (m *MockGamelistRepository) GetUserGameList(arg0 context.Context, arg1 string, arg2 entity.Cursor, arg3 int) ([]entity.TypedGameListProperties, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserGameList", arg0)
+	ret := m.ctrl.Call(m, "GetUserGameList", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].([]entity.TypedGameListProperties)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetUserGameList indicates an expected call of GetUserGameList.
-func (mr *MockGamelistRepositoryMockRecorder) GetUserGameList(arg0 interface{}) *gomock.Call {
+func (mr *MockGamelistRepositoryMockRecorder) GetUserGameList(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserGameList", reflect.TypeOf((*MockGamelistRepository)(nil).GetUserGameList), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserGameList", reflect.TypeOf((*MockGamelistRepository)(nil).GetUserGameList), arg0, arg1, arg2, arg3)
 }
 
 // ListGame mocks base method.
-func (m *MockGamelistRepository) ListGame(arg0 string, arg1, arg2 uint64) error {
+func (m *MockGamelistRepository) ListGame(arg0 context.Context, arg1 string, arg2, arg3 uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListGame", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "ListGame", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // ListGame indicates an expected call of ListGame.
-func (mr *MockGamelistRepositoryMockRecorder) ListGame(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockGamelistRepositoryMockRecorder) ListGame(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGame", reflect.TypeOf((*MockGamelistRepository)(nil).ListGame), arg0, arg1, arg2, arg3)
+}
+
+// ListGames mocks base method.
+func (m *MockGamelistRepository) ListGames(arg0 context.Context, arg1 string, arg2 []entity.GameListRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListGames", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ListGames indicates an expected call of ListGames.
+func (mr *MockGamelistRepositoryMockRecorder) ListGames(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGame", reflect.TypeOf((*MockGamelistRepository)(nil).ListGame), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListGames", reflect.TypeOf((*MockGamelistRepository)(nil).ListGames), arg0, arg1, arg2)
+}
+
+// MergeGames mocks base method.
+func (m *MockGamelistRepository) MergeGames(arg0 context.Context, arg1, arg2 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeGames", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MergeGames indicates an expected call of MergeGames.
+func (mr *MockGamelistRepositoryMockRecorder) MergeGames(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeGames", reflect.TypeOf((*MockGamelistRepository)(nil).MergeGames), arg0, arg1, arg2)
+}
+
+// Ping mocks base method.
+func (m *MockGamelistRepository) Ping(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockGamelistRepositoryMockRecorder) Ping(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockGamelistRepository)(nil).Ping), arg0)
+}
+
+// PurgeProfiles mocks base method.
+func (m *MockGamelistRepository) PurgeProfiles(arg0 context.Context, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeProfiles", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeProfiles indicates an expected call of PurgeProfiles.
+func (mr *MockGamelistRepositoryMockRecorder) PurgeProfiles(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeProfiles", reflect.TypeOf((*MockGamelistRepository)(nil).PurgeProfiles), arg0, arg1)
+}
+
+// RequestProfileDeletion mocks base method.
+func (m *MockGamelistRepository) RequestProfileDeletion(arg0 context.Context, arg1 string, arg2 time.Time) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestProfileDeletion", arg0, arg1, arg2)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestProfileDeletion indicates an expected call of RequestProfileDeletion.
+func (mr *MockGamelistRepositoryMockRecorder) RequestProfileDeletion(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestProfileDeletion", reflect.TypeOf((*MockGamelistRepository)(nil).RequestProfileDeletion), arg0, arg1, arg2)
+}
+
+// RestoreGame mocks base method.
+func (m *MockGamelistRepository) RestoreGame(arg0 context.Context, arg1 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreGame", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreGame indicates an expected call of RestoreGame.
+func (mr *MockGamelistRepositoryMockRecorder) RestoreGame(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreGame", reflect.TypeOf((*MockGamelistRepository)(nil).RestoreGame), arg0, arg1)
+}
+
+// RestoreGenre mocks base method.
+func (m *MockGamelistRepository) RestoreGenre(arg0 context.Context, arg1 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreGenre", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreGenre indicates an expected call of RestoreGenre.
+func (mr *MockGamelistRepositoryMockRecorder) RestoreGenre(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreGenre", reflect.TypeOf((*MockGamelistRepository)(nil).RestoreGenre), arg0, arg1)
+}
+
+// RestoreListType mocks base method.
+func (m *MockGamelistRepository) RestoreListType(arg0 context.Context, arg1 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreListType", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreListType indicates an expected call of RestoreListType.
+func (mr *MockGamelistRepositoryMockRecorder) RestoreListType(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreListType", reflect.TypeOf((*MockGamelistRepository)(nil).RestoreListType), arg0, arg1)
+}
+
+// RestorePlatform mocks base method.
+func (m *MockGamelistRepository) RestorePlatform(arg0 context.Context, arg1 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestorePlatform", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestorePlatform indicates an expected call of RestorePlatform.
+func (mr *MockGamelistRepositoryMockRecorder) RestorePlatform(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestorePlatform", reflect.TypeOf((*MockGamelistRepository)(nil).RestorePlatform), arg0, arg1)
+}
+
+// RestoreSocialType mocks base method.
+func (m *MockGamelistRepository) RestoreSocialType(arg0 context.Context, arg1 uint64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreSocialType", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreSocialType indicates an expected call of RestoreSocialType.
+func (mr *MockGamelistRepositoryMockRecorder) RestoreSocialType(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreSocialType", reflect.TypeOf((*MockGamelistRepository)(nil).RestoreSocialType), arg0, arg1)
 }
 
 // SaveGame mocks base method.
-func (m *MockGamelistRepository) SaveGame(arg0 entity.GameProperties) error {
+func (m *MockGamelistRepository) SaveGame(arg0 context.Context, arg1 *entity.GameProperties) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveGame", arg0)
+	ret := m.ctrl.Call(m, "SaveGame", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SaveGame indicates an expected call of SaveGame.
-func (mr *MockGamelistRepositoryMockRecorder) SaveGame(arg0 interface{}) *gomock.Call {
+func (mr *MockGamelistRepositoryMockRecorder) SaveGame(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveGame", reflect.TypeOf((*MockGamelistRepository)(nil).SaveGame), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveGame", reflect.TypeOf((*MockGamelistRepository)(nil).SaveGame), arg0, arg1)
+}
+
+// SaveGameDuplicates mocks base method.
+func (m *MockGamelistRepository) SaveGameDuplicates(arg0 context.Context, arg1 []entity.GameDuplicate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveGameDuplicates", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveGameDuplicates indicates an expected call of SaveGameDuplicates.
+func (mr *MockGamelistRepositoryMockRecorder) SaveGameDuplicates(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveGameDuplicates", reflect.TypeOf((*MockGamelistRepository)(nil).SaveGameDuplicates), arg0, arg1)
 }
 
 // SaveGenre mocks base method.
-func (m *MockGamelistRepository) SaveGenre(arg0 entity.Genre) error {
+func (m *MockGamelistRepository) SaveGenre(arg0 context.Context, arg1 *entity.Genre) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveGenre", arg0)
+	ret := m.ctrl.Call(m, "SaveGenre", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SaveGenre indicates an expected call of SaveGenre.
-func (mr *MockGamelistRepositoryMockRecorder) SaveGenre(arg0 interface{}) *gomock.Call {
+func (mr *MockGamelistRepositoryMockRecorder) SaveGenre(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveGenre", reflect.TypeOf((*MockGamelistRepository)(nil).SaveGenre), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveGenre", reflect.TypeOf((*MockGamelistRepository)(nil).SaveGenre), arg0, arg1)
 }
 
 // SavePlatform mocks base method.
-func (m *MockGamelistRepository) SavePlatform(arg0 entity.Platform) error {
+func (m *MockGamelistRepository) SavePlatform(arg0 context.Context, arg1 *entity.Platform) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SavePlatform", arg0)
+	ret := m.ctrl.Call(m, "SavePlatform", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SavePlatform indicates an expected call of SavePlatform.
-func (mr *MockGamelistRepositoryMockRecorder) SavePlatform(arg0 interface{}) *gomock.Call {
+func (mr *MockGamelistRepositoryMockRecorder) SavePlatform(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePlatform", reflect.TypeOf((*MockGamelistRepository)(nil).SavePlatform), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePlatform", reflect.TypeOf((*MockGamelistRepository)(nil).SavePlatform), arg0, arg1)
 }
 
 // SaveProfile mocks base method.
-func (m *MockGamelistRepository) SaveProfile(arg0 entity.Profile) error {
+func (m *MockGamelistRepository) SaveProfile(arg0 context.Context, arg1 entity.Profile) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveProfile", arg0)
+	ret := m.ctrl.Call(m, "SaveProfile", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SaveProfile indicates an expected call of SaveProfile.
-func (mr *MockGamelistRepositoryMockRecorder) SaveProfile(arg0 interface{}) *gomock.Call {
+func (mr *MockGamelistRepositoryMockRecorder) SaveProfile(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveProfile", reflect.TypeOf((*MockGamelistRepository)(nil).SaveProfile), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveProfile", reflect.TypeOf((*MockGamelistRepository)(nil).SaveProfile), arg0, arg1)
 }
 
 // SaveRefreshToken mocks base method.
-func (m *MockGamelistRepository) SaveRefreshToken(arg0, arg1 string) error {
+func (m *MockGamelistRepository) SaveRefreshToken(arg0 context.Context, arg1, arg2 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveRefreshToken", arg0, arg1)
+	ret := m.ctrl.Call(m, "SaveRefreshToken", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SaveRefreshToken indicates an expected call of SaveRefreshToken.
-func (mr *MockGamelistRepositoryMockRecorder) SaveRefreshToken(arg0, arg1 interface{}) *gomock.Call {
+func (mr *MockGamelistRepositoryMockRecorder) SaveRefreshToken(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRefreshToken", reflect.TypeOf((*MockGamelistRepository)(nil).SaveRefreshToken), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRefreshToken", reflect.TypeOf((*MockGamelistRepository)(nil).SaveRefreshToken), arg0, arg1, arg2)
+}
+
+// SaveScrapeJob mocks base method.
+func (m *MockGamelistRepository) SaveScrapeJob(arg0 context.Context, arg1 entity.ScrapeJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveScrapeJob", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveScrapeJob indicates an expected call of SaveScrapeJob.
+func (mr *MockGamelistRepositoryMockRecorder) SaveScrapeJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveScrapeJob", reflect.TypeOf((*MockGamelistRepository)(nil).SaveScrapeJob), arg0, arg1)
 }
 
 // SaveSocialType mocks base method.
-func (m *MockGamelistRepository) SaveSocialType(arg0 entity.SocialType) error {
+func (m *MockGamelistRepository) SaveSocialType(arg0 context.Context, arg1 *entity.SocialType) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveSocialType", arg0)
+	ret := m.ctrl.Call(m, "SaveSocialType", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SaveSocialType indicates an expected call of SaveSocialType.
-func (mr *MockGamelistRepositoryMockRecorder) SaveSocialType(arg0 interface{}) *gomock.Call {
+func (mr *MockGamelistRepositoryMockRecorder) SaveSocialType(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSocialType", reflect.TypeOf((*MockGamelistRepository)(nil).SaveSocialType), arg0, arg1)
+}
+
+// SchemaVersion mocks base method.
+func (m *MockGamelistRepository) SchemaVersion(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SchemaVersion", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SchemaVersion indicates an expected call of SchemaVersion.
+func (mr *MockGamelistRepositoryMockRecorder) SchemaVersion(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSocialType", reflect.TypeOf((*MockGamelistRepository)(nil).SaveSocialType), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SchemaVersion", reflect.TypeOf((*MockGamelistRepository)(nil).SchemaVersion), arg0)
 }
 
 // SearchGames mocks base method.
-func (m *MockGamelistRepository) SearchGames(arg0 string) ([]entity.GameSearchResult, error) {
+func (m *MockGamelistRepository) SearchGames(arg0 context.Context, arg1 string) ([]entity.GameSearchResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SearchGames", arg0)
+	ret := m.ctrl.Call(m, "SearchGames", arg0, arg1)
 	ret0, _ := ret[0].([]entity.GameSearchResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // SearchGames indicates an expected call of SearchGames.
-func (mr *MockGamelistRepositoryMockRecorder) SearchGames(arg0 interface{}) *gomock.Call {
+func (mr *MockGamelistRepositoryMockRecorder) SearchGames(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchGames", reflect.TypeOf((*MockGamelistRepository)(nil).SearchGames), arg0, arg1)
+}
+
+// UpdateGame mocks base method.
+func (m *MockGamelistRepository) UpdateGame(arg0 context.Context, arg1 *entity.GameProperties) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateGame", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateGame indicates an expected call of UpdateGame.
+func (mr *MockGamelistRepositoryMockRecorder) UpdateGame(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGame", reflect.TypeOf((*MockGamelistRepository)(nil).UpdateGame), arg0, arg1)
+}
+
+// UpdateGenre mocks base method.
+func (m *MockGamelistRepository) UpdateGenre(arg0 context.Context, arg1 *entity.Genre) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateGenre", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateGenre indicates an expected call of UpdateGenre.
+func (mr *MockGamelistRepositoryMockRecorder) UpdateGenre(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGenre", reflect.TypeOf((*MockGamelistRepository)(nil).UpdateGenre), arg0, arg1)
+}
+
+// UpdateListType mocks base method.
+func (m *MockGamelistRepository) UpdateListType(arg0 context.Context, arg1 *entity.ListType) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateListType", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateListType indicates an expected call of UpdateListType.
+func (mr *MockGamelistRepositoryMockRecorder) UpdateListType(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateListType", reflect.TypeOf((*MockGamelistRepository)(nil).UpdateListType), arg0, arg1)
+}
+
+// UpdatePlatform mocks base method.
+func (m *MockGamelistRepository) UpdatePlatform(arg0 context.Context, arg1 *entity.Platform) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePlatform", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePlatform indicates an expected call of UpdatePlatform.
+func (mr *MockGamelistRepositoryMockRecorder) UpdatePlatform(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePlatform", reflect.TypeOf((*MockGamelistRepository)(nil).UpdatePlatform), arg0, arg1)
+}
+
+// UpdateSocialType mocks base method.
+func (m *MockGamelistRepository) UpdateSocialType(arg0 context.Context, arg1 *entity.SocialType) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSocialType", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSocialType indicates an expected call of UpdateSocialType.
+func (mr *MockGamelistRepositoryMockRecorder) UpdateSocialType(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSocialType", reflect.TypeOf((*MockGamelistRepository)(nil).UpdateSocialType), arg0, arg1)
+}
+
+// UpsertGame mocks base method.
+func (m *MockGamelistRepository) UpsertGame(arg0 context.Context, arg1 entity.SourcedGame, arg2 entity.SourcePriority) (entity.IngestResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertGame", arg0, arg1, arg2)
+	ret0, _ := ret[0].(entity.IngestResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertGame indicates an expected call of UpsertGame.
+func (mr *MockGamelistRepositoryMockRecorder) UpsertGame(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertGame", reflect.TypeOf((*MockGamelistRepository)(nil).UpsertGame), arg0, arg1, arg2)
+}
+
+// WithTx mocks base method.
+func (m *MockGamelistRepository) WithTx(arg0 context.Context, arg1 func(repository.GamelistRepository) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockGamelistRepositoryMockRecorder) WithTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchGames", reflect.TypeOf((*MockGamelistRepository)(nil).SearchGames), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockGamelistRepository)(nil).WithTx), arg0, arg1)
 }
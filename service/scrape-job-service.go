@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/br3w0r/gamelist-backend/entity"
+	"github.com/br3w0r/gamelist-backend/repository"
+	utilErrs "github.com/br3w0r/gamelist-backend/util/errors"
+	utilLogger "github.com/br3w0r/gamelist-backend/util/logger"
+	"github.com/br3w0r/gamelist-backend/util/metrics"
+	"github.com/br3w0r/gamelist-backend/util/tracing"
+	"github.com/robfig/cron/v3"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var scrapeLog = utilLogger.For(utilLogger.SubsystemScrape)
+
+type ScrapeJobService interface {
+	// Start runs a new scrape job in background. Only one job may run at a time across
+	// all instances.
+	Start(ctx context.Context, trigger string) (*entity.ScrapeJob, error)
+	// Run runs a new scrape job and waits for it to finish
+	Run(ctx context.Context, trigger string) (*entity.ScrapeJob, error)
+	Cancel(id uint64) error
+	GetJob(ctx context.Context, id uint64) (*entity.ScrapeJob, error)
+	GetJobs(ctx context.Context, limit int) ([]entity.ScrapeJob, error)
+	// AbortStale marks jobs of servers which stopped without finishing them as failed
+	AbortStale(ctx context.Context) error
+
+	// Schedule starts a job on every tick of the cron spec, e.g. "0 4 * * *"
+	Schedule(spec string) error
+	// Stop stops the schedule and cancels the running job
+	Stop()
+}
+
+type runningScrapeJob struct {
+	id     uint64
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+type scrapeJobService struct {
+	repo            repository.GamelistRepository
+	gamelistService GameListService
+
+	mu      sync.Mutex
+	running *runningScrapeJob
+	cron    *cron.Cron
+}
+
+const (
+	SCRAPE_JOBS_LIMIT = 100
+
+	// Running jobs beat every SCRAPE_JOB_HEARTBEAT, jobs which missed a few beats are
+	// taken for jobs of stopped servers
+	SCRAPE_JOB_HEARTBEAT   = 15 * time.Second
+	SCRAPE_JOB_STALE_AFTER = 4 * SCRAPE_JOB_HEARTBEAT
+)
+
+func NewScrapeJobService(repo repository.GamelistRepository, gamelistService GameListService) ScrapeJobService {
+	return &scrapeJobService{
+		repo:            repo,
+		gamelistService: gamelistService,
+	}
+}
+
+func (s *scrapeJobService) Start(ctx context.Context, trigger string) (*entity.ScrapeJob, error) {
+	job, running, err := s.start(ctx, trigger)
+	if err != nil {
+		return nil, err
+	}
+
+	go s.run(job, running)
+
+	return &job, nil
+}
+
+func (s *scrapeJobService) Run(ctx context.Context, trigger string) (*entity.ScrapeJob, error) {
+	job, running, err := s.start(ctx, trigger)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.run(job, running), nil
+}
+
+func (s *scrapeJobService) Cancel(id uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running == nil || s.running.id != id {
+		return utilErrs.Newf(utilErrs.NotFound, nil, "no running scrape job with id: %d", id)
+	}
+
+	s.running.cancel()
+
+	return nil
+}
+
+func (s *scrapeJobService) GetJob(ctx context.Context, id uint64) (*entity.ScrapeJob, error) {
+	return s.repo.GetScrapeJob(ctx, id)
+}
+
+func (s *scrapeJobService) GetJobs(ctx context.Context, limit int) ([]entity.ScrapeJob, error) {
+	if limit <= 0 || limit > SCRAPE_JOBS_LIMIT {
+		limit = SCRAPE_JOBS_LIMIT
+	}
+
+	return s.repo.GetScrapeJobs(ctx, limit)
+}
+
+func (s *scrapeJobService) AbortStale(ctx context.Context) error {
+	return s.repo.AbortStaleScrapeJobs(ctx, time.Now().Add(-SCRAPE_JOB_STALE_AFTER),
+		"its server stopped without finishing it")
+}
+
+func (s *scrapeJobService) Schedule(spec string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cron != nil {
+		return utilErrs.New(utilErrs.BadInput, nil, "scrape jobs are already scheduled")
+	}
+
+	c := cron.New()
+	_, err := c.AddFunc(spec, func() {
+		if _, err := s.Start(context.Background(), entity.ScrapeTriggerSchedule); err != nil {
+			scrapeLog.Error("failed to start scheduled scrape job", "error", err)
+		}
+	})
+	if err != nil {
+		return utilErrs.Newf(utilErrs.BadInput, err, "wrong scrape schedule \"%s\"", spec)
+	}
+
+	c.Start()
+	s.cron = c
+
+	return nil
+}
+
+func (s *scrapeJobService) Stop() {
+	s.mu.Lock()
+	if s.cron != nil {
+		s.cron.Stop()
+		s.cron = nil
+	}
+	running := s.running
+	s.mu.Unlock()
+
+	if running != nil {
+		running.cancel()
+		<-running.done
+	}
+}
+
+// start reserves the running slot and persists a new job record. The database keeps
+// jobs of other instances from running at the same time.
+func (s *scrapeJobService) start(ctx context.Context, trigger string) (entity.ScrapeJob, *runningScrapeJob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running != nil {
+		return entity.ScrapeJob{}, nil, utilErrs.Newf(utilErrs.Conflict, nil,
+			"scrape job %d is already running", s.running.id).WithReason(utilErrs.ReasonScrapeJobRunning)
+	}
+
+	// A job of a stopped server would hold the slot forever
+	if err := s.AbortStale(ctx); err != nil {
+		return entity.ScrapeJob{}, nil, err
+	}
+
+	now := time.Now()
+	job := entity.ScrapeJob{
+		Trigger:     trigger,
+		Status:      entity.ScrapeJobRunning,
+		StartedAt:   now,
+		HeartbeatAt: now,
+	}
+	if err := s.repo.CreateScrapeJob(ctx, &job); err != nil {
+		return entity.ScrapeJob{}, nil, err
+	}
+
+	// The job outlives the request which started it
+	jobCtx, cancel := context.WithCancel(context.Background())
+	s.running = &runningScrapeJob{
+		id:     job.ID,
+		ctx:    jobCtx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	metrics.ScrapeJobRunning.Set(1)
+
+	return job, s.running, nil
+}
+
+func (s *scrapeJobService) run(job entity.ScrapeJob, running *runningScrapeJob) *entity.ScrapeJob {
+	defer func() {
+		s.mu.Lock()
+		s.running = nil
+		s.mu.Unlock()
+
+		running.cancel()
+		close(running.done)
+		metrics.ScrapeJobRunning.Set(0)
+	}()
+
+	go s.beat(running)
+
+	ctx, span := serviceTracer.Start(running.ctx, "ScrapeJobService.Run", trace.WithAttributes(
+		attribute.Int64("job", int64(job.ID)),
+		attribute.String("trigger", job.Trigger),
+	))
+	stats, err := s.gamelistService.ScrapeGames(ctx)
+	tracing.End(span, err)
+
+	finished := time.Now()
+	job.FinishedAt = &finished
+	job.HeartbeatAt = finished
+	if stats != nil {
+		job.Stats = *stats
+	}
+
+	switch {
+	case running.ctx.Err() != nil:
+		job.Status = entity.ScrapeJobCancelled
+	case err != nil:
+		job.Status = entity.ScrapeJobFailed
+		job.Error = fmt.Sprintf("%v; cause: %v", err, errors.Unwrap(err))
+	default:
+		job.Status = entity.ScrapeJobSucceeded
+	}
+
+	metrics.ScrapeJobs.WithLabelValues(job.Trigger, job.Status).Inc()
+	metrics.ScrapeJobDuration.WithLabelValues(job.Status).Observe(finished.Sub(job.StartedAt).Seconds())
+	scrapeLog.Info("scrape job finished", "job", job.ID, "status", job.Status, "elapsed", finished.Sub(job.StartedAt))
+
+	if job.Status == entity.ScrapeJobSucceeded && (job.Stats.Inserted > 0 || job.Stats.Updated > 0) {
+		found, err := s.gamelistService.ScanDuplicates(ctx)
+		if err != nil {
+			scrapeLog.Error("failed to scan duplicates after scrape job", "job", job.ID, "error", err)
+		} else {
+			scrapeLog.Info("likely duplicate games found after scrape job", "job", job.ID, "found", found)
+		}
+	}
+
+	// Cancelled jobs are saved too
+	if err := s.repo.SaveScrapeJob(context.WithoutCancel(ctx), job); err != nil {
+		scrapeLog.Error("failed to save scrape job", "job", job.ID, "error", err)
+	}
+
+	return &job
+}
+
+// beat renews the heartbeat of the job until it's done
+func (s *scrapeJobService) beat(running *runningScrapeJob) {
+	ticker := time.NewTicker(SCRAPE_JOB_HEARTBEAT)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-running.done:
+			return
+		case <-ticker.C:
+			// Cancelled jobs still beat until they're saved
+			if err := s.repo.BeatScrapeJob(context.WithoutCancel(running.ctx), running.id); err != nil {
+				scrapeLog.Warn("failed to renew heartbeat of scrape job", "job", running.id, "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	pb "github.com/br3w0r/gamelist-backend/proto"
+	utilErrs "github.com/br3w0r/gamelist-backend/util/errors"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+type ScraperConfig struct {
+	// Address of the scraper including port, e.g. "localhost:8888"
+	Address string
+
+	TLS bool
+	// CAFile is a PEM bundle to verify the scraper with. System roots are used if empty.
+	CAFile string
+	// CertFile and KeyFile are a client certificate for mTLS
+	CertFile   string
+	KeyFile    string
+	ServerName string
+
+	// CallTimeout is a deadline of a single ScrapeGames call. Zero means no deadline.
+	CallTimeout time.Duration
+	// MaxRetries is the number of reconnects in a row without receiving a game
+	MaxRetries     int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// DialOptions are appended to the default ones, e.g. a custom dialer in tests
+	DialOptions []grpc.DialOption
+}
+
+type ScraperClient interface {
+	// Scrape streams all games to handle. Dropped streams are resumed after the last received game.
+	Scrape(ctx context.Context, handle func(game *pb.GameProperties) error) error
+	// Ping connects to the scraper if it isn't connected and waits until the connection is ready
+	Ping(ctx context.Context) error
+	Close() error
+}
+
+type scraperClient struct {
+	conf   ScraperConfig
+	conn   *grpc.ClientConn
+	client pb.GameScrapeClient
+}
+
+var (
+	DefaultScraperConfig = ScraperConfig{
+		Address:        "localhost:8888",
+		MaxRetries:     5,
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+	}
+)
+
+// NewScraperClient doesn't wait for the connection, so the scraper may come up later
+func NewScraperClient(conf ScraperConfig) (ScraperClient, error) {
+	creds, err := scraperCredentials(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := append([]grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithChainUnaryInterceptor(otelgrpc.UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(otelgrpc.StreamClientInterceptor()),
+	}, conf.DialOptions...)
+
+	conn, err := grpc.Dial(conf.Address, opts...)
+	if err != nil {
+		return nil, utilErrs.Newf(utilErrs.Internal, err, "failed to dial scraper at %s", conf.Address)
+	}
+
+	return &scraperClient{
+		conf:   conf,
+		conn:   conn,
+		client: pb.NewGameScrapeClient(conn),
+	}, nil
+}
+
+func (c *scraperClient) Scrape(ctx context.Context, handle func(game *pb.GameProperties) error) error {
+	var (
+		after   string
+		retries int
+	)
+
+	for {
+		received, err := c.scrape(ctx, after, func(game *pb.GameProperties) error {
+			after = game.SourceID()
+			return handle(game)
+		})
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return utilErrs.New(utilErrs.Timeout, ctx.Err(), "scraping was interrupted")
+		}
+
+		// Only reconnects that made no progress count towards the limit
+		if received > 0 {
+			retries = 0
+		}
+		if !retryableScraperError(err) || retries >= c.conf.MaxRetries {
+			return utilErrs.New(utilErrs.Internal, err, "failed to receive games from scraper")
+		}
+
+		backoff := c.backoff(retries)
+		retries++
+		scrapeLog.WarnContext(ctx, "scraper stream dropped", "after", after, "error", err,
+			"retry", retries, "max_retries", c.conf.MaxRetries, "retry_in", backoff)
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return utilErrs.New(utilErrs.Timeout, ctx.Err(), "scraping was interrupted")
+		}
+	}
+}
+
+func (c *scraperClient) Ping(ctx context.Context) error {
+	c.conn.Connect()
+	for {
+		state := c.conn.GetState()
+		switch state {
+		case connectivity.Ready:
+			return nil
+		case connectivity.Shutdown:
+			return utilErrs.New(utilErrs.Internal, nil, "scraper client is closed")
+		}
+
+		if !c.conn.WaitForStateChange(ctx, state) {
+			// Errors of pings are reported by /readyz, so the address is only logged
+			scrapeLog.WarnContext(ctx, "scraper isn't reachable", "address", c.conf.Address, "state", state.String())
+			return utilErrs.Newf(utilErrs.Timeout, ctx.Err(), "scraper isn't reachable, connection is %s",
+				strings.ToLower(state.String()))
+		}
+	}
+}
+
+func (c *scraperClient) Close() error {
+	return c.conn.Close()
+}
+
+// scrape makes a single ScrapeGames call and returns the number of received games
+func (c *scraperClient) scrape(ctx context.Context, after string, handle func(game *pb.GameProperties) error) (int, error) {
+	// Streams which aren't drained, e.g. when handle fails, are only released along
+	// with their span when the context of the call is done
+	var cancel context.CancelFunc
+	if c.conf.CallTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, c.conf.CallTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	stream, err := c.client.ScrapeGames(ctx, &pb.ScrapeRequest{After: after})
+	if err != nil {
+		return 0, err
+	}
+
+	received := 0
+	for {
+		game, err := stream.Recv()
+		if err == io.EOF {
+			return received, nil
+		} else if err != nil {
+			return received, err
+		}
+
+		received++
+		if err := handle(game); err != nil {
+			return received, status.Error(codes.Aborted, err.Error())
+		}
+	}
+}
+
+func (c *scraperClient) backoff(retries int) time.Duration {
+	backoff := c.conf.InitialBackoff
+	for i := 0; i < retries && backoff < c.conf.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if c.conf.MaxBackoff > 0 && backoff > c.conf.MaxBackoff {
+		backoff = c.conf.MaxBackoff
+	}
+
+	return backoff
+}
+
+func retryableScraperError(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Internal, codes.Unknown:
+		return true
+	}
+
+	return false
+}
+
+func scraperCredentials(conf ScraperConfig) (credentials.TransportCredentials, error) {
+	if !conf.TLS {
+		return insecure.NewCredentials(), nil
+	}
+
+	tlsConf := &tls.Config{
+		ServerName: conf.ServerName,
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if conf.CAFile != "" {
+		ca, err := os.ReadFile(conf.CAFile)
+		if err != nil {
+			return nil, utilErrs.Newf(utilErrs.BadInput, err, "failed to read scraper CA file %s", conf.CAFile)
+		}
+
+		tlsConf.RootCAs = x509.NewCertPool()
+		if !tlsConf.RootCAs.AppendCertsFromPEM(ca) {
+			return nil, utilErrs.Newf(utilErrs.BadInput, nil, "no certificates found in scraper CA file %s", conf.CAFile)
+		}
+	}
+
+	if conf.CertFile != "" || conf.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(conf.CertFile, conf.KeyFile)
+		if err != nil {
+			return nil, utilErrs.New(utilErrs.BadInput, err, "failed to load scraper client certificate")
+		}
+		tlsConf.Certificates = []tls.Certificate{cert}
+	}
+
+	return credentials.NewTLS(tlsConf), nil
+}
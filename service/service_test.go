@@ -3,12 +3,38 @@
 package service
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/br3w0r/gamelist-backend/entity"
+	"github.com/br3w0r/gamelist-backend/helpers"
+	pb "github.com/br3w0r/gamelist-backend/proto"
+	"github.com/br3w0r/gamelist-backend/repository"
+	utilErrs "github.com/br3w0r/gamelist-backend/util/errors"
 	"github.com/golang/mock/gomock"
 	"github.com/smartystreets/goconvey/convey"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"gorm.io/gorm"
 )
 
 var (
@@ -44,17 +70,1099 @@ func TestCreateProfile(t *testing.T) {
 	repo := NewMockGamelistRepository(ctrl)
 
 	repo.EXPECT().
-		CreateProfile(gomock.Any()).
-		DoAndReturn(func(profile entity.Profile) error {
+		CreateProfile(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, profile entity.Profile) error {
 			return bcrypt.CompareHashAndPassword([]byte(profile.Password), []byte(mockProfile.Password))
 		}).
 		Times(1)
 
-	service := NewGameListService(repo, "")
+	service := NewGameListService(repo, nil, nil, nil, nil)
 
 	convey.Convey("service.CreateProfile() should return nil error", t, func() {
-		err := service.CreateProfile(mockProfile)
+		err := service.CreateProfile(context.Background(), mockProfile)
 
 		convey.So(err, convey.ShouldBeNil)
 	})
 }
+
+func TestRefreshTokens(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	repo := NewMockGamelistRepository(ctrl)
+	expectTx(repo)
+	service := NewJWTService(repo)
+
+	repo.EXPECT().SaveRefreshToken(gomock.Any(), "test", gomock.Any()).Return(nil)
+	tokens, err := service.GenerateTokens(context.Background(), "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	convey.Convey("The old token should be deleted with saving of the new pair", t, func() {
+		gomock.InOrder(
+			repo.EXPECT().FindRefreshToken(gomock.Any(), "test", tokens.RefreshToken).Return(nil),
+			repo.EXPECT().SaveRefreshToken(gomock.Any(), "test", gomock.Any()).Return(nil),
+			repo.EXPECT().DeleteRefreshToken(gomock.Any(), tokens.RefreshToken).Return(nil),
+		)
+
+		refreshed, err := service.RefreshTokens(context.Background(), tokens.RefreshToken)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(refreshed.RefreshToken, convey.ShouldNotBeEmpty)
+
+		convey.Convey("And no pair should be returned if the transaction fails", func() {
+			gomock.InOrder(
+				repo.EXPECT().FindRefreshToken(gomock.Any(), "test", tokens.RefreshToken).Return(nil),
+				repo.EXPECT().SaveRefreshToken(gomock.Any(), "test", gomock.Any()).Return(nil),
+				repo.EXPECT().DeleteRefreshToken(gomock.Any(), tokens.RefreshToken).Return(errors.New("failed")),
+			)
+
+			refreshed, err := service.RefreshTokens(context.Background(), tokens.RefreshToken)
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(refreshed, convey.ShouldBeNil)
+		})
+
+		convey.Convey("And no pair should be returned if the token was refreshed meanwhile", func() {
+			notFound := utilErrs.New(utilErrs.NotFound, nil, "failed to delete refresh token")
+			gomock.InOrder(
+				repo.EXPECT().FindRefreshToken(gomock.Any(), "test", tokens.RefreshToken).Return(nil),
+				repo.EXPECT().SaveRefreshToken(gomock.Any(), "test", gomock.Any()).Return(nil),
+				repo.EXPECT().DeleteRefreshToken(gomock.Any(), tokens.RefreshToken).Return(notFound),
+			)
+
+			refreshed, err := service.RefreshTokens(context.Background(), tokens.RefreshToken)
+			convey.So(err, convey.ShouldEqual, notFound)
+			convey.So(refreshed, convey.ShouldBeNil)
+		})
+	})
+
+	convey.Convey("Revoking tokens which are already revoked should succeed", t, func() {
+		repo.EXPECT().DeleteRefreshToken(gomock.Any(), tokens.RefreshToken).
+			Return(utilErrs.New(utilErrs.NotFound, nil, "failed to delete refresh token"))
+		convey.So(service.RevokeRefreshToken(context.Background(), tokens.RefreshToken), convey.ShouldBeNil)
+
+		failure := utilErrs.New(utilErrs.Internal, nil, "failed to delete refresh token")
+		repo.EXPECT().DeleteRefreshToken(gomock.Any(), tokens.RefreshToken).Return(failure)
+		convey.So(service.RevokeRefreshToken(context.Background(), tokens.RefreshToken), convey.ShouldEqual, failure)
+	})
+}
+
+func TestIngestGame(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	repo := NewMockGamelistRepository(ctrl)
+
+	gomock.InOrder(
+		repo.EXPECT().UpsertGame(gomock.Any(), sourcedGame("1"), gomock.Any()).Return(entity.IngestInserted, nil),
+		repo.EXPECT().UpsertGame(gomock.Any(), sourcedGame("1"), gomock.Any()).Return(entity.IngestSkipped, nil),
+		repo.EXPECT().UpsertGame(gomock.Any(), sourcedGame("2"), gomock.Any()).Return(entity.IngestUpdated, nil),
+		repo.EXPECT().UpsertGame(gomock.Any(), sourcedGame("3"), gomock.Any()).Return(entity.IngestResult(0), errors.New("test error")),
+	)
+
+	service := &gameListService{repo: repo}
+
+	convey.Convey("service.ingestGame(context.Background(), ) should count every result", t, func() {
+		stats := &entity.IngestStats{}
+		service.ingestGame(context.Background(), stats, sourcedGame("1"))
+		service.ingestGame(context.Background(), stats, sourcedGame("1"))
+		service.ingestGame(context.Background(), stats, sourcedGame("2"))
+		service.ingestGame(context.Background(), stats, sourcedGame("3"))
+
+		convey.So(*stats, convey.ShouldResemble, entity.IngestStats{
+			Inserted: 1,
+			Updated:  1,
+			Skipped:  1,
+			Failed:   1,
+		})
+	})
+}
+
+func sourcedGame(externalID string) entity.SourcedGame {
+	return entity.SourcedGame{
+		Source:     "test",
+		ExternalID: externalID,
+		Game:       mockGameProperty,
+	}
+}
+
+// expectTx runs functions of WithTx with the repo itself
+func expectTx(repo *MockGamelistRepository) {
+	repo.EXPECT().
+		WithTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, fn func(repository.GamelistRepository) error) error {
+			return fn(repo)
+		}).
+		AnyTimes()
+}
+
+// blockingScraper is a GameListService which scrapes until its context is cancelled
+type blockingScraper struct {
+	GameListService
+	started chan struct{}
+}
+
+func (s *blockingScraper) ScrapeGames(ctx context.Context) (*entity.IngestStats, error) {
+	close(s.started)
+	<-ctx.Done()
+	return &entity.IngestStats{Inserted: 1}, ctx.Err()
+}
+
+func TestScrapeJobService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	repo := NewMockGamelistRepository(ctrl)
+
+	saved := make(chan entity.ScrapeJob, 1)
+	repo.EXPECT().
+		AbortStaleScrapeJobs(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, staleBefore time.Time, _ string) error {
+			if staleBefore.After(time.Now().Add(-SCRAPE_JOB_STALE_AFTER)) {
+				t.Error("running jobs which still beat shouldn't be aborted")
+			}
+			return nil
+		}).
+		Times(1)
+	repo.EXPECT().
+		CreateScrapeJob(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, job *entity.ScrapeJob) error {
+			job.ID = 1
+			return nil
+		}).
+		Times(1)
+	repo.EXPECT().
+		SaveScrapeJob(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, job entity.ScrapeJob) error {
+			saved <- job
+			return nil
+		}).
+		Times(1)
+
+	scraper := &blockingScraper{started: make(chan struct{})}
+	service := NewScrapeJobService(repo, scraper)
+
+	convey.Convey("Only one scrape job may run and it should be cancellable", t, func() {
+		job, err := service.Start(context.Background(), entity.ScrapeTriggerManual)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(job.Status, convey.ShouldEqual, entity.ScrapeJobRunning)
+
+		<-scraper.started
+
+		_, err = service.Start(context.Background(), entity.ScrapeTriggerManual)
+		convey.So(err, convey.ShouldNotBeNil)
+		convey.So(err.(*utilErrs.Error).Code(), convey.ShouldEqual, utilErrs.Conflict)
+		convey.So(err.(*utilErrs.Error).Reason(), convey.ShouldEqual, utilErrs.ReasonScrapeJobRunning)
+
+		convey.So(service.Cancel(2), convey.ShouldNotBeNil)
+		convey.So(service.Cancel(job.ID), convey.ShouldBeNil)
+
+		finished := <-saved
+		convey.So(finished.Status, convey.ShouldEqual, entity.ScrapeJobCancelled)
+		convey.So(finished.Stats.Inserted, convey.ShouldEqual, 1)
+		convey.So(finished.FinishedAt, convey.ShouldNotBeNil)
+	})
+
+	convey.Convey("Jobs shouldn't start while a job of another instance runs", t, func() {
+		running := utilErrs.New(utilErrs.Conflict, nil, "another scrape job is already running").
+			WithReason(utilErrs.ReasonScrapeJobRunning)
+		repo.EXPECT().AbortStaleScrapeJobs(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+		repo.EXPECT().CreateScrapeJob(gomock.Any(), gomock.Any()).Return(running)
+
+		job, err := service.Start(context.Background(), entity.ScrapeTriggerManual)
+		convey.So(err, convey.ShouldEqual, running)
+		convey.So(job, convey.ShouldBeNil)
+	})
+}
+
+// fakeScrapeServer streams games and drops the first stream after dropAfter games
+type fakeScrapeServer struct {
+	pb.UnimplementedGameScrapeServer
+
+	games     []*pb.GameProperties
+	dropAfter int
+
+	mu           sync.Mutex
+	requests     []string
+	traceparents []string
+}
+
+func (s *fakeScrapeServer) ScrapeGames(req *pb.ScrapeRequest, stream pb.GameScrape_ScrapeGamesServer) error {
+	s.mu.Lock()
+	s.requests = append(s.requests, req.After)
+	if md, ok := metadata.FromIncomingContext(stream.Context()); ok {
+		s.traceparents = append(s.traceparents, md.Get("traceparent")...)
+	}
+	first := len(s.requests) == 1
+	s.mu.Unlock()
+
+	start := 0
+	for i, game := range s.games {
+		if game.ExternalId == req.After {
+			start = i + 1
+		}
+	}
+
+	for i := start; i < len(s.games); i++ {
+		if first && i == s.dropAfter {
+			return status.Error(codes.Unavailable, "stream dropped")
+		}
+		if err := stream.Send(s.games[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func newFakeScraperClient(t *testing.T, server pb.GameScrapeServer, conf ScraperConfig) ScraperClient {
+	lis := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	pb.RegisterGameScrapeServer(grpcServer, server)
+	go grpcServer.Serve(lis) //nolint:errcheck
+	t.Cleanup(grpcServer.Stop)
+
+	conf.Address = "bufnet"
+	conf.DialOptions = []grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+	}
+
+	client, err := NewScraperClient(conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	return client
+}
+
+func TestScrapeGames(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	server := &fakeScrapeServer{dropAfter: 2}
+	for _, id := range []string{"1", "2", "3", "4"} {
+		server.games = append(server.games, &pb.GameProperties{
+			Name:       "Game " + id,
+			ExternalId: id,
+		})
+	}
+
+	conf := DefaultScraperConfig
+	conf.InitialBackoff = time.Millisecond
+	conf.CallTimeout = time.Second
+	scraper := newFakeScraperClient(t, server, conf)
+
+	repo := NewMockGamelistRepository(ctrl)
+	for _, id := range []string{"1", "2", "3", "4"} {
+		repo.EXPECT().
+			UpsertGame(gomock.Any(), entity.SourcedGame{
+				Source:     GameSourceScraper,
+				ExternalID: id,
+				Game: entity.GameProperties{
+					Name:      "Game " + id,
+					Platforms: []entity.Platform{},
+					Genres:    []entity.Genre{},
+				},
+			}, gomock.Any()).
+			Return(entity.IngestInserted, nil).
+			Times(1)
+	}
+
+	service := NewGameListService(repo, []GameSource{NewScraperSource(GameSourceScraper, scraper)}, nil, nil, nil)
+
+	convey.Convey("service.ScrapeGames() should resume after the last received game", t, func() {
+		stats, err := service.ScrapeGames(context.Background())
+
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(stats.Inserted, convey.ShouldEqual, 4)
+		convey.So(server.requests, convey.ShouldResemble, []string{"", "2"})
+	})
+}
+
+// cancelingSource cancels the scrape while it's fetched and stops without an error
+type cancelingSource struct {
+	cancel  context.CancelFunc
+	fetched bool
+}
+
+func (s *cancelingSource) Name() string {
+	return "canceling"
+}
+
+func (s *cancelingSource) Fetch(ctx context.Context, emit func(game entity.SourcedGame) error) error {
+	s.fetched = true
+	s.cancel()
+	return nil
+}
+
+func TestCanceledScrape(t *testing.T) {
+	convey.Convey("Canceled scrapes should fail even if their source doesn't", t, func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		first, second := &cancelingSource{cancel: cancel}, &cancelingSource{cancel: cancel}
+		service := NewGameListService(nil, []GameSource{first, second}, nil, nil, nil)
+
+		stats, err := service.ScrapeGames(ctx)
+		convey.So(err, convey.ShouldEqual, context.Canceled)
+		convey.So(stats, convey.ShouldNotBeNil)
+		convey.So(first.fetched, convey.ShouldBeTrue)
+		convey.So(second.fetched, convey.ShouldBeFalse)
+	})
+}
+
+var (
+	spanRecorder     = tracetest.NewSpanRecorder()
+	spanRecorderOnce sync.Once
+)
+
+// recordSpans returns spans ended since the call. The provider is only set once,
+// since tracers of packages stick to the first one.
+func recordSpans() func() []sdktrace.ReadOnlySpan {
+	spanRecorderOnce.Do(func() {
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)))
+	})
+
+	start := len(spanRecorder.Ended())
+	return func() []sdktrace.ReadOnlySpan {
+		return spanRecorder.Ended()[start:]
+	}
+}
+
+func TestScrapeTracing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	ended := recordSpans()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	server := &fakeScrapeServer{games: []*pb.GameProperties{{Name: "Game 1", ExternalId: "1"}}, dropAfter: 1}
+	conf := DefaultScraperConfig
+	conf.CallTimeout = time.Second
+	scraper := newFakeScraperClient(t, server, conf)
+
+	repo := NewMockGamelistRepository(ctrl)
+	repo.EXPECT().UpsertGame(gomock.Any(), gomock.Any(), gomock.Any()).Return(entity.IngestInserted, nil)
+
+	service := NewGameListService(repo, []GameSource{NewScraperSource(GameSourceScraper, scraper)}, nil, nil, nil)
+
+	convey.Convey("Scraping should be traced into the scraper", t, func() {
+		_, err := service.ScrapeGames(context.Background())
+		convey.So(err, convey.ShouldBeNil)
+
+		// Spans of streams end in the background once their calls are done
+		spans := map[string]sdktrace.ReadOnlySpan{}
+		for deadline := time.Now().Add(time.Second); spans["proto.GameScrape/ScrapeGames"] == nil && time.Now().Before(deadline); {
+			time.Sleep(time.Millisecond)
+			for _, span := range ended() {
+				spans[span.Name()] = span
+			}
+		}
+		scrape, ingest, call := spans["GameListService.ScrapeGames"], spans["GameListService.IngestGames"], spans["proto.GameScrape/ScrapeGames"]
+		convey.So(scrape, convey.ShouldNotBeNil)
+		convey.So(ingest, convey.ShouldNotBeNil)
+		convey.So(call, convey.ShouldNotBeNil)
+		convey.So(ingest.Parent().SpanID(), convey.ShouldEqual, scrape.SpanContext().SpanID())
+		convey.So(call.Parent().SpanID(), convey.ShouldEqual, ingest.SpanContext().SpanID())
+
+		// The scraper gets the context of the call in metadata
+		convey.So(server.traceparents, convey.ShouldHaveLength, 1)
+		convey.So(server.traceparents[0], convey.ShouldContainSubstring, call.SpanContext().TraceID().String())
+		convey.So(server.traceparents[0], convey.ShouldContainSubstring, call.SpanContext().SpanID().String())
+	})
+}
+
+func TestServiceSpans(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	ended := recordSpans()
+
+	repo := NewMockGamelistRepository(ctrl)
+	service := NewGameListService(repo, nil, nil, nil, nil)
+
+	convey.Convey("Calls of the service should have spans with their errors", t, func() {
+		repo.EXPECT().GetGameDetails(gomock.Any(), "test", uint64(7)).
+			Return(nil, utilErrs.New(utilErrs.NotFound, nil, "game not found"))
+		repo.EXPECT().GetUserGameList(gomock.Any(), "test", entity.Cursor{}, entity.PAGE_SIZE_LIMIT).Return(nil, nil)
+
+		ctx, parent := otel.Tracer("test").Start(context.Background(), "request")
+		_, err := service.GetGameDetails(ctx, "test", 7)
+		convey.So(err, convey.ShouldNotBeNil)
+		_, err = service.GetUserGameList(ctx, "test", entity.PageRequest{})
+		convey.So(err, convey.ShouldBeNil)
+		parent.End()
+
+		spans := ended()
+		convey.So(spans, convey.ShouldHaveLength, 3)
+		convey.So(spans[0].Name(), convey.ShouldEqual, "GameListService.GetGameDetails")
+		convey.So(spans[0].Parent().SpanID(), convey.ShouldEqual, parent.SpanContext().SpanID())
+		convey.So(spans[0].Status().Description, convey.ShouldEqual, "game not found")
+		convey.So(spans[1].Name(), convey.ShouldEqual, "GameListService.GetUserGameList")
+		convey.So(spans[1].Status().Description, convey.ShouldBeEmpty)
+	})
+}
+
+func TestAbandonedStreamSpans(t *testing.T) {
+	ended := recordSpans()
+
+	server := &fakeScrapeServer{games: []*pb.GameProperties{
+		{Name: "Game 1", ExternalId: "1"},
+		{Name: "Game 2", ExternalId: "2"},
+	}, dropAfter: 2}
+	// Calls have no deadline of their own
+	scraper := newFakeScraperClient(t, server, DefaultScraperConfig)
+
+	convey.Convey("Spans of streams should end when their consumer stops early", t, func() {
+		err := scraper.Scrape(context.Background(), func(game *pb.GameProperties) error {
+			return errors.New("failed to save game")
+		})
+		convey.So(err, convey.ShouldNotBeNil)
+
+		var call sdktrace.ReadOnlySpan
+		for deadline := time.Now().Add(time.Second); call == nil && time.Now().Before(deadline); {
+			time.Sleep(time.Millisecond)
+			for _, span := range ended() {
+				if span.Name() == "proto.GameScrape/ScrapeGames" {
+					call = span
+				}
+			}
+		}
+		convey.So(call, convey.ShouldNotBeNil)
+	})
+}
+
+func TestScrapeGamesGivesUp(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	conf := DefaultScraperConfig
+	conf.InitialBackoff = time.Millisecond
+	conf.MaxRetries = 2
+	scraper := newFakeScraperClient(t, &fakeScrapeServer{games: []*pb.GameProperties{{Name: "Game", ExternalId: "1"}}}, conf)
+
+	convey.Convey("scraper.Scrape() should stop on a handler error without retries", t, func() {
+		calls := 0
+		err := scraper.Scrape(context.Background(), func(game *pb.GameProperties) error {
+			calls++
+			return errors.New("test error")
+		})
+
+		convey.So(err, convey.ShouldNotBeNil)
+		convey.So(calls, convey.ShouldEqual, 1)
+	})
+
+	convey.Convey("scraper.Scrape() should give up after max retries", t, func() {
+		server := &unavailableScrapeServer{code: codes.Unavailable}
+		unavailable := newFakeScraperClient(t, server, conf)
+		start := time.Now()
+		err := unavailable.Scrape(context.Background(), func(game *pb.GameProperties) error {
+			return nil
+		})
+
+		convey.So(err, convey.ShouldNotBeNil)
+		convey.So(server.calls.Load(), convey.ShouldEqual, conf.MaxRetries+1)
+		// Backoffs of 1ms and 2ms
+		convey.So(time.Since(start), convey.ShouldBeGreaterThanOrEqualTo, 3*conf.InitialBackoff)
+
+		convey.Convey("But not retry errors which won't go away", func() {
+			server := &unavailableScrapeServer{code: codes.Unimplemented}
+			unimplemented := newFakeScraperClient(t, server, conf)
+			err := unimplemented.Scrape(context.Background(), func(game *pb.GameProperties) error {
+				return nil
+			})
+
+			convey.So(err, convey.ShouldNotBeNil)
+			convey.So(server.calls.Load(), convey.ShouldEqual, 1)
+		})
+	})
+}
+
+// unavailableScrapeServer fails every call with its code
+type unavailableScrapeServer struct {
+	pb.UnimplementedGameScrapeServer
+
+	code  codes.Code
+	calls atomic.Int32
+}
+
+func (s *unavailableScrapeServer) ScrapeGames(req *pb.ScrapeRequest, stream pb.GameScrape_ScrapeGamesServer) error {
+	s.calls.Add(1)
+	return status.Error(s.code, "scraper is down")
+}
+
+func collectGames(source GameSource) ([]entity.SourcedGame, error) {
+	var games []entity.SourcedGame
+	err := source.Fetch(context.Background(), func(game entity.SourcedGame) error {
+		games = append(games, game)
+		return nil
+	})
+
+	return games, err
+}
+
+func TestGameSources(t *testing.T) {
+	expected := []entity.SourcedGame{
+		{
+			Source:     "test",
+			ExternalID: "w3",
+			Game: entity.GameProperties{
+				Name:         "The Witcher 3",
+				ImageURL:     "https://example.com/w3.png",
+				YearReleased: 2015,
+				Platforms:    []entity.Platform{{Name: "PC"}, {Name: "PS4"}},
+				Genres:       []entity.Genre{{Name: "RPG"}},
+			},
+		},
+		{
+			Source:     "test",
+			ExternalID: "Portal",
+			Game: entity.GameProperties{
+				Name:      "Portal",
+				Platforms: []entity.Platform{},
+				Genres:    []entity.Genre{},
+			},
+		},
+	}
+
+	jsonFeed := `[
+		{
+			"external_id": "w3",
+			"name": "The Witcher 3",
+			"image_url": "https://example.com/w3.png",
+			"year_released": 2015,
+			"platforms": ["PC", "PS4"],
+			"genres": ["RPG"]
+		},
+		{"name": "Portal"}
+	]`
+	csvFeed := "external_id,name,image_url,year_released,platforms,genres\n" +
+		"w3,The Witcher 3,https://example.com/w3.png,2015,PC;PS4,RPG\n" +
+		",Portal,,,,\n"
+
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "games.json")
+	csvPath := filepath.Join(dir, "games.csv")
+	if err := os.WriteFile(jsonPath, []byte(jsonFeed), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(csvPath, []byte(csvFeed), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(jsonFeed)) //nolint:errcheck
+	}))
+	defer httpServer.Close()
+
+	convey.Convey("JSON file source should emit every game", t, func() {
+		games, err := collectGames(NewFileSource("test", jsonPath))
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(games, convey.ShouldResemble, expected)
+	})
+
+	convey.Convey("CSV file source should emit every game", t, func() {
+		games, err := collectGames(NewFileSource("test", csvPath))
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(games, convey.ShouldResemble, expected)
+	})
+
+	convey.Convey("HTTP source should emit every game", t, func() {
+		games, err := collectGames(NewHTTPSource("test", httpServer.URL, httpServer.Client()))
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(games, convey.ShouldResemble, expected)
+	})
+
+	convey.Convey("HTTP source should give up on stalled feeds", t, func() {
+		stalled := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		}))
+		defer stalled.Close()
+
+		source, err := NewGameSource(GameSourceConfig{
+			Name:     "test",
+			Kind:     GameSourceHTTP,
+			Location: stalled.URL,
+			Timeout:  50 * time.Millisecond,
+		}, nil)
+		convey.So(err, convey.ShouldBeNil)
+
+		_, err = collectGames(source)
+		convey.So(err, convey.ShouldNotBeNil)
+	})
+
+	convey.Convey("ParseGameSources() should parse every kind of source", t, func() {
+		sources, err := ParseGameSources("scraper, catalog=file:/data/games.csv,feed=http:https://example.com/games.json")
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(sources, convey.ShouldResemble, []GameSourceConfig{
+			{Name: "scraper", Kind: GameSourceScraper},
+			{Name: "catalog", Kind: GameSourceFile, Location: "/data/games.csv"},
+			{Name: "feed", Kind: GameSourceHTTP, Location: "https://example.com/games.json"},
+		})
+
+		_, err = ParseGameSources("catalog=file")
+		convey.So(err, convey.ShouldNotBeNil)
+	})
+}
+
+func TestSourcePriority(t *testing.T) {
+	convey.Convey("SourcePriority should decide per field", t, func() {
+		priority, err := entity.ParseSourcePriority("*=scraper,feed;image_url=feed,scraper")
+		convey.So(err, convey.ShouldBeNil)
+
+		convey.So(priority.Wins(entity.GameFieldName, "feed", ""), convey.ShouldBeTrue)
+		convey.So(priority.Wins(entity.GameFieldName, "feed", "scraper"), convey.ShouldBeFalse)
+		convey.So(priority.Wins(entity.GameFieldName, "scraper", "feed"), convey.ShouldBeTrue)
+		convey.So(priority.Wins(entity.GameFieldImageURL, "feed", "scraper"), convey.ShouldBeTrue)
+		convey.So(priority.Wins(entity.GameFieldImageURL, "scraper", "feed"), convey.ShouldBeFalse)
+		convey.So(priority.Wins(entity.GameFieldName, "unknown", "feed"), convey.ShouldBeFalse)
+
+		_, err = entity.ParseSourcePriority("rating=feed")
+		convey.So(err, convey.ShouldNotBeNil)
+	})
+}
+
+func TestFindDuplicates(t *testing.T) {
+	pc, ps4, xbox := entity.Platform{Name: "PC"}, entity.Platform{Name: "PS4"}, entity.Platform{Name: "Xbox One"}
+	game := func(id uint64, name string, year uint16, platforms ...entity.Platform) entity.GameProperties {
+		return entity.GameProperties{
+			Model:        entity.Model{ID: id},
+			Name:         name,
+			YearReleased: year,
+			Platforms:    platforms,
+		}
+	}
+
+	convey.Convey("Game names should be normalized", t, func() {
+		convey.So(helpers.NormalizeGameName("The Witcher® 3: Wild Hunt - GOTY Edition"), convey.ShouldEqual, "witcher 3 wild hunt")
+		convey.So(helpers.NormalizeGameName("Final Fantasy VII"), convey.ShouldEqual, "final fantasy 7")
+		convey.So(helpers.BaseGameName("The Witcher 3: Wild Hunt"), convey.ShouldEqual, "witcher 3")
+		convey.So(helpers.BaseGameName("Assassin's Creed - Director's Cut"), convey.ShouldEqual, "assassins creed")
+	})
+
+	convey.Convey("FindDuplicates() should find the same games only", t, func() {
+		duplicates := FindDuplicates([]entity.GameProperties{
+			game(1, "The Witcher 3", 2015, pc, ps4),
+			game(2, "Doom", 1993, pc),
+			game(3, "The Witcher 3: Wild Hunt", 2015, pc, ps4, xbox),
+			game(4, "DOOM", 2016, ps4, xbox),
+			game(5, "Portal", 2007, pc),
+		})
+
+		convey.So(len(duplicates), convey.ShouldEqual, 1)
+		convey.So(duplicates[0].GameID, convey.ShouldEqual, 1)
+		convey.So(duplicates[0].DuplicateID, convey.ShouldEqual, 3)
+		convey.So(duplicates[0].Status, convey.ShouldEqual, entity.DuplicatePending)
+	})
+}
+
+func TestImportGameList(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Every call of the import goes through the transaction
+	repo := NewMockGamelistRepository(ctrl)
+	tx := NewMockGamelistRepository(ctrl)
+	repo.EXPECT().
+		WithTx(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, fn func(repository.GamelistRepository) error) error {
+			return fn(tx)
+		}).
+		AnyTimes()
+	service := NewGameListService(repo, nil, nil, nil, nil)
+
+	titles := []entity.GameTitle{
+		{ID: 1, Name: "The Witcher 3: Wild Hunt", YearReleased: 2015},
+		{ID: 2, Name: "Doom", YearReleased: 1993},
+		{ID: 3, Name: "DOOM", YearReleased: 2016},
+		{ID: 4, Name: "Portal 2", YearReleased: 2011},
+		{ID: 5, Name: "Portal", YearReleased: 2007},
+	}
+	listTypes := []entity.ListType{
+		{Model: entity.Model{ID: 1}, Name: "Played"},
+		{Model: entity.Model{ID: 2}, Name: "Playing"},
+		{Model: entity.Model{ID: 3}, Name: "Want to play"},
+	}
+
+	convey.Convey("Only unambiguous entries should be listed", t, func() {
+		tx.EXPECT().GetGameTitles(gomock.Any()).Return(titles, nil)
+		tx.EXPECT().GetAllListTypes(gomock.Any(), entity.Cursor{}, 0).Return(listTypes, nil)
+		tx.EXPECT().ListGames(gomock.Any(), "test", []entity.GameListRequest{
+			{GameId: 1, ListType: 1},
+			{GameId: 3, ListType: 2},
+			{GameId: 5, ListType: 3},
+		}).Return(nil)
+
+		result, err := service.ImportGameList(context.Background(), "test", []entity.ListEntry{
+			{Title: "The Witcher 3 - Wild Hunt (GOTY Edition)", ListType: "Completed"},
+			{Title: "Doom", YearReleased: 2016, ListType: "playing"},
+			{Title: "Doom", ListType: "Played"},
+			{Title: "Portl 2", ListType: "Played"},
+			{GameID: 5, ListType: "Plan to Play"},
+			{Title: "Half-Life", ListType: "Played"},
+			{Title: "Portal", ListType: "Abandoned"},
+		}, false)
+
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(result.Imported, convey.ShouldEqual, 3)
+		convey.So(len(result.Review), convey.ShouldEqual, 4)
+
+		convey.So(result.Review[0].Status, convey.ShouldEqual, entity.ImportAmbiguous)
+		convey.So(len(result.Review[0].Candidates), convey.ShouldEqual, 2)
+		convey.So(result.Review[1].Status, convey.ShouldEqual, entity.ImportAmbiguous)
+		convey.So(result.Review[1].Candidates[0].ID, convey.ShouldEqual, 4)
+		convey.So(result.Review[2].Status, convey.ShouldEqual, entity.ImportUnmatched)
+		convey.So(result.Review[3].Status, convey.ShouldEqual, entity.ImportInvalid)
+	})
+
+	convey.Convey("Dry run shouldn't list anything", t, func() {
+		tx.EXPECT().GetGameTitles(gomock.Any()).Return(titles, nil)
+		tx.EXPECT().GetAllListTypes(gomock.Any(), entity.Cursor{}, 0).Return(listTypes, nil)
+
+		result, err := service.ImportGameList(context.Background(), "test", []entity.ListEntry{{Title: "Portal", ListType: "Played"}}, true)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(result.Imported, convey.ShouldEqual, 1)
+	})
+
+	convey.Convey("Imports of items deleted meanwhile should fail", t, func() {
+		missing := utilErrs.New(utilErrs.BadInput, nil, "failed to list games: list type refers to a missing item").
+			WithReason(utilErrs.ReasonReferenceNotFound)
+		tx.EXPECT().GetGameTitles(gomock.Any()).Return(titles, nil)
+		tx.EXPECT().GetAllListTypes(gomock.Any(), entity.Cursor{}, 0).Return(listTypes, nil)
+		tx.EXPECT().ListGames(gomock.Any(), "test", []entity.GameListRequest{{GameId: 5, ListType: 1}}).Return(missing)
+
+		result, err := service.ImportGameList(context.Background(), "test", []entity.ListEntry{{Title: "Portal", ListType: "Played"}}, false)
+		convey.So(err, convey.ShouldEqual, missing)
+		convey.So(result, convey.ShouldBeNil)
+	})
+}
+
+func TestListEntryFormats(t *testing.T) {
+	entries := []entity.ListEntry{
+		{GameID: 1, Title: "The Witcher 3: Wild Hunt", YearReleased: 2015, ListType: "Played"},
+		{GameID: 2, Title: "Doom, \"Eternal\"", ListType: "Want to play"},
+	}
+
+	convey.Convey("Exported lists should be imported back", t, func() {
+		for _, format := range []string{entity.ListFormatCSV, entity.ListFormatJSON, entity.ListFormatXML} {
+			var buf bytes.Buffer
+			err := EncodeListEntries(format, &buf, "test", entries)
+			convey.So(err, convey.ShouldBeNil)
+
+			decoded, err := DecodeListEntries(format, &buf)
+			convey.So(err, convey.ShouldBeNil)
+			convey.So(decoded, convey.ShouldResemble, entries)
+		}
+	})
+
+	convey.Convey("MyAnimeList exports should be read", t, func() {
+		mal := `<?xml version="1.0" encoding="UTF-8" ?>
+			<myanimelist>
+				<myinfo><user_export_type>1</user_export_type></myinfo>
+				<anime>
+					<series_animedb_id>1</series_animedb_id>
+					<series_title><![CDATA[Cowboy Bebop]]></series_title>
+					<my_status>Completed</my_status>
+				</anime>
+			</myanimelist>`
+
+		decoded, err := DecodeListEntries(entity.ListFormatXML, strings.NewReader(mal))
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(decoded, convey.ShouldResemble, []entity.ListEntry{{Title: "Cowboy Bebop", ListType: "Completed"}})
+	})
+
+	convey.Convey("Spreadsheets with a name column should be read", t, func() {
+		decoded, err := DecodeListEntries(entity.ListFormatCSV, strings.NewReader("Name,List_Type\nPortal,Played\n"))
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(decoded, convey.ShouldResemble, []entity.ListEntry{{Title: "Portal", ListType: "Played"}})
+	})
+
+	convey.Convey("Unknown formats should be rejected", t, func() {
+		_, err := DecodeListEntries("yaml", strings.NewReader(""))
+		convey.So(err, convey.ShouldNotBeNil)
+	})
+}
+
+func TestAccountService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockGamelistRepository(ctrl)
+	gracePeriod := 48 * time.Hour
+	service := NewAccountService(repo, NewGameListService(repo, nil, nil, nil, nil), gracePeriod)
+
+	convey.Convey("Archive should contain all the user's data", t, func() {
+		created := time.Date(2021, 10, 10, 0, 0, 0, 0, time.UTC)
+		revoked := created.Add(time.Hour)
+
+		repo.EXPECT().GetAccount(gomock.Any(), "test").Return(&entity.Profile{
+			ProfileInfo: entity.ProfileInfo{
+				Model:       entity.Model{ID: 1, CreatedAt: created},
+				Nickname:    "test",
+				GamesListed: 1,
+				Socials: []entity.Social{
+					{TypeID: 1, Type: entity.SocialType{Name: "Twitter"}, Data: "@test"},
+				},
+			},
+			Email:    "test@example.com",
+			Password: "hash",
+			RefreshTokens: []entity.RefreshToken{
+				{ID: 1, CreatedAt: created, DeletedAt: gorm.DeletedAt{Time: revoked, Valid: true}, Token: "old"},
+				{ID: 2, CreatedAt: revoked, Token: "current"},
+			},
+		}, nil)
+		repo.EXPECT().GetUserGameList(gomock.Any(), "test", entity.Cursor{}, 0).Return([]entity.TypedGameListProperties{
+			{GameProperties: entity.GameProperties{Model: entity.Model{ID: 5}, Name: "Portal", YearReleased: 2007}, ListTypeID: 1},
+		}, nil)
+		repo.EXPECT().GetAllListTypes(gomock.Any(), entity.Cursor{}, 0).Return([]entity.ListType{{Model: entity.Model{ID: 1}, Name: "Played"}}, nil)
+
+		archive, err := service.ExportAccount(context.Background(), "test")
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(archive.Profile.Email, convey.ShouldEqual, "test@example.com")
+		convey.So(archive.Socials, convey.ShouldResemble, []entity.AccountSocial{{Type: "Twitter", Data: "@test"}})
+		convey.So(archive.Games, convey.ShouldResemble, []entity.ListEntry{
+			{GameID: 5, Title: "Portal", YearReleased: 2007, ListType: "Played"},
+		})
+		convey.So(archive.Sessions, convey.ShouldResemble, []entity.AccountSession{
+			{ID: 1, CreatedAt: created, RevokedAt: &revoked},
+			{ID: 2, CreatedAt: revoked},
+		})
+	})
+
+	convey.Convey("Accounts should be purged after the grace period", t, func() {
+		requested := time.Now().Add(-time.Hour)
+		repo.EXPECT().RequestProfileDeletion(gomock.Any(), "test", gomock.Any()).Return(requested, nil)
+
+		deletion, err := service.RequestDeletion(context.Background(), "test")
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(deletion.PurgeAt, convey.ShouldEqual, requested.Add(gracePeriod))
+
+		var before time.Time
+		repo.EXPECT().PurgeProfiles(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, requestedBefore time.Time) (int64, error) {
+			before = requestedBefore
+			return 1, nil
+		})
+
+		purged, err := service.Purge(context.Background())
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(purged, convey.ShouldEqual, 1)
+		convey.So(before, convey.ShouldHappenWithin, time.Minute, time.Now().Add(-gracePeriod))
+	})
+}
+
+func TestPagination(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockGamelistRepository(ctrl)
+	limits, err := entity.ParsePageLimits("*=50; my-games=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := NewGameListService(repo, nil, nil, limits, nil)
+
+	games := []entity.TypedGameListProperties{
+		{GameProperties: entity.GameProperties{Model: entity.Model{ID: 3}, Name: "Doom"}},
+		{GameProperties: entity.GameProperties{Model: entity.Model{ID: 1}, Name: "Portal"}},
+		{GameProperties: entity.GameProperties{Model: entity.Model{ID: 2}, Name: "Quake"}},
+	}
+
+	convey.Convey("Pages should be limited and point to the next page", t, func() {
+		repo.EXPECT().GetUserGameList(gomock.Any(), "test", entity.Cursor{}, 2).Return(games, nil)
+
+		page, err := service.GetUserGameList(context.Background(), "test", entity.PageRequest{Limit: 10})
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(page.Items, convey.ShouldResemble, games[:2])
+
+		cursor, err := entity.DecodeCursor(page.NextCursor)
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(cursor, convey.ShouldResemble, entity.Cursor{Key: "Portal", ID: 1})
+
+		repo.EXPECT().GetUserGameList(gomock.Any(), "test", cursor, 2).Return(games[2:], nil)
+
+		page, err = service.GetUserGameList(context.Background(), "test", entity.PageRequest{Cursor: page.NextCursor})
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(page.Items, convey.ShouldResemble, games[2:])
+		convey.So(page.NextCursor, convey.ShouldBeEmpty)
+	})
+
+	convey.Convey("Game queries should filter pages by name", t, func() {
+		repo.EXPECT().GetAllGamesTyped(gomock.Any(), "test", "Po", entity.Cursor{}, 50).Return(games[1:2], nil)
+
+		page, err := service.GetAllGamesTyped(context.Background(), "test", entity.GamesRequest{Query: "Po"})
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(page.Items, convey.ShouldResemble, games[1:2])
+		convey.So(page.NextCursor, convey.ShouldBeEmpty)
+	})
+
+	convey.Convey("Other collections should get the default limit", t, func() {
+		repo.EXPECT().GetAllGenres(gomock.Any(), entity.Cursor{}, 50).Return(nil, nil)
+
+		page, err := service.GetAllGenres(context.Background(), entity.PageRequest{Limit: 1000})
+		convey.So(err, convey.ShouldBeNil)
+		convey.So(page.Items, convey.ShouldNotBeNil)
+		convey.So(page.Items, convey.ShouldBeEmpty)
+	})
+
+	convey.Convey("Wrong cursors and limits should be rejected", t, func() {
+		_, err := service.GetAllGenres(context.Background(), entity.PageRequest{Cursor: "not a cursor"})
+		convey.So(err, convey.ShouldNotBeNil)
+
+		_, err = entity.ParsePageLimits("games=0")
+		convey.So(err.(*utilErrs.Error).Reason(), convey.ShouldEqual, utilErrs.ReasonInvalidPageLimit)
+		_, err = entity.ParsePageLimits("reviews=10")
+		convey.So(err.(*utilErrs.Error).Reason(), convey.ShouldEqual, utilErrs.ReasonInvalidPageLimit)
+	})
+}
+
+func TestEventBus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repo := NewMockGamelistRepository(ctrl)
+	expectTx(repo)
+	bus := NewEventBus(repository.NewLocalEventBackend())
+	defer bus.Close()
+	service := NewGameListService(repo, nil, nil, nil, bus)
+
+	receive := func(events <-chan entity.Event) (entity.Event, bool) {
+		select {
+		case event := <-events:
+			return event, true
+		case <-time.After(time.Second):
+			return entity.Event{}, false
+		}
+	}
+
+	convey.Convey("List changes should be sent to subscribers of the user only", t, func() {
+		events, unsubscribe := bus.Subscribe("test")
+		defer unsubscribe()
+		others, unsubscribeOthers := bus.Subscribe("other")
+		defer unsubscribeOthers()
+
+		repo.EXPECT().ListGame(gomock.Any(), "test", uint64(3), uint64(0)).Return(nil)
+		convey.So(service.ListGame(context.Background(), "test", 3, 0), convey.ShouldBeNil)
+
+		event, ok := receive(events)
+		convey.So(ok, convey.ShouldBeTrue)
+		convey.So(event.Type, convey.ShouldEqual, entity.EventListUpdated)
+		convey.So(event.GameID, convey.ShouldEqual, 3)
+		convey.So(*event.ListType, convey.ShouldEqual, 0)
+		convey.So(event.Time.IsZero(), convey.ShouldBeFalse)
+
+		repo.EXPECT().SaveProfile(gomock.Any(), gomock.Any()).Return(nil)
+		convey.So(service.SaveProfile(context.Background(), entity.Profile{ProfileInfo: entity.ProfileInfo{Nickname: "other"}}), convey.ShouldBeNil)
+
+		event, ok = receive(others)
+		convey.So(ok, convey.ShouldBeTrue)
+		convey.So(event.Type, convey.ShouldEqual, entity.EventProfileUpdated)
+		convey.So(len(events), convey.ShouldEqual, 0)
+	})
+
+	convey.Convey("Failed changes shouldn't be published", t, func() {
+		events, unsubscribe := bus.Subscribe("test")
+		defer unsubscribe()
+
+		repo.EXPECT().ListGame(gomock.Any(), "test", uint64(3), uint64(1)).Return(errors.New("failed"))
+		convey.So(service.ListGame(context.Background(), "test", 3, 1), convey.ShouldNotBeNil)
+		convey.So(len(events), convey.ShouldEqual, 0)
+	})
+
+	convey.Convey("Slow subscribers should lose events instead of blocking", t, func() {
+		events, unsubscribe := bus.Subscribe("test")
+
+		for i := 0; i < EVENT_BUFFER+5; i++ {
+			bus.Publish(entity.Event{Type: entity.EventListUpdated, Nickname: "test", GameID: uint64(i)})
+		}
+		convey.So(len(events), convey.ShouldEqual, EVENT_BUFFER)
+
+		unsubscribe()
+		unsubscribe()
+		for range events {
+		}
+	})
+
+	convey.Convey("Closing the bus should end streams of subscribers", t, func() {
+		bus := NewEventBus(repository.NewLocalEventBackend())
+		events, unsubscribe := bus.Subscribe("test")
+
+		bus.Close()
+		_, ok := <-events
+		convey.So(ok, convey.ShouldBeFalse)
+		unsubscribe()
+
+		late, _ := bus.Subscribe("test")
+		_, ok = <-late
+		convey.So(ok, convey.ShouldBeFalse)
+		bus.Close()
+	})
+}
+
+func TestHealthService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	defer ctrl.Finish()
+
+	convey.Convey("Readiness should check the database and its migrations", t, func() {
+		repo := NewMockGamelistRepository(ctrl)
+		health := NewHealthService(repo, HealthConfig{SchemaVersion: 20261019140000})
+
+		repo.EXPECT().Ping(gomock.Any()).Return(nil)
+		repo.EXPECT().SchemaVersion(gomock.Any()).Return(int64(20261019140000), nil)
+		report := health.Readiness(context.Background())
+		convey.So(report.Status, convey.ShouldEqual, entity.HealthOK)
+		convey.So(report.Checks, convey.ShouldHaveLength, 2)
+		convey.So(report.Checks[HEALTH_CHECK_MIGRATIONS].Details["applied"], convey.ShouldEqual, 20261019140000)
+
+		convey.Convey("Missing migrations or a failed ping should make it unready", func() {
+			repo.EXPECT().Ping(gomock.Any()).Return(utilErrs.New(utilErrs.Internal, errors.New("dial tcp 10.0.0.2:5432"), "failed to ping database"))
+			repo.EXPECT().SchemaVersion(gomock.Any()).Return(int64(20261019130000), nil)
+			report := health.Readiness(context.Background())
+			convey.So(report.Status, convey.ShouldEqual, entity.HealthFailed)
+			convey.So(report.Checks[HEALTH_CHECK_DATABASE].Status, convey.ShouldEqual, entity.HealthFailed)
+			convey.So(report.Checks[HEALTH_CHECK_DATABASE].Error, convey.ShouldEqual, "failed to ping database")
+			convey.So(report.Checks[HEALTH_CHECK_MIGRATIONS].Status, convey.ShouldEqual, entity.HealthFailed)
+		})
+
+		convey.Convey("A draining server shouldn't be ready", func() {
+			health.Drain()
+			repo.EXPECT().Ping(gomock.Any()).Return(nil)
+			repo.EXPECT().SchemaVersion(gomock.Any()).Return(int64(20261019150000), nil)
+			convey.So(health.Readiness(context.Background()).Status, convey.ShouldEqual, entity.HealthDraining)
+			convey.So(health.Liveness().Status, convey.ShouldEqual, entity.HealthOK)
+		})
+	})
+
+	convey.Convey("Readiness should check the scraper if it's set", t, func() {
+		repo := NewMockGamelistRepository(ctrl)
+		repo.EXPECT().Ping(gomock.Any()).Return(nil).Times(2)
+		repo.EXPECT().SchemaVersion(gomock.Any()).Return(int64(1), nil).Times(2)
+
+		scraper := newFakeScraperClient(t, &fakeScrapeServer{}, DefaultScraperConfig)
+		report := NewHealthService(repo, HealthConfig{SchemaVersion: 1, Scraper: scraper}).Readiness(context.Background())
+		convey.So(report.Status, convey.ShouldEqual, entity.HealthOK)
+		convey.So(report.Checks, convey.ShouldContainKey, HEALTH_CHECK_SCRAPER)
+
+		unreachable, err := NewScraperClient(ScraperConfig{Address: "localhost:1"})
+		convey.So(err, convey.ShouldBeNil)
+		defer unreachable.Close()
+		report = NewHealthService(repo, HealthConfig{
+			SchemaVersion: 1,
+			Scraper:       unreachable,
+			CheckTimeout:  100 * time.Millisecond,
+		}).Readiness(context.Background())
+		convey.So(report.Status, convey.ShouldEqual, entity.HealthFailed)
+		convey.So(report.Checks[HEALTH_CHECK_SCRAPER].Error, convey.ShouldContainSubstring, "isn't reachable")
+		convey.So(report.Checks[HEALTH_CHECK_SCRAPER].Error, convey.ShouldNotContainSubstring, "localhost:1")
+	})
+}
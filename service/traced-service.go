@@ -0,0 +1,390 @@
+package service
+
+import (
+	"context"
+
+	"github.com/br3w0r/gamelist-backend/entity"
+	"github.com/br3w0r/gamelist-backend/util/tracing"
+)
+
+// tracedGameListService starts a span for each call of the service. Scraping
+// starts spans of its own with stats of the sources.
+type tracedGameListService struct {
+	GameListService
+}
+
+func (s *tracedGameListService) SaveGame(ctx context.Context, game *entity.GameProperties) error {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.SaveGame")
+	err := s.GameListService.SaveGame(ctx, game)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedGameListService) UpdateGame(ctx context.Context, game *entity.GameProperties) error {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.UpdateGame")
+	err := s.GameListService.UpdateGame(ctx, game)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedGameListService) DeleteGame(ctx context.Context, id uint64, reassignTo uint64) error {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.DeleteGame")
+	err := s.GameListService.DeleteGame(ctx, id, reassignTo)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedGameListService) RestoreGame(ctx context.Context, id uint64) error {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.RestoreGame")
+	err := s.GameListService.RestoreGame(ctx, id)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedGameListService) GetGame(ctx context.Context, id uint64) (*entity.GameProperties, error) {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.GetGame")
+	result, err := s.GameListService.GetGame(ctx, id)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *tracedGameListService) GetGames(ctx context.Context, page entity.PageRequest) (*entity.Page[entity.GameProperties], error) {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.GetGames")
+	result, err := s.GameListService.GetGames(ctx, page)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *tracedGameListService) GetAllGamesTyped(ctx context.Context, nickname string, request entity.GamesRequest) (*entity.Page[entity.TypedGameListProperties], error) {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.GetAllGamesTyped")
+	result, err := s.GameListService.GetAllGamesTyped(ctx, nickname, request)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *tracedGameListService) GetUserGameList(ctx context.Context, nickname string, page entity.PageRequest) (*entity.Page[entity.TypedGameListProperties], error) {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.GetUserGameList")
+	result, err := s.GameListService.GetUserGameList(ctx, nickname, page)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *tracedGameListService) GetGameListsOfProfiles(ctx context.Context, profileIDs []uint64, page entity.PageRequest) (map[uint64]*entity.Page[entity.TypedGameListProperties], error) {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.GetGameListsOfProfiles")
+	result, err := s.GameListService.GetGameListsOfProfiles(ctx, profileIDs, page)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *tracedGameListService) SearchGames(ctx context.Context, name string) ([]entity.GameSearchResult, error) {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.SearchGames")
+	result, err := s.GameListService.SearchGames(ctx, name)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *tracedGameListService) GetGameDetails(ctx context.Context, nickname string, gameId uint64) (*entity.GameDetailsResponse, error) {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.GetGameDetails")
+	result, err := s.GameListService.GetGameDetails(ctx, nickname, gameId)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *tracedGameListService) GetGamesByIDs(ctx context.Context, ids []uint64) (map[uint64]entity.GameProperties, error) {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.GetGamesByIDs")
+	result, err := s.GameListService.GetGamesByIDs(ctx, ids)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *tracedGameListService) GetPlatformsOfGames(ctx context.Context, gameIDs []uint64) (map[uint64][]entity.Platform, error) {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.GetPlatformsOfGames")
+	result, err := s.GameListService.GetPlatformsOfGames(ctx, gameIDs)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *tracedGameListService) GetGenresOfGames(ctx context.Context, gameIDs []uint64) (map[uint64][]entity.Genre, error) {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.GetGenresOfGames")
+	result, err := s.GameListService.GetGenresOfGames(ctx, gameIDs)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *tracedGameListService) GetListTypesOfGames(ctx context.Context, nickname string, gameIDs []uint64) (map[uint64]uint64, error) {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.GetListTypesOfGames")
+	result, err := s.GameListService.GetListTypesOfGames(ctx, nickname, gameIDs)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *tracedGameListService) ScanDuplicates(ctx context.Context) (int, error) {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.ScanDuplicates")
+	result, err := s.GameListService.ScanDuplicates(ctx)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *tracedGameListService) GetGameDuplicates(ctx context.Context, status string, limit int) ([]entity.GameDuplicate, error) {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.GetGameDuplicates")
+	result, err := s.GameListService.GetGameDuplicates(ctx, status, limit)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *tracedGameListService) DismissGameDuplicate(ctx context.Context, gameID uint64, duplicateID uint64) error {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.DismissGameDuplicate")
+	err := s.GameListService.DismissGameDuplicate(ctx, gameID, duplicateID)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedGameListService) MergeGames(ctx context.Context, survivorID uint64, duplicateID uint64) error {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.MergeGames")
+	err := s.GameListService.MergeGames(ctx, survivorID, duplicateID)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedGameListService) CreateListType(ctx context.Context, listType *entity.ListType) error {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.CreateListType")
+	err := s.GameListService.CreateListType(ctx, listType)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedGameListService) UpdateListType(ctx context.Context, listType *entity.ListType) error {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.UpdateListType")
+	err := s.GameListService.UpdateListType(ctx, listType)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedGameListService) DeleteListType(ctx context.Context, id uint64, reassignTo uint64) error {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.DeleteListType")
+	err := s.GameListService.DeleteListType(ctx, id, reassignTo)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedGameListService) RestoreListType(ctx context.Context, id uint64) error {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.RestoreListType")
+	err := s.GameListService.RestoreListType(ctx, id)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedGameListService) GetAllListTypes(ctx context.Context, page entity.PageRequest) (*entity.Page[entity.ListType], error) {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.GetAllListTypes")
+	result, err := s.GameListService.GetAllListTypes(ctx, page)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *tracedGameListService) GetListTypesByIDs(ctx context.Context, ids []uint64) (map[uint64]entity.ListType, error) {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.GetListTypesByIDs")
+	result, err := s.GameListService.GetListTypesByIDs(ctx, ids)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *tracedGameListService) ListGame(ctx context.Context, nickname string, gameId uint64, listType uint64) error {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.ListGame")
+	err := s.GameListService.ListGame(ctx, nickname, gameId, listType)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedGameListService) ImportGameList(ctx context.Context, nickname string, entries []entity.ListEntry, dryRun bool) (*entity.ImportResult, error) {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.ImportGameList")
+	result, err := s.GameListService.ImportGameList(ctx, nickname, entries, dryRun)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *tracedGameListService) ExportGameList(ctx context.Context, nickname string) ([]entity.ListEntry, error) {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.ExportGameList")
+	result, err := s.GameListService.ExportGameList(ctx, nickname)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *tracedGameListService) SaveGenre(ctx context.Context, genre *entity.Genre) error {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.SaveGenre")
+	err := s.GameListService.SaveGenre(ctx, genre)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedGameListService) UpdateGenre(ctx context.Context, genre *entity.Genre) error {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.UpdateGenre")
+	err := s.GameListService.UpdateGenre(ctx, genre)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedGameListService) DeleteGenre(ctx context.Context, id uint64, reassignTo uint64) error {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.DeleteGenre")
+	err := s.GameListService.DeleteGenre(ctx, id, reassignTo)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedGameListService) RestoreGenre(ctx context.Context, id uint64) error {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.RestoreGenre")
+	err := s.GameListService.RestoreGenre(ctx, id)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedGameListService) GetAllGenres(ctx context.Context, page entity.PageRequest) (*entity.Page[entity.Genre], error) {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.GetAllGenres")
+	result, err := s.GameListService.GetAllGenres(ctx, page)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *tracedGameListService) SavePlatform(ctx context.Context, platform *entity.Platform) error {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.SavePlatform")
+	err := s.GameListService.SavePlatform(ctx, platform)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedGameListService) UpdatePlatform(ctx context.Context, platform *entity.Platform) error {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.UpdatePlatform")
+	err := s.GameListService.UpdatePlatform(ctx, platform)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedGameListService) DeletePlatform(ctx context.Context, id uint64, reassignTo uint64) error {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.DeletePlatform")
+	err := s.GameListService.DeletePlatform(ctx, id, reassignTo)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedGameListService) RestorePlatform(ctx context.Context, id uint64) error {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.RestorePlatform")
+	err := s.GameListService.RestorePlatform(ctx, id)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedGameListService) GetAllPlatforms(ctx context.Context, page entity.PageRequest) (*entity.Page[entity.Platform], error) {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.GetAllPlatforms")
+	result, err := s.GameListService.GetAllPlatforms(ctx, page)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *tracedGameListService) CreateProfile(ctx context.Context, profile entity.Profile) error {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.CreateProfile")
+	err := s.GameListService.CreateProfile(ctx, profile)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedGameListService) SaveProfile(ctx context.Context, profile entity.Profile) error {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.SaveProfile")
+	err := s.GameListService.SaveProfile(ctx, profile)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedGameListService) GetAllProfiles(ctx context.Context, page entity.PageRequest) (*entity.Page[entity.ProfileInfo], error) {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.GetAllProfiles")
+	result, err := s.GameListService.GetAllProfiles(ctx, page)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *tracedGameListService) GetProfileInfo(ctx context.Context, nickname string) (*entity.ProfileInfo, error) {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.GetProfileInfo")
+	result, err := s.GameListService.GetProfileInfo(ctx, nickname)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *tracedGameListService) GetSocialsOfProfiles(ctx context.Context, profileIDs []uint64) (map[uint64][]entity.Social, error) {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.GetSocialsOfProfiles")
+	result, err := s.GameListService.GetSocialsOfProfiles(ctx, profileIDs)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *tracedGameListService) CheckLogin(ctx context.Context, login entity.LoginProfile) (*entity.Profile, error) {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.CheckLogin")
+	result, err := s.GameListService.CheckLogin(ctx, login)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *tracedGameListService) SaveSocialType(ctx context.Context, socialType *entity.SocialType) error {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.SaveSocialType")
+	err := s.GameListService.SaveSocialType(ctx, socialType)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedGameListService) UpdateSocialType(ctx context.Context, socialType *entity.SocialType) error {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.UpdateSocialType")
+	err := s.GameListService.UpdateSocialType(ctx, socialType)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedGameListService) DeleteSocialType(ctx context.Context, id uint64, reassignTo uint64) error {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.DeleteSocialType")
+	err := s.GameListService.DeleteSocialType(ctx, id, reassignTo)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedGameListService) RestoreSocialType(ctx context.Context, id uint64) error {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.RestoreSocialType")
+	err := s.GameListService.RestoreSocialType(ctx, id)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedGameListService) GetAllSocialTypes(ctx context.Context, page entity.PageRequest) (*entity.Page[entity.SocialType], error) {
+	ctx, span := serviceTracer.Start(ctx, "GameListService.GetAllSocialTypes")
+	result, err := s.GameListService.GetAllSocialTypes(ctx, page)
+	tracing.End(span, err)
+	return result, err
+}
+
+// tracedAccountService starts a span for each call of the service
+type tracedAccountService struct {
+	AccountService
+}
+
+func (s *tracedAccountService) ExportAccount(ctx context.Context, nickname string) (*entity.AccountArchive, error) {
+	ctx, span := serviceTracer.Start(ctx, "AccountService.ExportAccount")
+	result, err := s.AccountService.ExportAccount(ctx, nickname)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *tracedAccountService) RequestDeletion(ctx context.Context, nickname string) (*entity.AccountDeletion, error) {
+	ctx, span := serviceTracer.Start(ctx, "AccountService.RequestDeletion")
+	result, err := s.AccountService.RequestDeletion(ctx, nickname)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *tracedAccountService) CancelDeletion(ctx context.Context, nickname string) error {
+	ctx, span := serviceTracer.Start(ctx, "AccountService.CancelDeletion")
+	err := s.AccountService.CancelDeletion(ctx, nickname)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *tracedAccountService) Purge(ctx context.Context) (int64, error) {
+	ctx, span := serviceTracer.Start(ctx, "AccountService.Purge")
+	result, err := s.AccountService.Purge(ctx)
+	tracing.End(span, err)
+	return result, err
+}
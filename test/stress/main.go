@@ -2,6 +2,7 @@
 package test
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strconv"
@@ -26,14 +27,14 @@ func parseEntries(op []string) (uint64, error) {
 func getGame(repo repository.GamelistRepository, n uint64) {
 	var i uint64
 	for i = 1; i <= n; i++ {
-		repo.GetGameDetails("test", i)
+		repo.GetGameDetails(context.Background(), "test", i)
 	}
 }
 
 func userCreation(repo repository.GamelistRepository, n uint64) {
 	var i uint64
 	for i = 1; i <= n; i++ {
-		repo.CreateProfile(entity.Profile{
+		repo.CreateProfile(context.Background(), entity.Profile{
 			ProfileInfo: entity.ProfileInfo{
 				Nickname: fmt.Sprintf("test%d", i),
 			},
@@ -46,21 +47,21 @@ func userCreation(repo repository.GamelistRepository, n uint64) {
 func findUser(repo repository.GamelistRepository, n uint64) {
 	var i uint64
 	for i = 1; i <= n; i++ {
-		repo.GetProfile(entity.ProfileCreds{Nickname: fmt.Sprintf("test%d", i)})
+		repo.GetProfile(context.Background(), entity.ProfileCreds{Nickname: fmt.Sprintf("test%d", i)})
 	}
 }
 
 func findGames(repo repository.GamelistRepository) {
-	games, _ := repo.GetAllGames()
+	games, _ := repo.GetGames(context.Background(), entity.Cursor{}, 0)
 
 	for _, game := range games {
 		name := game.Name[:len(game.Name)/2]
-		repo.SearchGames(name)
+		repo.SearchGames(context.Background(), name)
 	}
 }
 
 func RunStress(repo repository.GamelistRepository, options []string) {
-	repo.CreateProfile(entity.Profile{
+	repo.CreateProfile(context.Background(), entity.Profile{
 		ProfileInfo: entity.ProfileInfo{
 			Nickname: "test",
 		},
@@ -1,6 +1,10 @@
 package errors
 
-import "net/http"
+import (
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+)
 
 type errorCode uint8
 
@@ -11,6 +15,7 @@ const (
 	Timeout      errorCode = 4
 	Unauthorized errorCode = 5
 	AccessDenied errorCode = 6
+	Conflict     errorCode = 7
 )
 
 func (c errorCode) String() string {
@@ -27,6 +32,8 @@ func (c errorCode) String() string {
 		return "UNAUTHORIZED"
 	case AccessDenied:
 		return "ACCESS_DENIED"
+	case Conflict:
+		return "CONFLICT"
 	}
 
 	return "UNKNOWN"
@@ -46,7 +53,46 @@ func (c errorCode) ToHTTP() int {
 		return http.StatusUnauthorized
 	case AccessDenied:
 		return http.StatusForbidden
+	case Conflict:
+		return http.StatusConflict
 	}
 
 	return http.StatusInternalServerError
 }
+
+func (c errorCode) ToGRPC() codes.Code {
+	switch c {
+	case NotFound:
+		return codes.NotFound
+	case BadInput:
+		return codes.InvalidArgument
+	case Internal:
+		return codes.Internal
+	case Timeout:
+		return codes.DeadlineExceeded
+	case Unauthorized:
+		return codes.Unauthenticated
+	case AccessDenied:
+		return codes.PermissionDenied
+	case Conflict:
+		return codes.FailedPrecondition
+	}
+
+	return codes.Internal
+}
+
+// GRPCCode is the gRPC code of the error. Conflicts are told apart by their reasons:
+// duplicates already exist and concurrent changes abort transactions, which may be
+// retried, while items in use and running jobs fail a precondition.
+func (e *Error) GRPCCode() codes.Code {
+	if e.code == Conflict {
+		switch e.Reason() {
+		case ReasonAlreadyExists, ReasonNicknameTaken, ReasonEmailTaken:
+			return codes.AlreadyExists
+		case ReasonConcurrentChange:
+			return codes.Aborted
+		}
+	}
+
+	return e.code.ToGRPC()
+}
@@ -6,10 +6,34 @@ import (
 	"time"
 )
 
+// ErrorResponse is the JSON body of errors sent by the API
+type ErrorResponse struct {
+	Code      string       `json:"code"`
+	Reason    Reason       `json:"reason"`
+	Message   string       `json:"message"`
+	Fields    []FieldError `json:"fields,omitempty"`
+	Cause     string       `json:"cause,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+	Timestamp int64        `json:"timestamp"`
+}
+
+// FieldError is a failed validation rule of a request field
+type FieldError struct {
+	// Field is the name of the field as clients send it
+	Field string `json:"field"`
+	Rule  string `json:"rule"`
+	// Param of the rule, e.g. 6 of gte=6
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
 type Error struct {
 	code      errorCode
+	reason    Reason
 	msg       string
 	cause     error
+	fields    []FieldError
+	requestID string
 	timestamp int64
 }
 
@@ -26,6 +50,37 @@ func Newf(code errorCode, cause error, format string, args ...interface{}) *Erro
 	return New(code, cause, fmt.Sprintf(format, args...))
 }
 
+// WithReason returns a copy of the error with the reason
+func (e *Error) WithReason(reason Reason) *Error {
+	c := *e
+	c.reason = reason
+	return &c
+}
+
+// WithFields returns a copy of the error with the field errors
+func (e *Error) WithFields(fields []FieldError) *Error {
+	c := *e
+	c.fields = fields
+	return &c
+}
+
+// WithRequestID returns a copy of the error with the id of the request it's sent to
+func (e *Error) WithRequestID(requestID string) *Error {
+	c := *e
+	c.requestID = requestID
+	return &c
+}
+
+// NotFoundAs returns a copy of the error with the reason if it's a NotFound error.
+// Other errors are returned as they are.
+func (e *Error) NotFoundAs(reason Reason) *Error {
+	if e.code != NotFound {
+		return e
+	}
+
+	return e.WithReason(reason)
+}
+
 func (e *Error) Error() string {
 	return e.msg
 }
@@ -34,10 +89,32 @@ func (e *Error) Cause() error {
 	return e.cause
 }
 
+// Unwrap returns the cause, so errors.Is and errors.As see through Error
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
 func (e *Error) Code() errorCode {
 	return e.code
 }
 
+// Reason is the reason of the error or the generic reason of its code
+func (e *Error) Reason() Reason {
+	if e.reason == "" {
+		return e.code.Reason()
+	}
+
+	return e.reason
+}
+
+func (e *Error) Fields() []FieldError {
+	return e.fields
+}
+
+func (e *Error) RequestID() string {
+	return e.requestID
+}
+
 // JSON parses error to json format.
 //
 // If safe is true, cause will not be parsed
@@ -47,15 +124,13 @@ func (e *Error) JSON(safe bool) []byte {
 		cause = fmt.Sprint(e.cause)
 	}
 
-	jsonErr := struct {
-		Code      string `json:"code"`
-		Message   string `json:"message"`
-		Cause     string `json:"cause,omitempty"`
-		Timestamp int64  `json:"timestamp"`
-	}{
+	jsonErr := ErrorResponse{
 		Code:      e.code.String(),
+		Reason:    e.Reason(),
 		Message:   e.msg,
+		Fields:    e.fields,
 		Cause:     cause,
+		RequestID: e.requestID,
 		Timestamp: e.timestamp,
 	}
 
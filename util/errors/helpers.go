@@ -1,23 +1,181 @@
 package errors
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgconn"
 	"gorm.io/gorm"
 )
 
-func FromGORM(tx *gorm.DB, msg string) *Error {
-	var code errorCode
+// Error codes of Postgres, see https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgForeignKeyViolation  = "23503"
+	pgUniqueViolation      = "23505"
+	pgCheckViolation       = "23514"
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+	pgQueryCanceled        = "57014"
+)
 
+func FromGORM(tx *gorm.DB, msg string) *Error {
 	if (tx.Error == nil && tx.RowsAffected == 0) || errors.Is(tx.Error, gorm.ErrRecordNotFound) {
-		code = NotFound
-	} else {
-		code = Internal
+		return New(NotFound, tx.Error, msg)
 	}
 
-	return New(code, tx.Error, msg)
+	table := ""
+	if tx.Statement != nil {
+		table = tx.Statement.Table
+	}
+
+	return fromDB(tx.Error, table, msg)
+}
+
+// FromDB classifies an error of the database. Constraint violations are errors of
+// clients and name the violated constraint, failed transactions are conflicts and
+// queries which ran out of time or were canceled with their request are timeouts.
+// Foreign key violations are taken for missing references, FromGORM also tells
+// apart rows which are still referenced by the table of the statement.
+func FromDB(err error, msg string) *Error {
+	return fromDB(err, "", msg)
 }
 
+// fromDB is FromDB of a statement on the table, which may be unknown
+func fromDB(err error, table string, msg string) *Error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return New(Timeout, err, msg+": query timed out")
+	}
+	if errors.Is(err, context.Canceled) {
+		return New(Timeout, err, msg+": request was canceled")
+	}
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return New(Internal, err, msg)
+	}
+
+	switch pgErr.Code {
+	case pgQueryCanceled:
+		return New(Timeout, err, msg+": query timed out")
+	case pgUniqueViolation:
+		return Newf(Conflict, err, "%s: %s already exists", msg, constraintSubject(pgErr)).WithReason(ReasonAlreadyExists)
+	case pgForeignKeyViolation:
+		// The table of the violation is always the referencing one, so statements on
+		// other tables deleted or updated rows which are still referenced. Details are
+		// translated with lc_messages and can't tell it.
+		if table != "" && pgErr.TableName != "" && table != pgErr.TableName {
+			return Newf(Conflict, err, "%s: %s is still referenced by %s", msg, constraintSubject(pgErr), pgErr.TableName).
+				WithReason(ReasonItemInUse)
+		}
+		return Newf(BadInput, err, "%s: %s refers to a missing item", msg, constraintSubject(pgErr)).WithReason(ReasonReferenceNotFound)
+	case pgCheckViolation:
+		return Newf(BadInput, err, "%s: violates check %s", msg, pgErr.ConstraintName).WithReason(ReasonConstraintViolated)
+	case pgSerializationFailure, pgDeadlockDetected:
+		return New(Conflict, err, msg+": conflicted with a concurrent change, try again").WithReason(ReasonConcurrentChange)
+	}
+
+	return New(Internal, err, msg)
+}
+
+// Retryable tells if the transaction which failed with err may succeed if it's run again
+func Retryable(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && (pgErr.Code == pgSerializationFailure || pgErr.Code == pgDeadlockDetected)
+}
+
+// UniqueViolation returns the constraint violated by err if it's a unique violation
+func UniqueViolation(err error) (constraint string, ok bool) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+		return pgErr.ConstraintName, true
+	}
+
+	return "", false
+}
+
+// constraintSubject is what the violated constraint is about: its key from the
+// detail, e.g. "(name)=(RPG)", or its name if the detail is missing
+func constraintSubject(pgErr *pgconn.PgError) string {
+	if start := strings.Index(pgErr.Detail, "("); strings.HasPrefix(pgErr.Detail, "Key ") && start >= 0 {
+		if end := strings.LastIndex(pgErr.Detail, ")"); end > start {
+			return pgErr.Detail[start : end+1]
+		}
+	}
+	if pgErr.ConstraintName != "" {
+		return pgErr.ConstraintName
+	}
+
+	return "item"
+}
+
+// JSONParseErr describes failed binding of a request body. Failed validation
+// rules and wrong types of fields are reported as field errors.
 func JSONParseErr(err error) *Error {
-	return Newf(BadInput, err, "failed to parse request to json: %v", err)
+	if fieldErr := bindingErr(err); fieldErr != nil {
+		return fieldErr
+	}
+
+	return Newf(BadInput, err, "failed to parse request to json: %v", err).WithReason(ReasonMalformedRequest)
+}
+
+// QueryParseErr describes failed binding of a request query like JSONParseErr
+func QueryParseErr(err error) *Error {
+	if fieldErr := bindingErr(err); fieldErr != nil {
+		return fieldErr
+	}
+
+	return New(BadInput, err, "failed to parse query").WithReason(ReasonMalformedRequest)
+}
+
+// bindingErr is the error with field errors of err or nil if err isn't about fields
+func bindingErr(err error) *Error {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]FieldError, len(validationErrs))
+		for i, fieldErr := range validationErrs {
+			fields[i] = FieldError{
+				Field:   fieldErr.Field(),
+				Rule:    fieldErr.Tag(),
+				Param:   fieldErr.Param(),
+				Message: ruleMessage(fieldErr.Field(), fieldErr.Tag(), fieldErr.Param()),
+			}
+		}
+
+		return New(BadInput, err, "request validation failed").WithReason(ReasonValidationFailed).WithFields(fields)
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		field := FieldError{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Param:   typeErr.Type.String(),
+			Message: fmt.Sprintf("%s must be of type %s", typeErr.Field, typeErr.Type),
+		}
+
+		return New(BadInput, err, "request validation failed").WithReason(ReasonValidationFailed).WithFields([]FieldError{field})
+	}
+
+	return nil
+}
+
+func ruleMessage(field string, rule string, param string) string {
+	switch rule {
+	case "required":
+		return field + " is required"
+	case "gte", "min":
+		return fmt.Sprintf("%s must be at least %s", field, param)
+	case "lte", "max":
+		return fmt.Sprintf("%s must be at most %s", field, param)
+	case "url", "uri", "email":
+		return fmt.Sprintf("%s must be a valid %s", field, rule)
+	case "oneof":
+		return fmt.Sprintf("%s must be one of %s", field, param)
+	}
+
+	return fmt.Sprintf("%s failed on the %s rule", field, rule)
 }
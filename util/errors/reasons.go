@@ -0,0 +1,48 @@
+package errors
+
+// Reason is a stable machine-readable cause of an error. Clients may rely on
+// reasons, so they're never renamed. Errors without a reason of their own get
+// the generic reason of their code.
+type Reason string
+
+const (
+	// Requests
+	ReasonMalformedRequest Reason = "MALFORMED_REQUEST"
+	ReasonValidationFailed Reason = "VALIDATION_FAILED"
+	ReasonInvalidCursor    Reason = "INVALID_CURSOR"
+	ReasonInvalidPageLimit Reason = "INVALID_PAGE_LIMIT"
+	ReasonInvalidListFile  Reason = "INVALID_LIST_FILE"
+	ReasonQueryTooComplex  Reason = "QUERY_TOO_COMPLEX"
+
+	// Authentication
+	ReasonTokenMissing       Reason = "TOKEN_MISSING"
+	ReasonTokenInvalid       Reason = "TOKEN_INVALID"
+	ReasonInvalidCredentials Reason = "INVALID_CREDENTIALS"
+
+	// Profiles
+	ReasonProfileNotFound      Reason = "PROFILE_NOT_FOUND"
+	ReasonNicknameTaken        Reason = "NICKNAME_TAKEN"
+	ReasonEmailTaken           Reason = "EMAIL_TAKEN"
+	ReasonDeletionNotRequested Reason = "DELETION_NOT_REQUESTED"
+
+	// Games and catalog
+	ReasonGameNotFound     Reason = "GAME_NOT_FOUND"
+	ReasonGameMerged       Reason = "GAME_MERGED"
+	ReasonListTypeNotFound Reason = "LIST_TYPE_NOT_FOUND"
+	ReasonItemNotFound     Reason = "ITEM_NOT_FOUND"
+	ReasonItemInUse        Reason = "ITEM_IN_USE"
+	ReasonAlreadyExists    Reason = "ALREADY_EXISTS"
+
+	// Database
+	ReasonReferenceNotFound  Reason = "REFERENCE_NOT_FOUND"
+	ReasonConstraintViolated Reason = "CONSTRAINT_VIOLATED"
+	ReasonConcurrentChange   Reason = "CONCURRENT_CHANGE"
+
+	// Scraping
+	ReasonScrapeJobRunning Reason = "SCRAPE_JOB_RUNNING"
+)
+
+// Reason is the generic reason of errors of the code
+func (c errorCode) Reason() Reason {
+	return Reason(c.String())
+}
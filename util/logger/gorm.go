@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+)
+
+// NewGORMLogger logs queries of GORM with the SubsystemGORM logger. Failed queries are
+// errors, slow ones are warnings and the rest are debug lines. LogLevel of conf limits
+// what GORM sends on top of the level of the subsystem.
+func NewGORMLogger(conf gormLogger.Config) gormLogger.Interface {
+	return &gormAdapter{
+		log:  For(SubsystemGORM),
+		conf: conf,
+	}
+}
+
+type gormAdapter struct {
+	log  *slog.Logger
+	conf gormLogger.Config
+}
+
+func (l *gormAdapter) LogMode(level gormLogger.LogLevel) gormLogger.Interface {
+	c := *l
+	c.conf.LogLevel = level
+	return &c
+}
+
+func (l *gormAdapter) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.conf.LogLevel >= gormLogger.Info {
+		l.log.InfoContext(ctx, fmt.Sprintf(msg, data...))
+	}
+}
+
+func (l *gormAdapter) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.conf.LogLevel >= gormLogger.Warn {
+		l.log.WarnContext(ctx, fmt.Sprintf(msg, data...))
+	}
+}
+
+func (l *gormAdapter) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.conf.LogLevel >= gormLogger.Error {
+		l.log.ErrorContext(ctx, fmt.Sprintf(msg, data...))
+	}
+}
+
+func (l *gormAdapter) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.conf.LogLevel <= gormLogger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	var (
+		level slog.Level
+		msg   string
+	)
+	switch {
+	case err != nil && l.conf.LogLevel >= gormLogger.Error &&
+		!(l.conf.IgnoreRecordNotFoundError && errors.Is(err, gorm.ErrRecordNotFound)):
+		level, msg = slog.LevelError, "query failed"
+	case l.conf.SlowThreshold > 0 && elapsed > l.conf.SlowThreshold && l.conf.LogLevel >= gormLogger.Warn:
+		level, msg = slog.LevelWarn, "slow query"
+	case l.conf.LogLevel >= gormLogger.Info:
+		level, msg = slog.LevelDebug, "query"
+	default:
+		return
+	}
+	if !l.log.Enabled(ctx, level) {
+		return
+	}
+
+	sql, rows := fc()
+	attrs := []slog.Attr{
+		slog.String("sql", sql),
+		slog.Int64("rows", rows),
+		slog.Duration("elapsed", elapsed),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+	l.log.LogAttrs(ctx, level, msg, attrs...)
+}
@@ -1,15 +1,172 @@
 package logger
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
-	"time"
+	"strings"
+	"sync/atomic"
 )
 
-type logger struct{}
+const (
+	FORMAT_TEXT = "text"
+	FORMAT_JSON = "json"
+)
+
+// Subsystems have levels of their own, see Config.Levels
+const (
+	SubsystemServer   = "server"
+	SubsystemHTTP     = "http"
+	SubsystemGORM     = "gorm"
+	SubsystemGraphQL  = "graphql"
+	SubsystemAdmin    = "admin"
+	SubsystemEvents   = "events"
+	SubsystemIngest   = "ingest"
+	SubsystemScrape   = "scrape"
+	SubsystemAccounts = "accounts"
+	SubsystemHealth   = "health"
+)
+
+type Config struct {
+	// Format is FORMAT_TEXT or FORMAT_JSON
+	Format string
+	Level  slog.Level
+	// Levels of subsystems override Level
+	Levels map[string]slog.Level
+	// Output is os.Stdout if it's nil
+	Output io.Writer
+}
+
+type state struct {
+	handler slog.Handler
+	level   slog.Level
+	levels  map[string]slog.Level
+}
+
+func (s *state) levelOf(subsystem string) slog.Level {
+	if level, ok := s.levels[subsystem]; ok {
+		return level
+	}
+
+	return s.level
+}
+
+var current atomic.Pointer[state]
+
+func init() {
+	Setup(Config{})
+}
+
+// Setup replaces the output, the format and the levels of all loggers. The standard
+// log and slog packages write through the logger of SubsystemServer afterwards.
+func Setup(conf Config) {
+	out := conf.Output
+	if out == nil {
+		out = os.Stdout
+	}
+
+	// Levels are checked by subsystems, so the handler passes everything
+	opts := &slog.HandlerOptions{Level: slog.LevelDebug}
+	var handler slog.Handler
+	if conf.Format == FORMAT_JSON {
+		handler = slog.NewJSONHandler(out, opts)
+	} else {
+		handler = slog.NewTextHandler(out, opts)
+	}
+
+	current.Store(&state{handler: handler, level: conf.Level, levels: conf.Levels})
+	slog.SetDefault(For(SubsystemServer))
+}
+
+// For returns the logger of the subsystem. Loggers follow Setup, so they may be
+// created before it's called.
+func For(subsystem string) *slog.Logger {
+	return slog.New(&handler{subsystem: subsystem})
+}
+
+// ParseLevel parses levels like "debug", "info", "warn" or "error"
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	err := level.UnmarshalText([]byte(strings.TrimSpace(s)))
+	return level, err
+}
 
-var Logger = new(logger)
+// ParseLevels parses levels of subsystems like "gorm=warn,http=info"
+func ParseLevels(s string) (map[string]slog.Level, error) {
+	levels := map[string]slog.Level{}
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		subsystem, levelStr, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("no level of subsystem %q", strings.TrimSpace(pair))
+		}
+		level, err := ParseLevel(levelStr)
+		if err != nil {
+			return nil, fmt.Errorf("wrong level of subsystem %q: %w", strings.TrimSpace(subsystem), err)
+		}
+		levels[strings.TrimSpace(subsystem)] = level
+	}
+
+	return levels, nil
+}
+
+type contextKey struct{}
+
+// WithAttrs returns a copy of ctx whose log lines have the attributes, e.g. the id
+// of the request that's being served
+func WithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	parent := attrsOf(ctx)
+	merged := make([]slog.Attr, 0, len(parent)+len(attrs))
+	merged = append(merged, parent...)
+	merged = append(merged, attrs...)
+
+	return context.WithValue(ctx, contextKey{}, merged)
+}
+
+func attrsOf(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	attrs, _ := ctx.Value(contextKey{}).([]slog.Attr)
+	return attrs
+}
+
+// handler writes through the current handler of Setup with the level of its subsystem
+type handler struct {
+	subsystem string
+	// ops are WithAttrs and WithGroup calls, which are replayed on the current handler
+	ops []func(slog.Handler) slog.Handler
+}
+
+func (h *handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= current.Load().levelOf(h.subsystem)
+}
+
+func (h *handler) Handle(ctx context.Context, record slog.Record) error {
+	attrs := append([]slog.Attr{slog.String("subsystem", h.subsystem)}, attrsOf(ctx)...)
+	out := current.Load().handler.WithAttrs(attrs)
+	for _, op := range h.ops {
+		out = op(out)
+	}
+
+	return out.Handle(ctx, record)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.with(func(out slog.Handler) slog.Handler { return out.WithAttrs(attrs) })
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	return h.with(func(out slog.Handler) slog.Handler { return out.WithGroup(name) })
+}
 
-func (l *logger) Write(p []byte) (n int, err error) {
-	return fmt.Fprintf(os.Stdout, "(%d) %s", time.Now().Unix(), p)
+func (h *handler) with(op func(slog.Handler) slog.Handler) slog.Handler {
+	ops := make([]func(slog.Handler) slog.Handler, 0, len(h.ops)+1)
+	ops = append(ops, h.ops...)
+	return &handler{subsystem: h.subsystem, ops: append(ops, op)}
 }
@@ -0,0 +1,130 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const NAMESPACE = "gamelist"
+
+// Registry has all metrics of the server. It's separate from the default registry
+// of prometheus, so only metrics of the server are exposed.
+var Registry = prometheus.NewRegistry()
+
+// Buckets of durations in seconds
+var (
+	requestBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+	queryBuckets   = []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1}
+	scrapeBuckets  = prometheus.ExponentialBuckets(10, 2, 10)
+)
+
+// HTTP
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Served HTTP requests by route and status.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of HTTP requests by route and status.",
+		Buckets:   requestBuckets,
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestsInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "http",
+		Name:      "requests_in_flight",
+		Help:      "HTTP requests being served, streams of events included.",
+	})
+)
+
+// Database
+var (
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Duration of GORM queries by operation and table.",
+		Buckets:   queryBuckets,
+	}, []string{"operation", "table"})
+
+	DBQueryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "db",
+		Name:      "query_errors_total",
+		Help:      "Failed GORM queries by operation and table. Records which aren't found aren't counted.",
+	}, []string{"operation", "table"})
+)
+
+// Authentication
+var (
+	TokensIssued = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "auth",
+		Name:      "tokens_issued_total",
+		Help:      "Issued token pairs by grant: login or refresh.",
+	}, []string{"grant"})
+
+	TokenRefreshes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "auth",
+		Name:      "token_refreshes_total",
+		Help:      "Refreshes of token pairs by result: success or failure.",
+	}, []string{"result"})
+
+	AuthFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "auth",
+		Name:      "failures_total",
+		Help:      "Failed logins, refreshes and authorizations by error reason.",
+	}, []string{"reason"})
+)
+
+// Scraping
+var (
+	ScrapeJobs = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "scrape",
+		Name:      "jobs_total",
+		Help:      "Finished scrape jobs by trigger and status.",
+	}, []string{"trigger", "status"})
+
+	ScrapeJobDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "scrape",
+		Name:      "job_duration_seconds",
+		Help:      "Duration of finished scrape jobs by status.",
+		Buckets:   scrapeBuckets,
+	}, []string{"status"})
+
+	ScrapeJobRunning = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "scrape",
+		Name:      "job_running",
+		Help:      "1 while a scrape job is running.",
+	})
+
+	IngestedGames = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "scrape",
+		Name:      "ingested_games_total",
+		Help:      "Games received from sources by source and result: inserted, updated, skipped or failed.",
+	}, []string{"source", "result"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{Namespace: NAMESPACE}),
+
+		HTTPRequests, HTTPRequestDuration, HTTPRequestsInFlight,
+		DBQueryDuration, DBQueryErrors,
+		TokensIssued, TokenRefreshes, AuthFailures,
+		ScrapeJobs, ScrapeJobDuration, ScrapeJobRunning, IngestedGames,
+	)
+}
@@ -0,0 +1,363 @@
+// Package openapi builds OpenAPI 3 documents with schemas reflected from Go types.
+// Schemas follow encoding/json field names and gin's binding tags.
+package openapi
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const Version = "3.0.3"
+
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Servers    []Server            `json:"servers,omitempty"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type Server struct {
+	URL string `json:"url"`
+}
+
+// PathItem maps lowercase HTTP methods to operations
+type PathItem map[string]*Operation
+
+type Operation struct {
+	Summary     string                `json:"summary"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+	Deprecated  bool                  `json:"deprecated,omitempty"`
+}
+
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas,omitempty"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+}
+
+var (
+	timeType  = reflect.TypeOf(time.Time{})
+	pathParam = regexp.MustCompile(`[:*]([^/]+)`)
+)
+
+// Generator collects operations and schemas of named types into a document
+type Generator struct {
+	doc *Document
+}
+
+func NewGenerator(info Info) *Generator {
+	return &Generator{
+		doc: &Document{
+			OpenAPI: Version,
+			Info:    info,
+			Paths:   map[string]PathItem{},
+			Components: Components{
+				Schemas: map[string]*Schema{},
+			},
+		},
+	}
+}
+
+func (g *Generator) Document() *Document {
+	return g.doc
+}
+
+// AddSecurityScheme adds a scheme operations may refer to by name
+func (g *Generator) AddSecurityScheme(name string, scheme SecurityScheme) {
+	if g.doc.Components.SecuritySchemes == nil {
+		g.doc.Components.SecuritySchemes = map[string]SecurityScheme{}
+	}
+	g.doc.Components.SecuritySchemes[name] = scheme
+}
+
+// AddOperation adds the operation of a gin route. Path parameters like :id
+// become {id} and are added to the operation's parameters.
+func (g *Generator) AddOperation(method string, path string, op *Operation) {
+	for _, match := range pathParam.FindAllStringSubmatch(path, -1) {
+		op.Parameters = append(op.Parameters, Parameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+	path = pathParam.ReplaceAllString(path, "{$1}")
+
+	item, ok := g.doc.Paths[path]
+	if !ok {
+		item = PathItem{}
+		g.doc.Paths[path] = item
+	}
+	item[strings.ToLower(method)] = op
+}
+
+// Schema reflects the type of v. Named structs are added to components and referenced.
+func (g *Generator) Schema(v interface{}) *Schema {
+	return g.schema(reflect.TypeOf(v))
+}
+
+// QueryParameters reflects fields of the struct v with form tags
+func (g *Generator) QueryParameters(v interface{}) []Parameter {
+	var params []Parameter
+	for _, field := range fields(reflect.TypeOf(v), "form") {
+		params = append(params, Parameter{
+			Name:     field.name,
+			In:       "query",
+			Required: field.required,
+			Schema:   field.schema(g),
+		})
+	}
+
+	return params
+}
+
+func (g *Generator) schema(t reflect.Type) *Schema {
+	if t == nil {
+		return &Schema{}
+	}
+
+	if t.Kind() == reflect.Ptr {
+		schema := g.schema(t.Elem())
+		if schema.Ref == "" {
+			schema.Nullable = true
+		}
+		return schema
+	}
+
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: "integer", Format: intFormat(t)}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		min := 0.0
+		return &Schema{Type: "integer", Format: intFormat(t), Minimum: &min}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+
+		name := schemaName(t)
+		if _, ok := g.doc.Components.Schemas[name]; !ok {
+			// Registered before reflecting fields, so recursive types end up with a reference
+			g.doc.Components.Schemas[name] = &Schema{}
+			*g.doc.Components.Schemas[name] = *g.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+
+	// Interfaces and anything else may be any value
+	return &Schema{}
+}
+
+func (g *Generator) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for _, field := range fields(t, "json") {
+		schema.Properties[field.name] = field.schema(g)
+		if field.required {
+			schema.Required = append(schema.Required, field.name)
+		}
+	}
+	sort.Strings(schema.Required)
+
+	return schema
+}
+
+type field struct {
+	name     string
+	typ      reflect.Type
+	required bool
+	rules    []string
+}
+
+// fields lists fields of the struct as encoding/json does: embedded
+// structs without a name are flattened and "-" fields are skipped
+func fields(t reflect.Type, tagKey string) []field {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	var result []field
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get(tagKey)
+		name := strings.Split(tag, ",")[0]
+		if name == "-" {
+			continue
+		}
+
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			result = append(result, fields(ft, tagKey)...)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			if tagKey == "form" {
+				continue
+			}
+			name = f.Name
+		}
+
+		rules := strings.Split(f.Tag.Get("binding"), ",")
+		result = append(result, field{
+			name:     name,
+			typ:      f.Type,
+			required: contains(rules, "required"),
+			rules:    rules,
+		})
+	}
+
+	return result
+}
+
+// schema applies binding rules to the schema of the field
+func (f field) schema(g *Generator) *Schema {
+	schema := g.schema(f.typ)
+	if schema.Ref != "" {
+		return schema
+	}
+
+	for _, rule := range f.rules {
+		kv := strings.SplitN(rule, "=", 2)
+		switch kv[0] {
+		case "url":
+			schema.Format = "uri"
+		case "email":
+			schema.Format = "email"
+		case "gte", "min", "lte", "max":
+			if len(kv) != 2 {
+				continue
+			}
+			n, err := strconv.ParseFloat(kv[1], 64)
+			if err != nil {
+				continue
+			}
+			lower := kv[0] == "gte" || kv[0] == "min"
+			switch {
+			case schema.Type == "string" && lower:
+				length := int(n)
+				schema.MinLength = &length
+			case schema.Type == "string":
+				length := int(n)
+				schema.MaxLength = &length
+			case lower:
+				schema.Minimum = &n
+			default:
+				schema.Maximum = &n
+			}
+		}
+	}
+
+	return schema
+}
+
+// schemaName names instances of generic types after their arguments, e.g. PageGenre
+func schemaName(t reflect.Type) string {
+	name := t.Name()
+	i := strings.Index(name, "[")
+	if i < 0 {
+		return name
+	}
+
+	base := name[:i]
+	for _, arg := range strings.Split(strings.TrimSuffix(name[i+1:], "]"), ",") {
+		base += arg[strings.LastIndex(arg, ".")+1:]
+	}
+
+	return base
+}
+
+func intFormat(t reflect.Type) string {
+	if t.Bits() == 64 {
+		return "int64"
+	}
+	return "int32"
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
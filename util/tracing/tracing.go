@@ -0,0 +1,93 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	EXPORTER_NONE   = ""
+	EXPORTER_STDOUT = "stdout"
+	EXPORTER_OTLP   = "otlp"
+
+	SERVICE_NAME = "gamelist-backend"
+
+	instrumentationName = "github.com/br3w0r/gamelist-backend"
+)
+
+type Config struct {
+	// Exporter is EXPORTER_NONE, EXPORTER_STDOUT or EXPORTER_OTLP. The OTLP exporter
+	// is configured with the standard OTEL_EXPORTER_OTLP_* environment variables.
+	Exporter string
+	// SampleRatio of traces which start in the server, 1 samples all of them.
+	// Traces of clients are sampled as clients decided.
+	SampleRatio float64
+}
+
+// Setup sets the global tracer provider and the W3C trace context propagator. The
+// returned shutdown flushes spans which weren't exported yet.
+func Setup(ctx context.Context, conf Config) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	switch conf.Exporter {
+	case EXPORTER_NONE:
+		return func(context.Context) error { return nil }, nil
+	case EXPORTER_STDOUT:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, err
+		}
+	case EXPORTER_OTLP:
+		exporter, err = otlptracehttp.New(ctx)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown exporter %q", conf.Exporter)
+	}
+
+	// Variables like OTEL_SERVICE_NAME override the defaults
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceNameKey.String(SERVICE_NAME)),
+		resource.WithTelemetrySDK(),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(conf.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Tracer of the package, e.g. "service" or "repository"
+func Tracer(pkg string) trace.Tracer {
+	return otel.Tracer(instrumentationName + "/" + pkg)
+}
+
+// End records the error of the span if there's one and ends the span
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}